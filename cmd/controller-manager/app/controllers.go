@@ -2,13 +2,27 @@ package app
 
 import (
 	"github.com/sunweiwe/horizon/cmd/controller-manager/app/options"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/token"
+	"github.com/sunweiwe/horizon/pkg/constants"
 	"github.com/sunweiwe/horizon/pkg/controller/cluster"
+	"github.com/sunweiwe/horizon/pkg/controller/group"
+	"github.com/sunweiwe/horizon/pkg/controller/groupbinding"
+	"github.com/sunweiwe/horizon/pkg/controller/kubeconfig"
+	"github.com/sunweiwe/horizon/pkg/controller/loginrecord"
 	"github.com/sunweiwe/horizon/pkg/controller/namespace"
+	"github.com/sunweiwe/horizon/pkg/controller/roletemplate"
+	"github.com/sunweiwe/horizon/pkg/controller/user"
+	"github.com/sunweiwe/horizon/pkg/controller/workspace"
+	"github.com/sunweiwe/horizon/pkg/controller/workspaceresourcequota"
+	"github.com/sunweiwe/horizon/pkg/controller/workspacetemplate"
 	"github.com/sunweiwe/horizon/pkg/informers"
 	"github.com/sunweiwe/horizon/pkg/simple/client/k8s"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 )
@@ -16,6 +30,15 @@ import (
 var allControllers = []string{
 	"cluster",
 	"namespace",
+	"user",
+	"loginrecord",
+	"group",
+	"groupbinding",
+	"roletemplate",
+	"kubeconfig",
+	"workspace",
+	"workspacetemplate",
+	"workspaceresourcequota",
 }
 
 var addSuccessfullyControllers = sets.New[string]()
@@ -25,6 +48,8 @@ func addAllControllers(mgr manager.Manager, client k8s.Client, informerFactory i
 	stopCh <-chan struct{}) error {
 
 	horizonInformer := informerFactory.HorizonSharedInformerFactory()
+	// the clients of the member clusters are shared by the controllers
+	clusterClients := cluster.NewClusterClients()
 	if cmOptions.GetControllerEnabled("cluster") {
 		if cmOptions.MultiClusterOptions.Enable {
 			clusterController := cluster.NewClusterController(
@@ -32,6 +57,7 @@ func addAllControllers(mgr manager.Manager, client k8s.Client, informerFactory i
 				client.Horizon(),
 				client.Config(),
 				horizonInformer.Cluster().V1alpha1().Clusters(),
+				clusterClients,
 				cmOptions.MultiClusterOptions.ClusterControllerResyncPeriod,
 				cmOptions.MultiClusterOptions.HostClusterName,
 			)
@@ -44,6 +70,87 @@ func addAllControllers(mgr manager.Manager, client k8s.Client, informerFactory i
 		addControllerWithSetup(mgr, "namespace", namespaceReconciler)
 	}
 
+	if cmOptions.GetControllerEnabled("user") {
+		userReconciler := &user.Reconciler{
+			AuthenticationOptions: cmOptions.AuthenticationOptions,
+		}
+		addControllerWithSetup(mgr, "user", userReconciler)
+	}
+
+	if cmOptions.GetControllerEnabled("loginrecord") {
+		loginRecordReconciler := &loginrecord.Reconciler{
+			AuthenticationOptions: cmOptions.AuthenticationOptions,
+		}
+		addControllerWithSetup(mgr, "loginrecord", loginRecordReconciler)
+	}
+
+	if cmOptions.GetControllerEnabled("group") {
+		groupReconciler := &group.Reconciler{}
+		addControllerWithSetup(mgr, "group", groupReconciler)
+	}
+
+	if cmOptions.GetControllerEnabled("groupbinding") {
+		groupBindingReconciler := &groupbinding.Reconciler{}
+		addControllerWithSetup(mgr, "groupbinding", groupBindingReconciler)
+	}
+
+	if cmOptions.GetControllerEnabled("roletemplate") {
+		addControllerWithSetup(mgr, "roletemplate", &roletemplate.GlobalRoleReconciler{})
+		addControllerWithSetup(mgr, "roletemplate", &roletemplate.WorkspaceRoleReconciler{})
+		addControllerWithSetup(mgr, "roletemplate", &roletemplate.RoleReconciler{})
+	}
+
+	if cmOptions.GetControllerEnabled("kubeconfig") {
+		// the tokens in the kubeconfig can only be verified by hz-apiserver if the token store is shared
+		if cmOptions.AuthenticationOptions.TokenStore == authentication.TokenStoreSecret {
+			kubeconfigReconciler := &kubeconfig.Reconciler{
+				AuthenticationOptions: cmOptions.AuthenticationOptions,
+				TokenStore: token.NewSecretStore(
+					client.Kubernetes(),
+					informerFactory.KubernetesSharedInformerFactory().Core().V1().Secrets().Lister(),
+					constants.HorizonNamespace),
+				Server: cmOptions.KubeconfigServer,
+			}
+			addControllerWithSetup(mgr, "kubeconfig", kubeconfigReconciler)
+		} else {
+			klog.Errorf("Unable to start kubeconfig controller: token store %q is not shared with hz-apiserver, "+
+				"the kubeconfig of the users is only generated with token store %q",
+				cmOptions.AuthenticationOptions.TokenStore, authentication.TokenStoreSecret)
+		}
+	}
+
+	if cmOptions.GetControllerEnabled("workspace") {
+		workspaceReconciler := &workspace.Reconciler{
+			CascadeDeleteNamespaces: cmOptions.CascadeDeleteNamespaces,
+		}
+		addControllerWithSetup(mgr, "workspace", workspaceReconciler)
+	}
+
+	if cmOptions.GetControllerEnabled("workspacetemplate") {
+		if cmOptions.MultiClusterOptions.Enable {
+			workspaceTemplateReconciler := &workspacetemplate.Reconciler{
+				ClusterClients: clusterClients,
+			}
+			addControllerWithSetup(mgr, "workspacetemplate", workspaceTemplateReconciler)
+		}
+	}
+
+	if cmOptions.GetControllerEnabled("workspaceresourcequota") {
+		workspaceResourceQuotaReconciler := &workspaceresourcequota.Reconciler{}
+		addControllerWithSetup(mgr, "workspaceresourcequota", workspaceResourceQuotaReconciler)
+
+		// the webhook server can only be started with the certificates
+		if cmOptions.WebhookCertDir != "" {
+			mgr.GetWebhookServer().Register(workspaceresourcequota.WebhookPath, &webhook.Admission{
+				Handler: &workspaceresourcequota.Validator{
+					Client:    mgr.GetClient(),
+					APIReader: mgr.GetAPIReader(),
+					Decoder:   admission.NewDecoder(mgr.GetScheme()),
+				},
+			})
+		}
+	}
+
 	// log all controllers process result
 	for _, name := range allControllers {
 		if cmOptions.GetControllerEnabled(name) {
@@ -4,7 +4,9 @@ import (
 	"time"
 
 	"github.com/spf13/pflag"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication"
 	controllerconfig "github.com/sunweiwe/horizon/pkg/apiserver/config"
+	"github.com/sunweiwe/horizon/pkg/models/kubeconfig"
 	"github.com/sunweiwe/horizon/pkg/simple/client/k8s"
 	"github.com/sunweiwe/horizon/pkg/simple/client/monitoring/prometheus"
 	"github.com/sunweiwe/horizon/pkg/simple/client/multicluster"
@@ -13,7 +15,8 @@ import (
 )
 
 type HorizonControllerManagerOptions struct {
-	KubernetesOptions *k8s.KubernetesOptions
+	KubernetesOptions     *k8s.KubernetesOptions
+	AuthenticationOptions *authentication.Options
 
 	MonitoringOptions   *prometheus.Options
 	MultiClusterOptions *multicluster.Options
@@ -23,6 +26,12 @@ type HorizonControllerManagerOptions struct {
 
 	WebhookCertDir string
 
+	// KubeconfigServer is the address of hz-apiserver written to the kubeconfig of the users
+	KubeconfigServer string
+
+	// CascadeDeleteNamespaces deletes the namespaces of a workspace along with the workspace
+	CascadeDeleteNamespaces bool
+
 	ApplicationSelector string
 
 	ControllerGates []string
@@ -30,16 +39,19 @@ type HorizonControllerManagerOptions struct {
 
 func NewHorizonControllerManagerOptions() *HorizonControllerManagerOptions {
 	s := &HorizonControllerManagerOptions{
-		KubernetesOptions:   k8s.NewKubernetesClientOptions(),
-		MultiClusterOptions: multicluster.NewOptions(),
-		LeaderElect:         false,
+		KubernetesOptions:     k8s.NewKubernetesClientOptions(),
+		AuthenticationOptions: authentication.NewOptions(),
+		MultiClusterOptions:   multicluster.NewOptions(),
+		LeaderElect:           false,
 		LeaderElection: &leaderelection.LeaderElectionConfig{
 			LeaseDuration: 30 * time.Second,
 			RenewDeadline: 15 * time.Second,
 			RetryPeriod:   5 * time.Second,
 		},
-		WebhookCertDir:  "",
-		ControllerGates: []string{"*"},
+		WebhookCertDir:          "",
+		KubeconfigServer:        kubeconfig.DefaultServer,
+		CascadeDeleteNamespaces: true,
+		ControllerGates:         []string{"*"},
 	}
 
 	return s
@@ -47,6 +59,7 @@ func NewHorizonControllerManagerOptions() *HorizonControllerManagerOptions {
 
 func (s *HorizonControllerManagerOptions) MergeConfig(cfg *controllerconfig.Config) {
 	s.KubernetesOptions = cfg.KubernetesOptions
+	s.AuthenticationOptions = cfg.AuthenticationOptions
 }
 
 func (s *HorizonControllerManagerOptions) GetControllerEnabled(name string) bool {
@@ -71,10 +84,26 @@ func (s *HorizonControllerManagerOptions) Flags(allControllerNameSelectors []str
 	fss := flag.NamedFlagSets{}
 
 	s.KubernetesOptions.AddFlags(fss.FlagSet("kubernetes"), s.KubernetesOptions)
+	s.AuthenticationOptions.AddFlags(fss.FlagSet("authentication"), s.AuthenticationOptions)
 
 	fs := fss.FlagSet("leaderelection")
 	s.bindLeaderElectionFlags(s.LeaderElection, fs)
 
+	fs = fss.FlagSet("webhook")
+	fs.StringVar(&s.WebhookCertDir, "webhook-cert-dir", s.WebhookCertDir, ""+
+		"The directory of the certificate tls.crt and the key tls.key the admission webhooks are served with, "+
+		"the webhooks are disabled if it is empty.")
+
+	fs = fss.FlagSet("kubeconfig")
+	fs.StringVar(&s.KubeconfigServer, "kubeconfig-server", s.KubeconfigServer, ""+
+		"The address of hz-apiserver written to the kubeconfig of the users, it should be served over https "+
+		"if the kubeconfig is used outside the cluster network, the tokens are sent in clear text otherwise.")
+
+	fs = fss.FlagSet("workspace")
+	fs.BoolVar(&s.CascadeDeleteNamespaces, "cascade-delete-namespaces", s.CascadeDeleteNamespaces, ""+
+		"Delete the namespaces of a workspace when the workspace is deleted, "+
+		"the namespaces are orphaned and keep the workspace label otherwise.")
+
 	return fss
 }
 
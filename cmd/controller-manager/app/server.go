@@ -27,13 +27,16 @@ func NewControllerManagerCommand() *cobra.Command {
 
 	if err == nil {
 		s = &options.HorizonControllerManagerOptions{
-			KubernetesOptions:   conf.KubernetesOptions,
-			LeaderElect:         s.LeaderElect,
-			LeaderElection:      s.LeaderElection,
-			WebhookCertDir:      s.WebhookCertDir,
-			MonitoringOptions:   conf.MonitoringOptions,
-			MultiClusterOptions: conf.MultiClusterOptions,
-			ControllerGates:     []string{"*"},
+			KubernetesOptions:       conf.KubernetesOptions,
+			AuthenticationOptions:   conf.AuthenticationOptions,
+			LeaderElect:             s.LeaderElect,
+			LeaderElection:          s.LeaderElection,
+			WebhookCertDir:          s.WebhookCertDir,
+			KubeconfigServer:        s.KubeconfigServer,
+			CascadeDeleteNamespaces: s.CascadeDeleteNamespaces,
+			MonitoringOptions:       conf.MonitoringOptions,
+			MultiClusterOptions:     conf.MultiClusterOptions,
+			ControllerGates:         []string{"*"},
 		}
 	} else {
 		klog.Fatalf("Failed to load configuration from disk: %v", err)
@@ -153,10 +156,10 @@ func run(s *options.HorizonControllerManagerOptions, ctx context.Context) error
 	klog.V(0).Info("Starting cache resource from apiServer...")
 	informerFactory.Start(ctx.Done())
 
-	// klog.V(0).Info("Starting the controllers.")
-	// if err = mgr.Start(ctx); err != nil {
-	// 	klog.Fatalf("unable to run the manager: %v", err)
-	// }
+	klog.V(0).Info("Starting the controllers.")
+	if err = mgr.Start(ctx); err != nil {
+		klog.Fatalf("unable to run the manager: %v", err)
+	}
 
 	return nil
 }
@@ -7,20 +7,27 @@ import (
 
 	"github.com/sunweiwe/horizon/pkg/apis"
 	"github.com/sunweiwe/horizon/pkg/apiserver"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/identityprovider"
 	"github.com/sunweiwe/horizon/pkg/informers"
 	"github.com/sunweiwe/horizon/pkg/simple/client/k8s"
 	"github.com/sunweiwe/horizon/pkg/simple/client/monitoring/metricsserver"
 	"github.com/sunweiwe/horizon/pkg/simple/client/monitoring/prometheus"
 	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/scheme"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/rest"
 	"k8s.io/component-base/cli/flag"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
 	apiserverconfig "github.com/sunweiwe/horizon/pkg/apiserver/config"
 	genericoptions "github.com/sunweiwe/horizon/pkg/server/options"
 	runtimecache "sigs.k8s.io/controller-runtime/pkg/cache"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	// register the identity providers
+	_ "github.com/sunweiwe/horizon/pkg/apiserver/authentication/identityprovider/github"
+	_ "github.com/sunweiwe/horizon/pkg/apiserver/authentication/identityprovider/ldap"
+	_ "github.com/sunweiwe/horizon/pkg/apiserver/authentication/identityprovider/oidc"
 )
 
 type ServerRunOptions struct {
@@ -63,6 +70,28 @@ func (s *ServerRunOptions) NewAPIServer(stopCh <-chan struct{}) (*apiserver.APIS
 		}
 	}
 
+	if s.AuthenticationOptions == nil || len(s.AuthenticationOptions.JwtSecret) == 0 {
+		return nil, fmt.Errorf("jwt secret in configuration MUST not be empty, please check configmap/horizon-config in horizon-system namespace")
+	}
+
+	if errs := s.AuthenticationOptions.Validate(); len(errs) != 0 {
+		return nil, utilerrors.NewAggregate(errs)
+	}
+
+	if s.AuthorizationOptions == nil {
+		return nil, fmt.Errorf("authorization options in configuration MUST not be empty, please check configmap/horizon-config in horizon-system namespace")
+	}
+
+	if errs := s.AuthorizationOptions.Validate(); len(errs) != 0 {
+		return nil, utilerrors.NewAggregate(errs)
+	}
+
+	if s.AuthenticationOptions.OAuthOptions != nil {
+		if err := identityprovider.SetupWithOptions(s.AuthenticationOptions.OAuthOptions.IdentityProviders); err != nil {
+			return nil, fmt.Errorf("failed to setup identity providers: %v", err)
+		}
+	}
+
 	apiServer.MetricsClient = metricsserver.NewMetricsClient(kubernetesClient.Kubernetes(), s.KubernetesOptions)
 
 	server := &http.Server{
@@ -103,6 +132,8 @@ func (s *ServerRunOptions) Flags() (fss flag.NamedFlagSets) {
 
 	s.KubernetesOptions.AddFlags(fss.FlagSet("kubernetes"), s.KubernetesOptions)
 	s.MonitoringOptions.AddFlags(fss.FlagSet("monitoring"), s.MonitoringOptions)
+	s.AuthenticationOptions.AddFlags(fss.FlagSet("authentication"), s.AuthenticationOptions)
+	s.AuthorizationOptions.AddFlags(fss.FlagSet("authorization"), s.AuthorizationOptions)
 
 	return fss
 }
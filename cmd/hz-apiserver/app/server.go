@@ -8,7 +8,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/sunweiwe/horizon/cmd/hz-apiserver/app/options"
 	apiserverconfig "github.com/sunweiwe/horizon/pkg/apiserver/config"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 )
 
@@ -7,7 +7,7 @@ import (
 
 	"github.com/emicklei/go-restful/v3"
 	"k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 func HandleError(response *restful.Response, req *restful.Request, err error) {
@@ -38,3 +38,11 @@ func HandleInternalError(response *restful.Response, req *restful.Request, err e
 func HandleNotFound(response *restful.Response, req *restful.Request, err error) {
 	handle(http.StatusNotFound, response, req, err)
 }
+
+func HandleBadRequest(response *restful.Response, req *restful.Request, err error) {
+	handle(http.StatusBadRequest, response, req, err)
+}
+
+func HandleForbidden(response *restful.Response, req *restful.Request, err error) {
+	handle(http.StatusForbidden, response, req, err)
+}
@@ -10,14 +10,31 @@ import (
 	"time"
 
 	"github.com/emicklei/go-restful/v3"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/authenticators/jwt"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/request/anonymous"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/token"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authorization"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authorization/authorizer"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authorization/authorizerfactory"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authorization/path"
 	"github.com/sunweiwe/horizon/pkg/apiserver/authorization/rbac"
 	"github.com/sunweiwe/horizon/pkg/apiserver/filter"
 	"github.com/sunweiwe/horizon/pkg/apiserver/request"
+	"github.com/sunweiwe/horizon/pkg/constants"
 	"github.com/sunweiwe/horizon/pkg/informers"
+	"github.com/sunweiwe/horizon/pkg/models/auth"
 	"github.com/sunweiwe/horizon/pkg/models/iam/am"
+	"github.com/sunweiwe/horizon/pkg/models/iam/group"
 	"github.com/sunweiwe/horizon/pkg/models/iam/im"
+	"github.com/sunweiwe/horizon/pkg/models/kubeconfig"
+	"github.com/sunweiwe/horizon/pkg/models/kubectl"
+	groupresource "github.com/sunweiwe/horizon/pkg/models/resources/group"
+	"github.com/sunweiwe/horizon/pkg/models/resources/groupbinding"
+	"github.com/sunweiwe/horizon/pkg/models/resources/loginrecord"
 	"github.com/sunweiwe/horizon/pkg/models/resources/user"
 	"github.com/sunweiwe/horizon/pkg/models/resources/v1beta1"
+	"github.com/sunweiwe/horizon/pkg/models/terminal"
 	"github.com/sunweiwe/horizon/pkg/server/healthz"
 	"github.com/sunweiwe/horizon/pkg/simple/client/k8s"
 	"github.com/sunweiwe/horizon/pkg/simple/client/monitoring"
@@ -26,17 +43,26 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/request/bearertoken"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 
+	clusterv1alpha1 "github.com/sunweiwe/api/cluster/v1alpha1"
+	iamapiv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	tenantv1alpha1 "github.com/sunweiwe/api/tenant/v1alpha1"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authorization/self"
+	unionauthorizer "github.com/sunweiwe/horizon/pkg/apiserver/authorization/union"
 	apiserverconfig "github.com/sunweiwe/horizon/pkg/apiserver/config"
 	clusterv1alphal "github.com/sunweiwe/horizon/pkg/hapis/cluster/v1alpha1"
 	iamv1alpha2 "github.com/sunweiwe/horizon/pkg/hapis/iam/v1alpha2"
+	"github.com/sunweiwe/horizon/pkg/hapis/oauth"
 	tenantv1alpha2 "github.com/sunweiwe/horizon/pkg/hapis/tenant/v1alpha2"
+	terminalv1alpha2 "github.com/sunweiwe/horizon/pkg/hapis/terminal/v1alpha2"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	urlruntime "k8s.io/apimachinery/pkg/util/runtime"
+	unionauth "k8s.io/apiserver/pkg/authentication/request/union"
 	runtimecache "sigs.k8s.io/controller-runtime/pkg/cache"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -63,6 +89,15 @@ type APIServer struct {
 	MetricsClient monitoring.Interface
 
 	MonitoringClient monitoring.Interface
+
+	// tokenOperator is shared by the oauth endpoints and the authentication filter
+	tokenOperator auth.TokenManagementInterface
+
+	// authorizer is shared by the authorization filter and the access review endpoints
+	authorizer authorizer.Authorizer
+
+	// requestInfoResolver is shared by the request info filter and the access review endpoints
+	requestInfoResolver *request.RequestInfoFactory
 }
 
 func (s *APIServer) PrepareRun(stopCh <-chan struct{}) error {
@@ -75,6 +110,10 @@ func (s *APIServer) PrepareRun(stopCh <-chan struct{}) error {
 		logStackFromRecover(i, w)
 	})
 
+	s.tokenOperator = auth.NewTokenOperator(s.newTokenStore(), s.Config.AuthenticationOptions)
+	s.authorizer = s.newAuthorizer()
+	s.requestInfoResolver = newRequestInfoResolver()
+
 	s.dynamicResourceAPI()
 	s.horizonAPIs(stopCh)
 	s.metricsAPI()
@@ -92,17 +131,81 @@ func (s *APIServer) PrepareRun(stopCh <-chan struct{}) error {
 }
 
 func (s *APIServer) buildHandlerChain(stopCh <-chan struct{}) {
-	requestInfoResolver := &request.RequestInfoFactory{
+	handler := s.Server.Handler
+	handler = filter.WithKubeAPIServer(handler, s.KubernetesClient.Config(), s.Config.KubernetesOptions.Impersonation, am.NewReadOnlyOperator(s.InformerFactory))
+
+	handler = filter.WithAuthorization(handler, s.authorizer)
+
+	userLister := s.InformerFactory.HorizonSharedInformerFactory().Iam().V1alpha2().Users().Lister()
+	authn := unionauth.New(
+		anonymous.NewAuthenticator(),
+		bearertoken.New(jwt.NewTokenAuthenticator(s.tokenOperator, userLister)),
+	)
+	handler = filter.WithAuthentication(handler, authn)
+
+	handler = filter.WithRequestInfo(handler, s.requestInfoResolver)
+	s.Server.Handler = handler
+}
+
+// newRequestInfoResolver returns the resolver of the request info, the resources not namespaced
+// in Horizon are authorized at the global scope.
+func newRequestInfoResolver() *request.RequestInfoFactory {
+	return &request.RequestInfoFactory{
 		APIPrefixes:          sets.New("api", "apis", "hapis", "hapi"),
 		GroupLessAPIPrefixes: sets.New("api", "hapi"),
-		GlobalResources:      []schema.GroupResource{},
+		GlobalResources: []schema.GroupResource{
+			iamapiv1alpha2.Resource(iamapiv1alpha2.ResourcePluralUser),
+			iamapiv1alpha2.Resource(iamapiv1alpha2.ResourcePluralLoginRecord),
+			iamapiv1alpha2.Resource(iamapiv1alpha2.ResourcePluralGlobalRole),
+			iamapiv1alpha2.Resource(iamapiv1alpha2.ResourcePluralGlobalRoleBinding),
+			iamapiv1alpha2.Resource("subjectaccessreviews"),
+			iamapiv1alpha2.Resource("selfsubjectaccessreviews"),
+			tenantv1alpha1.Resource(tenantv1alpha1.ResourcePluralWorkspace),
+			tenantv1alpha1.Resource(tenantv1alpha1.ResourcePluralWorkspaceTemplate),
+			tenantv1alpha1.Resource(tenantv1alpha1.ResourcePluralWorkspaceResourceQuota),
+			tenantv1alpha1.Resource(clusterv1alpha1.ResourcesPluralCluster),
+		},
 	}
+}
 
-	handler := s.Server.Handler
-	handler = filter.WithKubeAPIServer(handler, s.KubernetesClient.Config())
+// newAuthorizer returns the authorizer chain shared by the authorization filter and the access reviews,
+// the always allowed paths are checked before the authorizer of the configured mode.
+func (s *APIServer) newAuthorizer() authorizer.Authorizer {
+	var authorizers authorizer.Authorizer
+	switch s.Config.AuthorizationOptions.Mode {
+	case authorization.AlwaysAllow:
+		authorizers = authorizerfactory.NewAlwaysAllowAuthorizer()
+	case authorization.AlwaysDeny:
+		authorizers = authorizerfactory.NewAlwaysDenyAuthorizer()
+	default:
+		// authorization.RBAC, the users are always allowed to access their own records
+		authorizers = unionauthorizer.New(self.NewAuthorizer(), rbac.NewRBACAuthorizer(am.NewReadOnlyOperator(s.InformerFactory), s.localClusterName()))
+	}
+	pathAuthorizer, err := path.NewAuthorizer(s.Config.AuthorizationOptions.AlwaysAllowedPaths)
+	if err != nil {
+		klog.Fatalf("unable to create path authorizer: %v", err)
+	}
 
-	handler = filter.WithRequestInfo(handler, requestInfoResolver)
-	s.Server.Handler = handler
+	return unionauthorizer.New(pathAuthorizer, authorizers)
+}
+
+// localClusterName returns the name of the cluster the apiserver runs in,
+// the host cluster is not named until it is joined by the cluster controller.
+func (s *APIServer) localClusterName() string {
+	if s.Config.MultiClusterOptions.ClusterName != "" {
+		return s.Config.MultiClusterOptions.ClusterName
+	}
+	return s.Config.MultiClusterOptions.HostClusterName
+}
+
+func (s *APIServer) newTokenStore() token.Store {
+	if s.Config.AuthenticationOptions.TokenStore == authentication.TokenStoreSecret {
+		return token.NewSecretStore(
+			s.KubernetesClient.Kubernetes(),
+			s.InformerFactory.KubernetesSharedInformerFactory().Core().V1().Secrets().Lister(),
+			constants.HorizonNamespace)
+	}
+	return token.NewMemoryStore()
 }
 
 func (s *APIServer) Run(ctx context.Context) (err error) {
@@ -190,7 +293,17 @@ func (s *APIServer) waitForResourceSync(ctx context.Context) error {
 
 	hzGVRs := map[schema.GroupVersion][]string{
 		{Group: "cluster.horizon.io", Version: "v1alpha1"}: {"clusters"},
-		{Group: "tenant.horiozn.io", Version: "v1alpha1"}:  {"workspaces"},
+		{Group: "tenant.horizon.io", Version: "v1alpha1"}:  {"workspaces", "workspacetemplates"},
+		{Group: "iam.horizon.io", Version: "v1alpha2"}: {
+			"users",
+			"loginrecords",
+			"groups",
+			"groupbindings",
+			"globalroles",
+			"globalrolebindings",
+			"workspaceroles",
+			"workspacerolebindings",
+		},
 	}
 
 	if err := waitForCacheSync(
@@ -306,11 +419,15 @@ func (s *APIServer) metricsAPI() {
 
 func (s *APIServer) horizonAPIs(stopCh <-chan struct{}) {
 	imOperator := im.NewOperator(
+		s.KubernetesClient.Horizon(),
 		user.New(s.InformerFactory.KubernetesSharedInformerFactory(), s.InformerFactory.HorizonSharedInformerFactory()),
+		loginrecord.New(s.InformerFactory.HorizonSharedInformerFactory()),
+		s.tokenOperator,
 	)
 
+	userLister := s.InformerFactory.HorizonSharedInformerFactory().Iam().V1alpha2().Users().Lister()
+
 	amOperator := am.NewOperator(s.KubernetesClient.Kubernetes(), s.KubernetesClient.Horizon(), s.InformerFactory)
-	rbacAuthorizer := rbac.NewRBACAuthorizer(amOperator)
 
 	urlruntime.Must(clusterv1alphal.AddToContainer(
 		s.container,
@@ -325,13 +442,45 @@ func (s *APIServer) horizonAPIs(stopCh <-chan struct{}) {
 		s.container,
 		s.InformerFactory,
 		s.KubernetesClient.Kubernetes(),
-		s.KubernetesClient.Horizon()))
+		s.KubernetesClient.Horizon(),
+		amOperator,
+		s.authorizer))
+
+	groupOperator := group.New(
+		s.KubernetesClient.Horizon(),
+		amOperator,
+		groupresource.New(s.InformerFactory.HorizonSharedInformerFactory()),
+		groupbinding.New(s.InformerFactory.HorizonSharedInformerFactory()),
+		user.New(s.InformerFactory.KubernetesSharedInformerFactory(), s.InformerFactory.HorizonSharedInformerFactory()),
+	)
 
 	urlruntime.Must(iamv1alpha2.AddToContainer(
 		s.container,
-		rbacAuthorizer,
-		imOperator))
-
+		s.authorizer,
+		imOperator,
+		amOperator,
+		groupOperator,
+		s.requestInfoResolver,
+		kubeconfig.NewReadOnlyOperator(s.InformerFactory.KubernetesSharedInformerFactory().Core().V1().Secrets().Lister())))
+
+	urlruntime.Must(terminalv1alpha2.AddToContainer(
+		s.container,
+		kubectl.NewOperator(
+			s.KubernetesClient.Kubernetes(),
+			s.InformerFactory.KubernetesSharedInformerFactory().Core().V1().Pods().Lister(),
+			userLister,
+			s.Config.KubernetesOptions.KubectlImage),
+		terminal.NewTerminaler(s.KubernetesClient.Kubernetes(), s.KubernetesClient.Config()),
+		s.Config.KubernetesOptions.ConsoleOrigins))
+
+	urlruntime.Must(oauth.AddToContainer(
+		s.container,
+		imOperator,
+		s.tokenOperator,
+		auth.NewPasswordAuthenticator(imOperator, s.KubernetesClient.Horizon(), userLister, s.Config.AuthenticationOptions),
+		auth.NewOAuthAuthenticator(s.KubernetesClient.Horizon(), userLister, s.Config.AuthenticationOptions),
+		auth.NewLoginRecorder(s.KubernetesClient.Horizon(), userLister),
+		s.Config.AuthenticationOptions))
 }
 
 func logRequest(req *restful.Request, rep *restful.Response, chain *restful.FilterChain) {
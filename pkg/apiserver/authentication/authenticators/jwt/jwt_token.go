@@ -0,0 +1,61 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/token"
+	"github.com/sunweiwe/horizon/pkg/models/auth"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/klog/v2"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	iamv1alpha2listers "github.com/sunweiwe/horizon/pkg/client/listers/iam/v1alpha2"
+)
+
+// tokenAuthenticator implements authenticator.Token, it verifies the access token
+// has not been revoked and makes sure the user it was issued to still exists and is active.
+type tokenAuthenticator struct {
+	tokenOperator auth.TokenManagementInterface
+	userLister    iamv1alpha2listers.UserLister
+}
+
+func NewTokenAuthenticator(tokenOperator auth.TokenManagementInterface, userLister iamv1alpha2listers.UserLister) authenticator.Token {
+	return &tokenAuthenticator{
+		tokenOperator: tokenOperator,
+		userLister:    userLister,
+	}
+}
+
+func (t *tokenAuthenticator) AuthenticateToken(ctx context.Context, tokenString string) (*authenticator.Response, bool, error) {
+	providedUser, tokenType, err := t.tokenOperator.Verify(tokenString)
+	if err != nil {
+		klog.V(4).Infof("Failed to verify token: %v", err)
+		return nil, false, err
+	}
+
+	if tokenType != token.AccessToken && tokenType != token.StaticToken {
+		return nil, false, fmt.Errorf("token type %s is not allowed to access resources", tokenType)
+	}
+
+	u, err := t.userLister.Get(providedUser.GetName())
+	if err != nil {
+		return nil, false, err
+	}
+
+	if u.Status.State != iamv1alpha2.UserActive {
+		return nil, false, fmt.Errorf("user %s is not active", u.Name)
+	}
+
+	// group membership is taken from the user rather than the token, so that it takes effect immediately
+	groups := append([]string{}, u.Spec.Groups...)
+	groups = append(groups, user.AllAuthenticated)
+
+	return &authenticator.Response{
+		User: &user.DefaultInfo{
+			Name:   u.GetName(),
+			Groups: groups,
+		},
+	}, true, nil
+}
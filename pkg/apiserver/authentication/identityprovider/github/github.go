@@ -0,0 +1,170 @@
+package github
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/identityprovider"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/oauth"
+	"golang.org/x/oauth2"
+	"k8s.io/klog/v2"
+)
+
+const (
+	githubIdentityProvider = "GitHubIdentityProvider"
+
+	authURL     = "https://github.com/login/oauth/authorize"
+	tokenURL    = "https://github.com/login/oauth/access_token"
+	userInfoURL = "https://api.github.com/user"
+)
+
+func init() {
+	identityprovider.RegisterOAuthProvider(&githubProviderFactory{})
+}
+
+type github struct {
+	// ClientID is the application's ID.
+	ClientID string `json:"clientID" yaml:"clientID"`
+
+	// ClientSecret is the application's secret.
+	ClientSecret string `json:"-" yaml:"clientSecret"`
+
+	// Endpoint contains the resource server's token endpoint
+	// URLs. These are constants specific to each server and are
+	// often available via site-specific packages, such as
+	// google.Endpoint or github.endpoint.
+	Endpoint endpoint `json:"endpoint" yaml:"endpoint"`
+
+	// RedirectURL is the URL to redirect users going through
+	// the OAuth flow, after the resource owner's URLs.
+	RedirectURL string `json:"redirectURL" yaml:"redirectURL"`
+
+	// Used to turn off TLS certificate checks
+	InsecureSkipVerify bool `json:"insecureSkipVerify" yaml:"insecureSkipVerify"`
+
+	// Scope specifies optional requested permissions.
+	Scopes []string `json:"scopes" yaml:"scopes"`
+
+	httpClient   *http.Client
+	oauth2Config *oauth2.Config
+}
+
+// endpoint represents an OAuth 2.0 provider's authorization and token
+// endpoint URLs, the default values point to github.com
+type endpoint struct {
+	AuthURL     string `json:"authURL" yaml:"authURL"`
+	TokenURL    string `json:"tokenURL" yaml:"tokenURL"`
+	UserInfoURL string `json:"userInfoURL" yaml:"userInfoURL"`
+}
+
+type githubIdentity struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+	Email string `json:"email"`
+}
+
+func (g *githubIdentity) GetUserID() string {
+	return strconv.FormatInt(g.ID, 10)
+}
+
+func (g *githubIdentity) GetUsername() string {
+	return g.Login
+}
+
+func (g *githubIdentity) GetEmail() string {
+	return g.Email
+}
+
+type githubProviderFactory struct {
+}
+
+func (g *githubProviderFactory) Type() string {
+	return githubIdentityProvider
+}
+
+func (g *githubProviderFactory) Create(options oauth.DynamicOptions) (identityprovider.OAuthProvider, error) {
+	var provider github
+	if err := mapstructure.Decode(options, &provider); err != nil {
+		return nil, err
+	}
+
+	if provider.Endpoint.AuthURL == "" {
+		provider.Endpoint.AuthURL = authURL
+	}
+	if provider.Endpoint.TokenURL == "" {
+		provider.Endpoint.TokenURL = tokenURL
+	}
+	if provider.Endpoint.UserInfoURL == "" {
+		provider.Endpoint.UserInfoURL = userInfoURL
+	}
+
+	provider.httpClient = &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: provider.InsecureSkipVerify},
+		},
+	}
+
+	provider.oauth2Config = &oauth2.Config{
+		ClientID:     provider.ClientID,
+		ClientSecret: provider.ClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  provider.Endpoint.AuthURL,
+			TokenURL: provider.Endpoint.TokenURL,
+		},
+		RedirectURL: provider.RedirectURL,
+		Scopes:      provider.Scopes,
+	}
+	return &provider, nil
+}
+
+func (g *github) AuthCodeURL(state string) string {
+	return g.oauth2Config.AuthCodeURL(state)
+}
+
+// IdentityExchangeCallback exchanges the authorization code for the access token and fetches the user from the userinfo endpoint
+func (g *github) IdentityExchangeCallback(req *http.Request) (identityprovider.Identity, error) {
+	code := req.URL.Query().Get("code")
+	if code == "" {
+		return nil, errors.New("authorization code is required")
+	}
+
+	ctx := context.WithValue(req.Context(), oauth2.HTTPClient, g.httpClient)
+	token, err := g.oauth2Config.Exchange(ctx, code)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	resp, err := oauth2.NewClient(ctx, oauth2.StaticTokenSource(token)).Get(g.Endpoint.UserInfoURL)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch user info: %s: %s", resp.Status, data)
+	}
+
+	var githubIdentity githubIdentity
+	if err := json.Unmarshal(data, &githubIdentity); err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	return &githubIdentity, nil
+}
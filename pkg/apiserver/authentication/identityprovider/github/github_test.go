@@ -0,0 +1,143 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/oauth"
+)
+
+const (
+	testCode        = "code"
+	testAccessToken = "access-token"
+)
+
+// newTestServer returns a stand-in of github.com which exchanges testCode for testAccessToken
+// and serves the user of testAccessToken with the given status.
+func newTestServer(t *testing.T, userStatus int) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("failed to parse token request: %v", err)
+		}
+		if r.Form.Get("code") != testCode {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"bad_verification_code"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": testAccessToken,
+			"token_type":   "bearer",
+		})
+	})
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+testAccessToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(userStatus)
+		if userStatus == http.StatusOK {
+			w.Write([]byte(`{"id":1024,"login":"octocat","email":"octocat@github.com"}`))
+		}
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestGithubIdentityExchangeCallback(t *testing.T) {
+	tests := []struct {
+		name         string
+		code         string
+		userStatus   int
+		wantErr      bool
+		wantUserID   string
+		wantUsername string
+		wantEmail    string
+	}{
+		{
+			name:         "exchange code for identity",
+			code:         testCode,
+			userStatus:   http.StatusOK,
+			wantUserID:   "1024",
+			wantUsername: "octocat",
+			wantEmail:    "octocat@github.com",
+		},
+		{
+			name:       "missing code",
+			code:       "",
+			userStatus: http.StatusOK,
+			wantErr:    true,
+		},
+		{
+			name:       "invalid code",
+			code:       "invalid",
+			userStatus: http.StatusOK,
+			wantErr:    true,
+		},
+		{
+			name:       "user info unavailable",
+			code:       testCode,
+			userStatus: http.StatusInternalServerError,
+			wantErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newTestServer(t, test.userStatus)
+			defer server.Close()
+
+			provider, err := (&githubProviderFactory{}).Create(oauth.DynamicOptions{
+				"clientID":     "horizon",
+				"clientSecret": "horizon",
+				"redirectURL":  "http://horizon.io/oauth/callback/github",
+				"endpoint": oauth.DynamicOptions{
+					"authURL":     server.URL + "/login/oauth/authorize",
+					"tokenURL":    server.URL + "/login/oauth/access_token",
+					"userInfoURL": server.URL + "/user",
+				},
+			})
+			if err != nil {
+				t.Fatalf("failed to create provider: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/oauth/callback/github?code="+test.code, nil)
+			identity, err := provider.IdentityExchangeCallback(req)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got identity %+v", identity)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if identity.GetUserID() != test.wantUserID {
+				t.Errorf("expected user id %q, got %q", test.wantUserID, identity.GetUserID())
+			}
+			if identity.GetUsername() != test.wantUsername {
+				t.Errorf("expected username %q, got %q", test.wantUsername, identity.GetUsername())
+			}
+			if identity.GetEmail() != test.wantEmail {
+				t.Errorf("expected email %q, got %q", test.wantEmail, identity.GetEmail())
+			}
+		})
+	}
+}
+
+func TestGithubProviderFactoryDefaultEndpoint(t *testing.T) {
+	provider, err := (&githubProviderFactory{}).Create(oauth.DynamicOptions{"clientID": "horizon"})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	endpoint := provider.(*github).Endpoint
+	if endpoint.AuthURL != authURL || endpoint.TokenURL != tokenURL || endpoint.UserInfoURL != userInfoURL {
+		t.Errorf("expected the endpoint of github.com, got %+v", endpoint)
+	}
+}
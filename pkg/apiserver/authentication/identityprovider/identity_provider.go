@@ -0,0 +1,145 @@
+package identityprovider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/oauth"
+	"k8s.io/klog/v2"
+)
+
+var (
+	ErrorIdentityProviderNotFound = errors.New("the identity provider was not found")
+	ErrorAlreadyRegistered        = errors.New("the identity provider was already registered")
+
+	genericProviderFactories = make(map[string]GenericProviderFactory)
+	oauthProviderFactories   = make(map[string]OAuthProviderFactory)
+
+	mutex            sync.RWMutex
+	genericProviders = make(map[string]GenericProvider)
+	oauthProviders   = make(map[string]OAuthProvider)
+)
+
+// Identity represents the account mapped to horizon
+type Identity interface {
+	// GetUserID required
+	// Identifier for the End-User at the Issuer.
+	GetUserID() string
+	// GetUsername optional
+	// The username which the End-User wishes to be referred to horizon.
+	GetUsername() string
+	// GetEmail optional
+	GetEmail() string
+}
+
+// GenericProvider authenticates the user with username and password, e.g. LDAP
+type GenericProvider interface {
+	// Authenticate from remote server
+	Authenticate(username string, password string) (Identity, error)
+}
+
+type GenericProviderFactory interface {
+	// Type unique type of the provider
+	Type() string
+	// Create Apply the dynamic options from horizon-config
+	Create(options oauth.DynamicOptions) (GenericProvider, error)
+}
+
+// OAuthProvider exchanges the authorization code of the OAuth callback for the identity, e.g. OIDC and GitHub
+type OAuthProvider interface {
+	// AuthCodeURL returns the URL of the consent page of the remote server, the state is passed back to the callback
+	AuthCodeURL(state string) string
+	// IdentityExchangeCallback handle oauth callback, exchange identity from remote server
+	IdentityExchangeCallback(req *http.Request) (Identity, error)
+}
+
+type OAuthProviderFactory interface {
+	// Type unique type of the provider
+	Type() string
+	// Create Apply the dynamic options
+	Create(options oauth.DynamicOptions) (OAuthProvider, error)
+}
+
+// RegisterGenericProvider registers the factory of GenericProvider, it's called in the init function of the provider package
+func RegisterGenericProvider(factory GenericProviderFactory) {
+	if _, ok := genericProviderFactories[factory.Type()]; ok {
+		panic(fmt.Errorf("%w: %s", ErrorAlreadyRegistered, factory.Type()))
+	}
+	genericProviderFactories[factory.Type()] = factory
+}
+
+// RegisterOAuthProvider registers the factory of OAuthProvider, it's called in the init function of the provider package
+func RegisterOAuthProvider(factory OAuthProviderFactory) {
+	if _, ok := oauthProviderFactories[factory.Type()]; ok {
+		panic(fmt.Errorf("%w: %s", ErrorAlreadyRegistered, factory.Type()))
+	}
+	oauthProviderFactories[factory.Type()] = factory
+}
+
+// SetupWithOptions creates the identity providers configured in the oauth options,
+// the providers created previously are replaced.
+func SetupWithOptions(options []oauth.IdentityProviderOptions) error {
+	generics := make(map[string]GenericProvider)
+	oauths := make(map[string]OAuthProvider)
+
+	for _, o := range options {
+		if _, ok := generics[o.Name]; ok {
+			return fmt.Errorf("duplicate identity provider found: %s", o.Name)
+		}
+		if _, ok := oauths[o.Name]; ok {
+			return fmt.Errorf("duplicate identity provider found: %s", o.Name)
+		}
+
+		if factory, ok := genericProviderFactories[o.Type]; ok {
+			provider, err := factory.Create(o.Provider)
+			if err != nil {
+				klog.Error(fmt.Sprintf("failed to create identity provider %s: %s", o.Name, err))
+				return err
+			}
+			generics[o.Name] = provider
+			klog.V(4).Infof("create identity provider %s successfully", o.Name)
+			continue
+		}
+
+		if factory, ok := oauthProviderFactories[o.Type]; ok {
+			provider, err := factory.Create(o.Provider)
+			if err != nil {
+				klog.Error(fmt.Sprintf("failed to create identity provider %s: %s", o.Name, err))
+				return err
+			}
+			oauths[o.Name] = provider
+			klog.V(4).Infof("create identity provider %s successfully", o.Name)
+			continue
+		}
+
+		return fmt.Errorf("unsupported identity provider type %s of %s", o.Type, o.Name)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	genericProviders = generics
+	oauthProviders = oauths
+	return nil
+}
+
+// GetGenericProvider returns GenericProvider with given name
+func GetGenericProvider(providerName string) (GenericProvider, error) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if provider, ok := genericProviders[providerName]; ok {
+		return provider, nil
+	}
+	return nil, ErrorIdentityProviderNotFound
+}
+
+// GetOAuthProvider returns OAuthProvider with given name
+func GetOAuthProvider(providerName string) (OAuthProvider, error) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if provider, ok := oauthProviders[providerName]; ok {
+		return provider, nil
+	}
+	return nil, ErrorIdentityProviderNotFound
+}
@@ -0,0 +1,186 @@
+package ldap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/mitchellh/mapstructure"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/identityprovider"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/oauth"
+	"k8s.io/klog/v2"
+)
+
+const (
+	ldapIdentityProvider = "LDAPIdentityProvider"
+	defaultReadTimeout   = 15000
+)
+
+var (
+	ErrIncorrectCredentials = errors.New("incorrect username or password")
+)
+
+func init() {
+	identityprovider.RegisterGenericProvider(&ldapProviderFactory{})
+}
+
+type ldapProvider struct {
+	// Host and optional port of the LDAP server in the form "host:port".
+	// If the port is not supplied, 389 for insecure or StartTLS connections, 636
+	Host string `json:"host,omitempty" yaml:"host"`
+	// Timeout duration when reading data from remote server. Default to 15s.
+	ReadTimeout int `json:"readTimeout" yaml:"readTimeout"`
+	// If specified, connections will use the ldaps:// protocol
+	StartTLS bool `json:"startTLS,omitempty" yaml:"startTLS"`
+	// Used to turn off TLS certificate checks
+	InsecureSkipVerify bool `json:"insecureSkipVerify" yaml:"insecureSkipVerify"`
+	// Path to a trusted root certificate file. Default: use the host's root CA.
+	RootCA string `json:"rootCA,omitempty" yaml:"rootCA"`
+	// A raw certificate file can also be provided inline. Base64 encoded PEM file
+	RootCAData string `json:"rootCAData,omitempty" yaml:"rootCAData"`
+	// Username (DN) of the "manager" user identity.
+	ManagerDN string `json:"managerDN,omitempty" yaml:"managerDN"`
+	// The password for the manager DN.
+	ManagerPassword string `json:"-" yaml:"managerPassword"`
+	// User search scope.
+	UserSearchBase string `json:"userSearchBase,omitempty" yaml:"userSearchBase"`
+	// LDAP filter used to identify objects of type user. e.g. (objectClass=person)
+	UserSearchFilter string `json:"userSearchFilter,omitempty" yaml:"userSearchFilter"`
+	// The following three fields are direct mappings of attributes on the user entry.
+	// Username attribute, e.g. uid
+	LoginAttribute string `json:"loginAttribute" yaml:"loginAttribute"`
+	// Email attribute, e.g. mail
+	MailAttribute string `json:"mailAttribute" yaml:"mailAttribute"`
+}
+
+type ldapProviderFactory struct {
+}
+
+func (l *ldapProviderFactory) Type() string {
+	return ldapIdentityProvider
+}
+
+func (l *ldapProviderFactory) Create(options oauth.DynamicOptions) (identityprovider.GenericProvider, error) {
+	var provider ldapProvider
+	if err := mapstructure.Decode(options, &provider); err != nil {
+		return nil, err
+	}
+	if provider.ReadTimeout <= 0 {
+		provider.ReadTimeout = defaultReadTimeout
+	}
+	if provider.LoginAttribute == "" {
+		provider.LoginAttribute = "uid"
+	}
+	if provider.MailAttribute == "" {
+		provider.MailAttribute = "mail"
+	}
+	return &provider, nil
+}
+
+type ldapIdentity struct {
+	Username string
+	Email    string
+}
+
+func (l *ldapIdentity) GetUserID() string {
+	return l.Username
+}
+
+func (l *ldapIdentity) GetUsername() string {
+	return l.Username
+}
+
+func (l *ldapIdentity) GetEmail() string {
+	return l.Email
+}
+
+// Authenticate searches the user entry with the manager account, then binds with the user entry and password
+func (l *ldapProvider) Authenticate(username string, password string) (identityprovider.Identity, error) {
+	if username == "" || password == "" {
+		return nil, ErrIncorrectCredentials
+	}
+
+	conn, err := l.newConn()
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	defer conn.Close()
+
+	conn.SetTimeout(time.Duration(l.ReadTimeout) * time.Millisecond)
+	if err = conn.Bind(l.ManagerDN, l.ManagerPassword); err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	filter := fmt.Sprintf("(&(%s=%s)%s)", l.LoginAttribute, ldap.EscapeFilter(username), l.UserSearchFilter)
+	result, err := conn.Search(&ldap.SearchRequest{
+		BaseDN:       l.UserSearchBase,
+		Scope:        ldap.ScopeWholeSubtree,
+		DerefAliases: ldap.NeverDerefAliases,
+		SizeLimit:    1,
+		TimeLimit:    0,
+		TypesOnly:    false,
+		Filter:       filter,
+		Attributes:   []string{l.LoginAttribute, l.MailAttribute},
+	})
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	if len(result.Entries) == 0 {
+		return nil, ErrIncorrectCredentials
+	}
+
+	entry := result.Entries[0]
+	if err = conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrIncorrectCredentials
+		}
+		klog.Error(err)
+		return nil, err
+	}
+
+	return &ldapIdentity{
+		Username: entry.GetAttributeValue(l.LoginAttribute),
+		Email:    entry.GetAttributeValue(l.MailAttribute),
+	}, nil
+}
+
+func (l *ldapProvider) newConn() (*ldap.Conn, error) {
+	if !l.StartTLS {
+		return ldap.DialURL(fmt.Sprintf("ldap://%s", l.Host))
+	}
+
+	tlsConfig := tls.Config{}
+	if l.InsecureSkipVerify {
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	tlsConfig.RootCAs = x509.NewCertPool()
+	var caCert []byte
+	var err error
+	// Load CA cert
+	if l.RootCA != "" {
+		if caCert, err = os.ReadFile(l.RootCA); err != nil {
+			klog.Error(err)
+			return nil, err
+		}
+	}
+	if l.RootCAData != "" {
+		if caCert, err = base64.StdEncoding.DecodeString(l.RootCAData); err != nil {
+			klog.Error(err)
+			return nil, err
+		}
+	}
+	if caCert != nil {
+		tlsConfig.RootCAs.AppendCertsFromPEM(caCert)
+	}
+	return ldap.DialURL(fmt.Sprintf("ldaps://%s", l.Host), ldap.DialWithTLSConfig(&tlsConfig))
+}
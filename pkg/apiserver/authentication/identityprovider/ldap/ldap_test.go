@@ -0,0 +1,234 @@
+package ldap
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/oauth"
+)
+
+const (
+	testManagerDN       = "cn=admin,dc=horizon,dc=io"
+	testManagerPassword = "admin"
+	testUserSearchBase  = "ou=Users,dc=horizon,dc=io"
+)
+
+type testEntry struct {
+	password   string
+	attributes map[string]string
+}
+
+// testServer is a stand-in LDAP server, it only understands the simple bind and the search
+// by equality of the login attribute issued by the provider.
+type testServer struct {
+	listener net.Listener
+	// entries are keyed by DN
+	entries map[string]testEntry
+}
+
+func newTestServer(t *testing.T, entries map[string]testEntry) *testServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	s := &testServer{listener: listener, entries: entries}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *testServer) Host() string {
+	return s.listener.Addr().String()
+}
+
+func (s *testServer) Close() {
+	s.listener.Close()
+}
+
+func (s *testServer) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+
+		messageID := packet.Children[0].Value
+		request := packet.Children[1]
+		switch request.Tag {
+		case ldap.ApplicationBindRequest:
+			dn := request.Children[1].Data.String()
+			password := request.Children[2].Data.String()
+			resultCode := uint16(ldap.LDAPResultInvalidCredentials)
+			if entry, ok := s.entries[dn]; ok && entry.password == password {
+				resultCode = ldap.LDAPResultSuccess
+			}
+			s.write(conn, messageID, newResult(ldap.ApplicationBindResponse, resultCode))
+		case ldap.ApplicationSearchRequest:
+			filter, err := ldap.DecompileFilter(request.Children[6])
+			if err != nil {
+				return
+			}
+			for dn, entry := range s.entries {
+				if dn == testManagerDN {
+					continue
+				}
+				if filter == fmt.Sprintf("(&(uid=%s))", ldap.EscapeFilter(entry.attributes["uid"])) {
+					s.write(conn, messageID, newSearchResultEntry(dn, entry.attributes))
+				}
+			}
+			s.write(conn, messageID, newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess))
+		case ldap.ApplicationUnbindRequest:
+			return
+		}
+	}
+}
+
+func (s *testServer) write(conn net.Conn, messageID interface{}, response *ber.Packet) {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "Message ID"))
+	packet.AppendChild(response)
+	conn.Write(packet.Bytes())
+}
+
+func newResult(tag ber.Tag, resultCode uint16) *ber.Packet {
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, uint64(resultCode), "Result Code"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	return result
+}
+
+func newSearchResultEntry(dn string, attributes map[string]string) *ber.Packet {
+	entry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	entry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, dn, "Object Name"))
+	list := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for name, value := range attributes {
+		attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+		values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+		attribute.AppendChild(values)
+		list.AppendChild(attribute)
+	}
+	entry.AppendChild(list)
+	return entry
+}
+
+func TestLDAPAuthenticate(t *testing.T) {
+	server := newTestServer(t, map[string]testEntry{
+		testManagerDN: {password: testManagerPassword},
+		"uid=alice,ou=Users,dc=horizon,dc=io": {
+			password:   "P@88w0rd",
+			attributes: map[string]string{"uid": "alice", "mail": "alice@horizon.io"},
+		},
+	})
+	defer server.Close()
+
+	tests := []struct {
+		name            string
+		managerPassword string
+		username        string
+		password        string
+		wantErr         error
+		wantAnyErr      bool
+		wantUsername    string
+		wantEmail       string
+	}{
+		{
+			name:            "authenticate user",
+			managerPassword: testManagerPassword,
+			username:        "alice",
+			password:        "P@88w0rd",
+			wantUsername:    "alice",
+			wantEmail:       "alice@horizon.io",
+		},
+		{
+			name:            "incorrect password",
+			managerPassword: testManagerPassword,
+			username:        "alice",
+			password:        "incorrect",
+			wantErr:         ErrIncorrectCredentials,
+		},
+		{
+			name:            "user not found",
+			managerPassword: testManagerPassword,
+			username:        "bob",
+			password:        "P@88w0rd",
+			wantErr:         ErrIncorrectCredentials,
+		},
+		{
+			name:            "empty password",
+			managerPassword: testManagerPassword,
+			username:        "alice",
+			password:        "",
+			wantErr:         ErrIncorrectCredentials,
+		},
+		{
+			name:            "incorrect manager password",
+			managerPassword: "incorrect",
+			username:        "alice",
+			password:        "P@88w0rd",
+			wantAnyErr:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			provider, err := (&ldapProviderFactory{}).Create(oauth.DynamicOptions{
+				"host":            server.Host(),
+				"managerDN":       testManagerDN,
+				"managerPassword": test.managerPassword,
+				"userSearchBase":  testUserSearchBase,
+			})
+			if err != nil {
+				t.Fatalf("failed to create provider: %v", err)
+			}
+
+			identity, err := provider.Authenticate(test.username, test.password)
+			if test.wantErr != nil || test.wantAnyErr {
+				if err == nil {
+					t.Fatalf("expected error, got identity %+v", identity)
+				}
+				if test.wantErr != nil && !errors.Is(err, test.wantErr) {
+					t.Fatalf("expected error %v, got %v", test.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if identity.GetUsername() != test.wantUsername {
+				t.Errorf("expected username %q, got %q", test.wantUsername, identity.GetUsername())
+			}
+			if identity.GetEmail() != test.wantEmail {
+				t.Errorf("expected email %q, got %q", test.wantEmail, identity.GetEmail())
+			}
+		})
+	}
+}
+
+func TestLDAPProviderFactoryDefaults(t *testing.T) {
+	provider, err := (&ldapProviderFactory{}).Create(oauth.DynamicOptions{"host": "localhost:389"})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	l := provider.(*ldapProvider)
+	if l.ReadTimeout != defaultReadTimeout || l.LoginAttribute != "uid" || l.MailAttribute != "mail" {
+		t.Errorf("expected the default options, got %+v", l)
+	}
+}
@@ -0,0 +1,191 @@
+package oidc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/mitchellh/mapstructure"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/identityprovider"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/oauth"
+	"golang.org/x/oauth2"
+	"k8s.io/klog/v2"
+)
+
+const (
+	oidcIdentityProvider = "OIDCIdentityProvider"
+
+	defaultPreferredUsernameKey = "preferred_username"
+	defaultEmailKey             = "email"
+)
+
+func init() {
+	identityprovider.RegisterOAuthProvider(&oidcProviderFactory{})
+}
+
+type oidcProvider struct {
+	// Defines how Clients dynamically discover information about OpenID Providers
+	// See also, https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderConfig
+	Issuer string `json:"issuer" yaml:"issuer"`
+
+	// ClientID is the application's ID.
+	ClientID string `json:"clientID" yaml:"clientID"`
+
+	// ClientSecret is the application's secret.
+	ClientSecret string `json:"-" yaml:"clientSecret"`
+
+	// RedirectURL is the URL to redirect users going through
+	// the OAuth flow, after the resource owner's URLs.
+	RedirectURL string `json:"redirectURL" yaml:"redirectURL"`
+
+	// Scope specifies optional requested permissions.
+	Scopes []string `json:"scopes" yaml:"scopes"`
+
+	// GetUserInfo uses the userinfo endpoint to get additional claims for the token.
+	// This is especially useful where upstreams return "thin" id tokens
+	// See also, https://openid.net/specs/openid-connect-core-1_0.html#UserInfo
+	GetUserInfo bool `json:"getUserInfo" yaml:"getUserInfo"`
+
+	// Used to turn off TLS certificate checks
+	InsecureSkipVerify bool `json:"insecureSkipVerify" yaml:"insecureSkipVerify"`
+
+	// Configurable key which contains the preferred username claims
+	PreferredUsernameKey string `json:"preferredUsernameKey" yaml:"preferredUsernameKey"`
+
+	// Configurable key which contains the email claims
+	EmailKey string `json:"emailKey" yaml:"emailKey"`
+
+	httpClient   *http.Client
+	provider     *oidc.Provider
+	verifier     *oidc.IDTokenVerifier
+	oauth2Config *oauth2.Config
+}
+
+type oidcIdentity struct {
+	Sub               string
+	PreferredUsername string
+	Email             string
+}
+
+func (o *oidcIdentity) GetUserID() string {
+	return o.Sub
+}
+
+func (o *oidcIdentity) GetUsername() string {
+	return o.PreferredUsername
+}
+
+func (o *oidcIdentity) GetEmail() string {
+	return o.Email
+}
+
+type oidcProviderFactory struct {
+}
+
+func (f *oidcProviderFactory) Type() string {
+	return oidcIdentityProvider
+}
+
+func (f *oidcProviderFactory) Create(options oauth.DynamicOptions) (identityprovider.OAuthProvider, error) {
+	var o oidcProvider
+	if err := mapstructure.Decode(options, &o); err != nil {
+		return nil, err
+	}
+
+	if o.PreferredUsernameKey == "" {
+		o.PreferredUsernameKey = defaultPreferredUsernameKey
+	}
+	if o.EmailKey == "" {
+		o.EmailKey = defaultEmailKey
+	}
+	if len(o.Scopes) == 0 {
+		o.Scopes = []string{oidc.ScopeOpenID}
+	}
+
+	o.httpClient = &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: o.InsecureSkipVerify},
+		},
+	}
+
+	// dynamically discover the endpoints of the provider
+	ctx := oidc.ClientContext(context.Background(), o.httpClient)
+	provider, err := oidc.NewProvider(ctx, o.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query provider %q: %v", o.Issuer, err)
+	}
+
+	o.provider = provider
+	o.verifier = provider.Verifier(&oidc.Config{ClientID: o.ClientID})
+	o.oauth2Config = &oauth2.Config{
+		ClientID:     o.ClientID,
+		ClientSecret: o.ClientSecret,
+		Endpoint:     provider.Endpoint(),
+		RedirectURL:  o.RedirectURL,
+		Scopes:       o.Scopes,
+	}
+	return &o, nil
+}
+
+func (o *oidcProvider) AuthCodeURL(state string) string {
+	return o.oauth2Config.AuthCodeURL(state)
+}
+
+// IdentityExchangeCallback exchanges the authorization code for the id token and extracts the identity from the claims
+func (o *oidcProvider) IdentityExchangeCallback(req *http.Request) (identityprovider.Identity, error) {
+	code := req.URL.Query().Get("code")
+	if code == "" {
+		return nil, errors.New("authorization code is required")
+	}
+
+	ctx := oidc.ClientContext(req.Context(), o.httpClient)
+	token, err := o.oauth2Config.Exchange(ctx, code)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("no id_token in token response")
+	}
+
+	idToken, err := o.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	claims := make(map[string]interface{})
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("failed to decode id token claims: %v", err)
+	}
+
+	if o.GetUserInfo {
+		userInfo, err := o.provider.UserInfo(ctx, oauth2.StaticTokenSource(token))
+		if err != nil {
+			klog.Error(err)
+			return nil, err
+		}
+		userInfoClaims := make(map[string]interface{})
+		if err := userInfo.Claims(&userInfoClaims); err != nil {
+			return nil, fmt.Errorf("failed to decode userinfo claims: %v", err)
+		}
+		for k, v := range userInfoClaims {
+			claims[k] = v
+		}
+	}
+
+	preferredUsername, _ := claims[o.PreferredUsernameKey].(string)
+	email, _ := claims[o.EmailKey].(string)
+
+	return &oidcIdentity{
+		Sub:               idToken.Subject,
+		PreferredUsername: preferredUsername,
+		Email:             email,
+	}, nil
+}
@@ -0,0 +1,256 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/oauth"
+)
+
+const (
+	testClientID    = "horizon"
+	testCode        = "code"
+	testAccessToken = "access-token"
+	testKeyID       = "test-key"
+)
+
+// testServer is a stand-in OpenID provider, it exchanges testCode for an id token signed with its key.
+type testServer struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	claims jwt.MapClaims
+	// userInfo is served from the userinfo endpoint
+	userInfo map[string]interface{}
+}
+
+func newTestServer(t *testing.T) *testServer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	s := &testServer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"issuer":                                s.URL,
+			"authorization_endpoint":                s.URL + "/authorize",
+			"token_endpoint":                        s.URL + "/token",
+			"userinfo_endpoint":                     s.URL + "/userinfo",
+			"jwks_uri":                              s.URL + "/keys",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"keys": []map[string]interface{}{{
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"kid": testKeyID,
+				"n":   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("failed to parse token request: %v", err)
+		}
+		if r.Form.Get("code") != testCode {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, s.claims)
+		token.Header["kid"] = testKeyID
+		idToken, err := token.SignedString(key)
+		if err != nil {
+			t.Errorf("failed to sign id token: %v", err)
+		}
+		writeJSON(w, map[string]interface{}{
+			"access_token": testAccessToken,
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     idToken,
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+testAccessToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, s.userInfo)
+	})
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func TestOIDCIdentityExchangeCallback(t *testing.T) {
+	tests := []struct {
+		name         string
+		code         string
+		options      oauth.DynamicOptions
+		claims       func(issuer string) jwt.MapClaims
+		userInfo     map[string]interface{}
+		wantErr      bool
+		wantUserID   string
+		wantUsername string
+		wantEmail    string
+	}{
+		{
+			name: "identity from id token",
+			code: testCode,
+			claims: func(issuer string) jwt.MapClaims {
+				return validClaims(issuer, jwt.MapClaims{"preferred_username": "alice", "email": "alice@horizon.io"})
+			},
+			wantUserID:   "1024",
+			wantUsername: "alice",
+			wantEmail:    "alice@horizon.io",
+		},
+		{
+			name:    "identity from configured claims",
+			code:    testCode,
+			options: oauth.DynamicOptions{"preferredUsernameKey": "name", "emailKey": "mail"},
+			claims: func(issuer string) jwt.MapClaims {
+				return validClaims(issuer, jwt.MapClaims{"name": "bob", "mail": "bob@horizon.io"})
+			},
+			wantUserID:   "1024",
+			wantUsername: "bob",
+			wantEmail:    "bob@horizon.io",
+		},
+		{
+			name:    "identity completed from userinfo endpoint",
+			code:    testCode,
+			options: oauth.DynamicOptions{"getUserInfo": true},
+			claims: func(issuer string) jwt.MapClaims {
+				return validClaims(issuer, jwt.MapClaims{})
+			},
+			userInfo:     map[string]interface{}{"sub": "1024", "preferred_username": "carol", "email": "carol@horizon.io"},
+			wantUserID:   "1024",
+			wantUsername: "carol",
+			wantEmail:    "carol@horizon.io",
+		},
+		{
+			name:    "missing code",
+			code:    "",
+			claims:  func(issuer string) jwt.MapClaims { return validClaims(issuer, nil) },
+			wantErr: true,
+		},
+		{
+			name:    "invalid code",
+			code:    "invalid",
+			claims:  func(issuer string) jwt.MapClaims { return validClaims(issuer, nil) },
+			wantErr: true,
+		},
+		{
+			name: "id token issued to another client",
+			code: testCode,
+			claims: func(issuer string) jwt.MapClaims {
+				return validClaims(issuer, jwt.MapClaims{"aud": "another"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "expired id token",
+			code: testCode,
+			claims: func(issuer string) jwt.MapClaims {
+				return validClaims(issuer, jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()})
+			},
+			wantErr: true,
+		},
+		{
+			name: "id token from another issuer",
+			code: testCode,
+			claims: func(issuer string) jwt.MapClaims {
+				return validClaims(issuer, jwt.MapClaims{"iss": "https://another.io"})
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newTestServer(t)
+			defer server.Close()
+			server.claims = test.claims(server.URL)
+			server.userInfo = test.userInfo
+
+			options := oauth.DynamicOptions{
+				"issuer":       server.URL,
+				"clientID":     testClientID,
+				"clientSecret": "horizon",
+				"redirectURL":  "http://horizon.io/oauth/callback/oidc",
+			}
+			for k, v := range test.options {
+				options[k] = v
+			}
+
+			provider, err := (&oidcProviderFactory{}).Create(options)
+			if err != nil {
+				t.Fatalf("failed to create provider: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/oauth/callback/oidc?code="+test.code, nil)
+			identity, err := provider.IdentityExchangeCallback(req)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got identity %+v", identity)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if identity.GetUserID() != test.wantUserID {
+				t.Errorf("expected user id %q, got %q", test.wantUserID, identity.GetUserID())
+			}
+			if identity.GetUsername() != test.wantUsername {
+				t.Errorf("expected username %q, got %q", test.wantUsername, identity.GetUsername())
+			}
+			if identity.GetEmail() != test.wantEmail {
+				t.Errorf("expected email %q, got %q", test.wantEmail, identity.GetEmail())
+			}
+		})
+	}
+}
+
+func TestOIDCProviderFactoryDiscoveryFailure(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	if _, err := (&oidcProviderFactory{}).Create(oauth.DynamicOptions{"issuer": server.URL}); err == nil {
+		t.Fatal("expected error when the provider can not be discovered")
+	}
+}
+
+// validClaims returns the claims of a valid id token issued to testClientID, overridden by the given claims.
+func validClaims(issuer string, overrides jwt.MapClaims) jwt.MapClaims {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss": issuer,
+		"sub": "1024",
+		"aud": testClientID,
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+	for k, v := range overrides {
+		claims[k] = v
+	}
+	return claims
+}
@@ -0,0 +1,71 @@
+package oauth
+
+// ErrorType is the error code defined in https://tools.ietf.org/html/rfc6749#section-5.2
+type ErrorType string
+
+const (
+	// InvalidRequest the request is missing a required parameter, includes an
+	// unsupported parameter value (other than grant type), repeats a parameter,
+	// includes multiple credentials, or is otherwise malformed.
+	InvalidRequest ErrorType = "invalid_request"
+
+	// InvalidClient client authentication failed (e.g., unknown client, no
+	// client authentication included, or unsupported authentication method).
+	InvalidClient ErrorType = "invalid_client"
+
+	// InvalidGrant the provided authorization grant (e.g., authorization code,
+	// resource owner credentials) or refresh token is invalid, expired, revoked,
+	// does not match the redirection URI used in the authorization request, or
+	// was issued to another client.
+	InvalidGrant ErrorType = "invalid_grant"
+
+	// UnsupportedGrantType the authorization grant type is not supported by the
+	// authorization server.
+	UnsupportedGrantType ErrorType = "unsupported_grant_type"
+
+	// UnsupportedResponseType the authorization server does not support obtaining
+	// an authorization code using this method.
+	UnsupportedResponseType ErrorType = "unsupported_response_type"
+
+	// AccessDenied the resource owner or authorization server denied the request.
+	AccessDenied ErrorType = "access_denied"
+
+	// ServerError the authorization server encountered an unexpected condition
+	// that prevented it from fulfilling the request.
+	ServerError ErrorType = "server_error"
+)
+
+// Error wrapped OAuth error Response, for more details: https://tools.ietf.org/html/rfc6749#section-5.2
+type Error struct {
+	// Type is the error code
+	Type ErrorType `json:"error"`
+	// Description is human-readable ASCII text providing additional information
+	Description string `json:"error_description,omitempty"`
+}
+
+func NewError(errorType ErrorType, err error) *Error {
+	return &Error{
+		Type:        errorType,
+		Description: err.Error(),
+	}
+}
+
+func NewInvalidRequest(err error) *Error {
+	return NewError(InvalidRequest, err)
+}
+
+func NewInvalidClient(err error) *Error {
+	return NewError(InvalidClient, err)
+}
+
+func NewInvalidGrant(err error) *Error {
+	return NewError(InvalidGrant, err)
+}
+
+func NewServerError(err error) *Error {
+	return NewError(ServerError, err)
+}
+
+func (e *Error) Error() string {
+	return string(e.Type) + ": " + e.Description
+}
@@ -0,0 +1,200 @@
+package oauth
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/sunweiwe/horizon/pkg/utils/slice"
+)
+
+const (
+	// AllowAllRedirectURI allows the client to redirect to any uri
+	AllowAllRedirectURI = "*"
+
+	// ResponseTypeCode is the response type of the authorization code grant flow
+	ResponseTypeCode = "code"
+
+	GrantTypePassword          = "password"
+	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypeAuthorizationCode = "authorization_code"
+)
+
+// MappingMethod determines how identities from identity providers are mapped to users
+type MappingMethod string
+
+const (
+	// MappingMethodAuto is the default value, the user will be created automatically on first login
+	MappingMethodAuto MappingMethod = "auto"
+	// MappingMethodLookup looks up an existing user linked to the identity, but does not create users
+	MappingMethodLookup MappingMethod = "lookup"
+	// MappingMethodManual creates the user on first login, the user stays disabled until an administrator confirms it
+	MappingMethodManual MappingMethod = "manual"
+)
+
+var (
+	ErrorClientNotFound           = errors.New("the OAuth client was not found")
+	ErrorRedirectURLNotAllowed    = errors.New("redirect URL is not allowed")
+	ErrorIdentityProviderNotFound = errors.New("the identity provider was not found")
+)
+
+type Options struct {
+	// Register additional OAuth clients.
+	Clients []Client `json:"clients,omitempty" yaml:"clients,omitempty"`
+
+	// AccessTokenMaxAge control the lifetime of access tokens. The default lifetime is 2 hours.
+	// 0 means no expiration.
+	AccessTokenMaxAge time.Duration `json:"accessTokenMaxAge" yaml:"accessTokenMaxAge"`
+
+	// AccessTokenInactivityTimeout is the window after the access token expired in which
+	// the refresh token can still be used to obtain a new access token.
+	// 0 means refresh tokens never expire.
+	AccessTokenInactivityTimeout time.Duration `json:"accessTokenInactivityTimeout" yaml:"accessTokenInactivityTimeout"`
+
+	// IdentityProviders authenticate users with external identity providers, e.g. LDAP, OIDC and GitHub.
+	IdentityProviders []IdentityProviderOptions `json:"identityProviders,omitempty" yaml:"identityProviders,omitempty"`
+}
+
+type IdentityProviderOptions struct {
+	// The provider name, used as the callback path of OAuth providers.
+	Name string `json:"name" yaml:"name"`
+
+	// Defines how new identities are mapped to users when they login. Allowed values are:
+	//  - auto:   The default value. The user will be created automatically on first login.
+	//  - lookup: Looks up an existing user linked to the identity, but does not create users.
+	//  - manual: The user will be created on first login and disabled until an administrator confirms it.
+	MappingMethod MappingMethod `json:"mappingMethod" yaml:"mappingMethod"`
+
+	// The type of identity provider, e.g. LDAPIdentityProvider, OIDCIdentityProvider and GitHubIdentityProvider.
+	Type string `json:"type" yaml:"type"`
+
+	// The options of identity provider
+	Provider DynamicOptions `json:"provider" yaml:"provider"`
+}
+
+// DynamicOptions holds the provider specific options, which are decoded by the provider factory.
+type DynamicOptions map[string]interface{}
+
+type Client struct {
+	// The name of the OAuth client is used as the client_id parameter when making requests to <master>/oauth/authorize
+	// and <master>/oauth/token.
+	Name string `json:"name" yaml:"name,omitempty"`
+
+	// Secret is the unique secret associated with a client
+	Secret string `json:"-" yaml:"secret,omitempty"`
+
+	// RedirectURIs is the valid redirection URIs associated with a client
+	RedirectURIs []string `json:"redirectURIs,omitempty" yaml:"redirectURIs,omitempty"`
+
+	// AccessTokenMaxAge overrides the default access token max age for tokens granted to this client.
+	AccessTokenMaxAge *time.Duration `json:"accessTokenMaxAge,omitempty" yaml:"accessTokenMaxAge,omitempty"`
+
+	// AccessTokenInactivityTimeout overrides the default token
+	// inactivity timeout for tokens granted to this client.
+	AccessTokenInactivityTimeout *time.Duration `json:"accessTokenInactivityTimeout,omitempty" yaml:"accessTokenInactivityTimeout,omitempty"`
+}
+
+// Token represents the credentials used to authorize
+// the requests to access protected resources on the OAuth 2.0
+// provider's backend.
+type Token struct {
+	// AccessToken is the token that authorizes and authenticates
+	// the requests.
+	AccessToken string `json:"access_token"`
+
+	// TokenType is the type of token.
+	// The Type method returns either this or "Bearer", the default.
+	TokenType string `json:"token_type,omitempty"`
+
+	// RefreshToken is a token that's used by the application
+	// (as opposed to the user) to refresh the access token
+	// if it expires.
+	RefreshToken string `json:"refresh_token,omitempty"`
+
+	// ExpiresIn is the optional expiration second of the access token.
+	ExpiresIn int `json:"expires_in,omitempty"`
+}
+
+func (o *Options) OAuthClient(name string) (Client, error) {
+	for _, found := range o.Clients {
+		if found.Name == name {
+			return found, nil
+		}
+	}
+	return Client{}, ErrorClientNotFound
+}
+
+func (o *Options) IdentityProviderOptions(name string) (*IdentityProviderOptions, error) {
+	for _, found := range o.IdentityProviders {
+		if found.Name == name {
+			return &found, nil
+		}
+	}
+	return nil, ErrorIdentityProviderNotFound
+}
+
+func (c Client) anyRedirectAbleURI() []string {
+	uris := make([]string, 0)
+	for _, uri := range c.RedirectURIs {
+		_, err := url.Parse(uri)
+		if err == nil {
+			uris = append(uris, uri)
+		}
+	}
+	return uris
+}
+
+// ResolveRedirectURL returns the redirect url the authorization response should be sent to,
+// expectURL must be registered by the client unless the client allows any redirect uri.
+func (c Client) ResolveRedirectURL(expectURL string) (*url.URL, error) {
+	// RedirectURIs is empty
+	if len(c.RedirectURIs) == 0 {
+		return nil, ErrorRedirectURLNotAllowed
+	}
+	allowAllRedirectURI := false
+	for _, uri := range c.RedirectURIs {
+		if uri == AllowAllRedirectURI {
+			allowAllRedirectURI = true
+			break
+		}
+	}
+
+	redirectAbleURIs := c.anyRedirectAbleURI()
+
+	if expectURL == "" {
+		// Need to specify at least one RedirectURI
+		if len(redirectAbleURIs) > 0 {
+			return url.Parse(redirectAbleURIs[0])
+		} else {
+			return nil, ErrorRedirectURLNotAllowed
+		}
+	}
+	if allowAllRedirectURI || slice.HasString(redirectAbleURIs, expectURL) {
+		return url.Parse(expectURL)
+	}
+
+	return nil, ErrorRedirectURLNotAllowed
+}
+
+// AccessTokenLifetime returns the access token max age and inactivity timeout applied to the client.
+func (o *Options) AccessTokenLifetime(client Client) (time.Duration, time.Duration) {
+	maxAge := o.AccessTokenMaxAge
+	if client.AccessTokenMaxAge != nil {
+		maxAge = *client.AccessTokenMaxAge
+	}
+
+	inactivityTimeout := o.AccessTokenInactivityTimeout
+	if client.AccessTokenInactivityTimeout != nil {
+		inactivityTimeout = *client.AccessTokenInactivityTimeout
+	}
+
+	return maxAge, inactivityTimeout
+}
+
+func NewOptions() *Options {
+	return &Options{
+		Clients:                      make([]Client, 0),
+		AccessTokenMaxAge:            time.Hour * 2,
+		AccessTokenInactivityTimeout: time.Hour * 2,
+	}
+}
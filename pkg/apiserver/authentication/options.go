@@ -0,0 +1,91 @@
+package authentication
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/oauth"
+)
+
+const (
+	// TokenStoreMemory keeps the issued tokens in memory of hz-apiserver
+	TokenStoreMemory = "memory"
+	// TokenStoreSecret keeps the issued tokens in Secrets, which are shared between the replicas of hz-apiserver
+	TokenStoreSecret = "secret"
+)
+
+type Options struct {
+	// maximum failed login attempts allowed within AuthenticateRateLimiterDuration
+	AuthenticateRateLimiterMaxTries int `json:"authenticateRateLimiterMaxTries" yaml:"authenticateRateLimiterMaxTries"`
+
+	AuthenticateRateLimiterDuration time.Duration `json:"authenticateRateLimiterDuration" yaml:"authenticateRateLimiterDuration"`
+
+	// Token verification maximum time difference
+	MaximumClockSkew time.Duration `json:"maximumClockSkew" yaml:"maximumClockSkew"`
+
+	// retention login history, records beyond this amount will be deleted
+	LoginHistoryRetentionPeriod time.Duration `json:"loginHistoryRetentionPeriod" yaml:"loginHistoryRetentionPeriod"`
+
+	// allow multiple users login from different location at the same time
+	MultipleLogin bool `json:"multipleLogin" yaml:"multipleLogin"`
+
+	// secret to sign jwt token
+	JwtSecret string `json:"jwtSecret" yaml:"jwtSecret"`
+
+	// where the issued tokens are recorded, one of memory and secret
+	TokenStore string `json:"tokenStore" yaml:"tokenStore"`
+
+	// lifetime of the tokens in the kubeconfig generated for users, they are renewed halfway through
+	KubeconfigTokenMaxAge time.Duration `json:"kubeconfigTokenMaxAge" yaml:"kubeconfigTokenMaxAge"`
+
+	// options of the built-in OAuth2 authorization server
+	OAuthOptions *oauth.Options `json:"oauthOptions" yaml:"oauthOptions"`
+}
+
+func NewOptions() *Options {
+	return &Options{
+		AuthenticateRateLimiterMaxTries: 10,
+		AuthenticateRateLimiterDuration: 10 * time.Minute,
+		MaximumClockSkew:                10 * time.Second,
+		LoginHistoryRetentionPeriod:     7 * 24 * time.Hour,
+		MultipleLogin:                   false,
+		JwtSecret:                       "",
+		TokenStore:                      TokenStoreMemory,
+		KubeconfigTokenMaxAge:           7 * 24 * time.Hour,
+		OAuthOptions:                    oauth.NewOptions(),
+	}
+}
+
+// Validate makes sure the access tokens expire, they are rejected without expiration time
+func (o *Options) Validate() []error {
+	var errs []error
+	if o.KubeconfigTokenMaxAge <= 0 {
+		errs = append(errs, fmt.Errorf("kubeconfig token max age must be positive"))
+	}
+	if o.OAuthOptions == nil {
+		return errs
+	}
+	if o.OAuthOptions.AccessTokenMaxAge <= 0 {
+		errs = append(errs, fmt.Errorf("access token max age must be positive"))
+	}
+	for _, client := range o.OAuthOptions.Clients {
+		if client.AccessTokenMaxAge != nil && *client.AccessTokenMaxAge <= 0 {
+			errs = append(errs, fmt.Errorf("access token max age of OAuth client %s must be positive", client.Name))
+		}
+	}
+	return errs
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet, s *Options) {
+	fs.IntVar(&o.AuthenticateRateLimiterMaxTries, "authenticate-rate-limiter-max-retries", s.AuthenticateRateLimiterMaxTries, ""+
+		"Maximum number of failed login attempts allowed within authenticate-rate-limiter-duration.")
+	fs.DurationVar(&o.AuthenticateRateLimiterDuration, "authenticate-rate-limiter-duration", s.AuthenticateRateLimiterDuration, ""+
+		"Time window used to count failed login attempts.")
+	fs.BoolVar(&o.MultipleLogin, "multiple-login", s.MultipleLogin, "Allow multiple login with the same account, disable means only one user can login at the same time.")
+	fs.StringVar(&o.JwtSecret, "jwt-secret", s.JwtSecret, "Secret to sign jwt token, must not be empty.")
+	fs.StringVar(&o.TokenStore, "token-store", s.TokenStore, "Where the issued tokens are recorded, one of memory and secret.")
+	fs.DurationVar(&o.KubeconfigTokenMaxAge, "kubeconfig-token-max-age", s.KubeconfigTokenMaxAge, "Lifetime of the tokens in the kubeconfig generated for users.")
+	fs.DurationVar(&o.LoginHistoryRetentionPeriod, "login-history-retention-period", s.LoginHistoryRetentionPeriod, "login-history-retention-period defines how long login history should be kept.")
+	fs.DurationVar(&o.MaximumClockSkew, "maximum-clock-skew", s.MaximumClockSkew, "The maximum time difference between the system clocks of the hz-apiserver that issued a JWT and the hz-apiserver that verified the JWT.")
+}
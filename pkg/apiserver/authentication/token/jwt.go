@@ -0,0 +1,113 @@
+package token
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/klog/v2"
+)
+
+const (
+	DefaultIssuerName = "horizon"
+)
+
+type Claims struct {
+	Username  string              `json:"username"`
+	Groups    []string            `json:"groups,omitempty"`
+	Extra     map[string][]string `json:"extra,omitempty"`
+	TokenType TokenType           `json:"token_type"`
+	jwt.RegisteredClaims
+}
+
+type jwtTokenIssuer struct {
+	name   string
+	secret []byte
+	// Maximum time difference
+	maximumClockSkew time.Duration
+}
+
+func NewTokenIssuer(secret string, maximumClockSkew time.Duration) Issuer {
+	return &jwtTokenIssuer{
+		name:             DefaultIssuerName,
+		secret:           []byte(secret),
+		maximumClockSkew: maximumClockSkew,
+	}
+}
+
+func (s *jwtTokenIssuer) Verify(tokenString string) (user.Info, TokenType, error) {
+	clm := &Claims{}
+	// verify token signature and expiration time
+	_, err := jwt.ParseWithClaims(tokenString, clm, s.keyFunc)
+	if err != nil {
+		// time based claims are validated below with the maximum clock skew taken into account
+		timeErrors := jwt.ValidationErrorExpired | jwt.ValidationErrorNotValidYet | jwt.ValidationErrorIssuedAt
+		if validationErr, ok := err.(*jwt.ValidationError); !ok || validationErr.Errors&^timeErrors != 0 {
+			klog.V(4).Info(err)
+			return nil, "", err
+		}
+	}
+
+	if clm.Issuer != s.name {
+		return nil, "", fmt.Errorf("token issuer %q is not trusted", clm.Issuer)
+	}
+
+	// the access tokens are sent with every request, they must not be valid forever once leaked
+	if clm.TokenType == AccessToken && clm.ExpiresAt == nil {
+		return nil, "", fmt.Errorf("access token without expiration time is not allowed")
+	}
+
+	now := time.Now()
+	if clm.ExpiresAt != nil && now.Add(-s.maximumClockSkew).After(clm.ExpiresAt.Time) {
+		return nil, "", fmt.Errorf("token is expired")
+	}
+	if clm.NotBefore != nil && now.Add(s.maximumClockSkew).Before(clm.NotBefore.Time) {
+		return nil, "", fmt.Errorf("token is not valid yet")
+	}
+
+	return &user.DefaultInfo{Name: clm.Username, Groups: clm.Groups, Extra: clm.Extra}, clm.TokenType, nil
+}
+
+func (s *jwtTokenIssuer) IssueTo(user user.Info, tokenType TokenType, expiresIn time.Duration) (string, error) {
+	if tokenType == AccessToken && expiresIn <= 0 {
+		return "", fmt.Errorf("access token must expire")
+	}
+
+	issueAt := time.Now()
+	clm := &Claims{
+		Username:  user.GetName(),
+		Groups:    user.GetGroups(),
+		Extra:     user.GetExtra(),
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			// the tokens issued to the same user in the same second differ, so that they are revoked separately
+			ID:        string(uuid.NewUUID()),
+			IssuedAt:  jwt.NewNumericDate(issueAt),
+			Issuer:    s.name,
+			NotBefore: jwt.NewNumericDate(issueAt),
+		},
+	}
+
+	if expiresIn > 0 {
+		clm.ExpiresAt = jwt.NewNumericDate(issueAt.Add(expiresIn))
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, clm)
+
+	tokenString, err := token.SignedString(s.secret)
+	if err != nil {
+		klog.Error(err)
+		return "", err
+	}
+
+	return tokenString, nil
+}
+
+func (s *jwtTokenIssuer) keyFunc(token *jwt.Token) (i interface{}, err error) {
+	if method, ok := token.Method.(*jwt.SigningMethodHMAC); ok && method.Alg() == jwt.SigningMethodHS256.Alg() {
+		return s.secret, nil
+	}
+	return nil, fmt.Errorf("expect token signed with HS256 but got %v", token.Header["alg"])
+}
@@ -0,0 +1,73 @@
+package token
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryStore keeps the tokens in memory, it's not shared between the replicas of hz-apiserver
+type memoryStore struct {
+	sync.RWMutex
+	tokens map[string]map[string]time.Time
+}
+
+func NewMemoryStore() Store {
+	return &memoryStore{
+		tokens: make(map[string]map[string]time.Time),
+	}
+}
+
+func (m *memoryStore) Add(username string, token string, expiresIn time.Duration) error {
+	m.Lock()
+	defer m.Unlock()
+
+	tokens, ok := m.tokens[username]
+	if !ok {
+		tokens = make(map[string]time.Time)
+		m.tokens[username] = tokens
+	}
+
+	// prune the expired tokens
+	for key, t := range tokens {
+		if expired(t) {
+			delete(tokens, key)
+		}
+	}
+
+	tokens[tokenKey(token)] = expiresAt(expiresIn)
+	return nil
+}
+
+func (m *memoryStore) Exists(username string, token string) (bool, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	t, ok := m.tokens[username][tokenKey(token)]
+	return ok && !expired(t), nil
+}
+
+func (m *memoryStore) Consume(username string, token string) (bool, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	key := tokenKey(token)
+	t, ok := m.tokens[username][key]
+	delete(m.tokens[username], key)
+	return ok && !expired(t), nil
+}
+
+func (m *memoryStore) Revoke(username string, token string) error {
+	m.Lock()
+	defer m.Unlock()
+
+	delete(m.tokens[username], tokenKey(token))
+	return nil
+}
+
+func (m *memoryStore) RevokeAll(username string) error {
+	m.Lock()
+	defer m.Unlock()
+
+	delete(m.tokens, username)
+	return nil
+}
@@ -0,0 +1,212 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+)
+
+const (
+	SecretTypeUserTokens corev1.SecretType = "horizon.io/user-tokens"
+
+	userTokensSecretNameFormat = "horizon-user-tokens-%s"
+	usernameLabel              = "iam.horizon.io/user-ref"
+
+	// the tokens not found in the cache are looked up from the API server at most this rate,
+	// the tokens missing there too are not looked up again until the record expires
+	lookupQPS            = 10
+	lookupBurst          = 20
+	missingTokensSize    = 4096
+	missingTokensExpires = 10 * time.Minute
+)
+
+// secretStore keeps the tokens of each user in a Secret, the data key is the digest of the token
+// and the value is the unix time the token expires at, 0 means never.
+type secretStore struct {
+	client        kubernetes.Interface
+	secretLister  corev1listers.SecretLister
+	namespace     string
+	lookupLimiter flowcontrol.RateLimiter
+	missingTokens *cache.LRUExpireCache
+}
+
+func NewSecretStore(client kubernetes.Interface, secretLister corev1listers.SecretLister, namespace string) Store {
+	return &secretStore{
+		client:        client,
+		secretLister:  secretLister,
+		namespace:     namespace,
+		lookupLimiter: flowcontrol.NewTokenBucketRateLimiter(lookupQPS, lookupBurst),
+		missingTokens: cache.NewLRUExpireCache(missingTokensSize),
+	}
+}
+
+func (s *secretStore) Add(username string, token string, expiresIn time.Duration) error {
+	key := tokenKey(token)
+	value := []byte(strconv.FormatInt(unixTime(expiresAt(expiresIn)), 10))
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := s.client.CoreV1().Secrets(s.namespace).Get(context.Background(), secretName(username), metav1.GetOptions{})
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      secretName(username),
+					Namespace: s.namespace,
+					Labels:    map[string]string{usernameLabel: username},
+				},
+				Type: SecretTypeUserTokens,
+				Data: map[string][]byte{key: value},
+			}
+			_, err = s.client.CoreV1().Secrets(s.namespace).Create(context.Background(), secret, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				return apierrors.NewConflict(corev1.Resource("secrets"), secret.Name, err)
+			}
+			return err
+		}
+
+		secret = secret.DeepCopy()
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		// prune the expired tokens
+		for k, v := range secret.Data {
+			if expired(parseUnixTime(v)) {
+				delete(secret.Data, k)
+			}
+		}
+		secret.Data[key] = value
+		_, err = s.client.CoreV1().Secrets(s.namespace).Update(context.Background(), secret, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func (s *secretStore) Exists(username string, token string) (bool, error) {
+	key := tokenKey(token)
+
+	secret, err := s.secretLister.Secrets(s.namespace).Get(secretName(username))
+	if err != nil && !apierrors.IsNotFound(err) {
+		klog.Error(err)
+		return false, err
+	}
+
+	// the cache may not be synced with the token just issued, the tokens revoked or never issued are
+	// not found either, so the lookups are rate limited and the missing tokens are remembered
+	if err != nil || secret.Data[key] == nil {
+		missingKey := username + "/" + key
+		if _, ok := s.missingTokens.Get(missingKey); ok || !s.lookupLimiter.TryAccept() {
+			return false, nil
+		}
+		secret, err = s.client.CoreV1().Secrets(s.namespace).Get(context.Background(), secretName(username), metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.Error(err)
+			return false, err
+		}
+		if err != nil || secret.Data[key] == nil {
+			s.missingTokens.Add(missingKey, struct{}{}, missingTokensExpires)
+			return false, nil
+		}
+	}
+
+	value, ok := secret.Data[key]
+	return ok && !expired(parseUnixTime(value)), nil
+}
+
+func (s *secretStore) Consume(username string, token string) (bool, error) {
+	key := tokenKey(token)
+
+	consumed := false
+	// the update conflicts if the token is consumed concurrently, the retry finds it removed
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		consumed = false
+		secret, err := s.client.CoreV1().Secrets(s.namespace).Get(context.Background(), secretName(username), metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+
+		value, ok := secret.Data[key]
+		if !ok {
+			return nil
+		}
+
+		secret = secret.DeepCopy()
+		delete(secret.Data, key)
+		if _, err = s.client.CoreV1().Secrets(s.namespace).Update(context.Background(), secret, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		consumed = !expired(parseUnixTime(value))
+		return nil
+	})
+	if err != nil {
+		klog.Error(err)
+		return false, err
+	}
+	return consumed, nil
+}
+
+func (s *secretStore) Revoke(username string, token string) error {
+	key := tokenKey(token)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := s.client.CoreV1().Secrets(s.namespace).Get(context.Background(), secretName(username), metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+
+		if _, ok := secret.Data[key]; !ok {
+			return nil
+		}
+
+		secret = secret.DeepCopy()
+		delete(secret.Data, key)
+		_, err = s.client.CoreV1().Secrets(s.namespace).Update(context.Background(), secret, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func (s *secretStore) RevokeAll(username string) error {
+	err := s.client.CoreV1().Secrets(s.namespace).Delete(context.Background(), secretName(username), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		klog.Error(err)
+		return err
+	}
+	return nil
+}
+
+func secretName(username string) string {
+	return fmt.Sprintf(userTokensSecretNameFormat, username)
+}
+
+func unixTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func parseUnixTime(value []byte) time.Time {
+	sec, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil || sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
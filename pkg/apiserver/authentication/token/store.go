@@ -0,0 +1,40 @@
+package token
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Store records the tokens issued to users, tokens not found in the store are treated as revoked
+type Store interface {
+	// Add records the token issued to the user, the record expires after expiresIn, 0 means never
+	Add(username string, token string, expiresIn time.Duration) error
+	// Exists returns whether the token issued to the user is recorded and not expired
+	Exists(username string, token string) (bool, error)
+	// Consume removes the token issued to the user and returns whether it was recorded and not expired,
+	// so that a single-use token, e.g. an authorization code, is only accepted once
+	Consume(username string, token string) (bool, error)
+	// Revoke removes the token issued to the user
+	Revoke(username string, token string) error
+	// RevokeAll removes all the tokens issued to the user
+	RevokeAll(username string) error
+}
+
+// tokenKey returns the digest of the token, the raw token is never stored
+func tokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// expiresAt returns zero time if the token never expires
+func expiresAt(expiresIn time.Duration) time.Time {
+	if expiresIn <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(expiresIn)
+}
+
+func expired(expiresAt time.Time) bool {
+	return !expiresAt.IsZero() && time.Now().After(expiresAt)
+}
@@ -29,7 +29,114 @@ type AtrributesRecord struct {
 	ResourceRequest bool
 }
 
+func (a *AtrributesRecord) GetUser() user.Info {
+	return a.User
+}
+
+func (a *AtrributesRecord) GetVerb() string {
+	return a.Verb
+}
+
+func (a *AtrributesRecord) IsReadOnly() bool {
+	return a.Verb == "get" || a.Verb == "list" || a.Verb == "watch"
+}
+
+func (a *AtrributesRecord) GetCluster() string {
+	return a.Cluster
+}
+
+func (a *AtrributesRecord) GetWorkspace() string {
+	return a.Workspace
+}
+
+func (a *AtrributesRecord) GetNamespace() string {
+	return a.Namespace
+}
+
+func (a *AtrributesRecord) GetDevOps() string {
+	return a.DevOps
+}
+
+func (a *AtrributesRecord) GetResource() string {
+	return a.Resource
+}
+
+func (a *AtrributesRecord) GetSubresource() string {
+	return a.Subresource
+}
+
+func (a *AtrributesRecord) GetName() string {
+	return a.Name
+}
+
+func (a *AtrributesRecord) GetAPIGroup() string {
+	return a.APIGroup
+}
+
+func (a *AtrributesRecord) GetAPIVersion() string {
+	return a.APIVersion
+}
+
+func (a *AtrributesRecord) IsResourceRequest() bool {
+	return a.ResourceRequest
+}
+
+func (a *AtrributesRecord) GetPath() string {
+	return a.Path
+}
+
+func (a *AtrributesRecord) GetResourceScope() string {
+	return a.ResourceScope
+}
+
+// Attributes is an interface used by an Authorizer to get information about a request
+// that is used to make an authorization decision.
 type Attributes interface {
+	// GetUser returns the user.Info object to authorize
+	GetUser() user.Info
+
+	// GetVerb returns the kube verb associated with API requests (this includes get, list, watch, create, update, patch, delete, deletecollection, and proxy),
+	// or the lowercased HTTP verb associated with non-API requests (this includes get, put, post, patch, and delete)
+	GetVerb() string
+
+	// IsReadOnly returns true when the request has no side effects, based off its verb
+	IsReadOnly() bool
+
+	// GetCluster returns the cluster of the request
+	GetCluster() string
+
+	// GetWorkspace returns the workspace of the request
+	GetWorkspace() string
+
+	// GetNamespace returns the namespace of the request
+	GetNamespace() string
+
+	// GetDevOps returns the devops project of the request
+	GetDevOps() string
+
+	// GetResource returns the kind of object being requested, the scope of the resource is returned by GetResourceScope
+	GetResource() string
+
+	// GetSubresource returns the subresource being requested, if present
+	GetSubresource() string
+
+	// GetName returns the name of the object being requested, empty for list and create requests
+	GetName() string
+
+	// GetAPIGroup returns the API group of the resource being requested
+	GetAPIGroup() string
+
+	// GetAPIVersion returns the version of the group requested
+	GetAPIVersion() string
+
+	// IsResourceRequest returns true for requests to API resources and false for non-resource endpoints
+	IsResourceRequest() bool
+
+	// GetPath returns the path of the request
+	GetPath() string
+
+	// GetResourceScope returns the scope of the resource requested, one of Global, Cluster, Workspace, Namespace and DevOps
+	GetResourceScope() string
 }
 
 type Authorizer interface {
@@ -1 +1,56 @@
 package authorization
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	// RBAC evaluates the roles bound to the requesting user
+	RBAC = "RBAC"
+	// AlwaysAllow allows all requests, do not use it in production
+	AlwaysAllow = "AlwaysAllow"
+	// AlwaysDeny denies all requests except the always allowed paths
+	AlwaysDeny = "AlwaysDeny"
+)
+
+type Options struct {
+	// authorization mode, one of RBAC, AlwaysAllow and AlwaysDeny
+	Mode string `json:"mode" yaml:"mode"`
+
+	// non-resource paths allowed for everyone, including anonymous users,
+	// a trailing * matches every path with the given prefix
+	AlwaysAllowedPaths []string `json:"alwaysAllowedPaths,omitempty" yaml:"alwaysAllowedPaths,omitempty"`
+}
+
+func NewOptions() *Options {
+	return &Options{
+		Mode: RBAC,
+		AlwaysAllowedPaths: []string{
+			"/oauth/*",
+			"/healthz",
+			"/livez",
+			"/readyz",
+			"/version",
+			"/hapis/version",
+			"/metrics",
+		},
+	}
+}
+
+func (o *Options) Validate() []error {
+	var errs []error
+	switch o.Mode {
+	case RBAC, AlwaysAllow, AlwaysDeny:
+	default:
+		errs = append(errs, fmt.Errorf("authorization mode %s not supported, must be one of %s, %s and %s", o.Mode, RBAC, AlwaysAllow, AlwaysDeny))
+	}
+	return errs
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet, s *Options) {
+	fs.StringVar(&o.Mode, "authorization-mode", s.Mode, "Authorization mode, one of RBAC, AlwaysAllow and AlwaysDeny.")
+	fs.StringSliceVar(&o.AlwaysAllowedPaths, "authorization-always-allowed-paths", s.AlwaysAllowedPaths, ""+
+		"Non-resource paths that skip authorization, a trailing * matches every path with the given prefix.")
+}
@@ -1,19 +1,29 @@
 package rbac
 
 import (
+	"bytes"
 	"fmt"
+	"strings"
 
 	"github.com/sunweiwe/horizon/pkg/apiserver/authorization/authorizer"
+	"github.com/sunweiwe/horizon/pkg/apiserver/request"
 	"github.com/sunweiwe/horizon/pkg/models/iam/am"
+	"k8s.io/apiserver/pkg/authentication/user"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	rbacv1 "k8s.io/api/rbac/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 )
 
 type RBACAuthorizer struct {
 	am am.AccessManagementInterface
+	// clusterName is the name of the cluster the role bindings are listed from,
+	// the cluster and namespace role bindings only apply to the requests of this cluster.
+	clusterName string
 }
 
-func NewRBACAuthorizer(am am.AccessManagementInterface) *RBACAuthorizer {
-	return &RBACAuthorizer{am: am}
+func NewRBACAuthorizer(am am.AccessManagementInterface, clusterName string) *RBACAuthorizer {
+	return &RBACAuthorizer{am: am, clusterName: clusterName}
 }
 
 type authorizingVisitor struct {
@@ -24,16 +34,371 @@ type authorizingVisitor struct {
 	errors  []error
 }
 
+func (v *authorizingVisitor) visit(source fmt.Stringer, rule *rbacv1.PolicyRule, err error) bool {
+	if rule != nil && ruleAllows(v.requestAttributes, rule) {
+		v.allowed = true
+		v.reason = fmt.Sprintf("RBAC: allowed by %s", source.String())
+		return false
+	}
+	if err != nil {
+		v.errors = append(v.errors, err)
+	}
+	return true
+}
+
 func (r *RBACAuthorizer) Authorize(requestAttributes authorizer.Attributes) (authorizer.Decision, string, error) {
 	ruleCheckingVisitor := &authorizingVisitor{requestAttributes: requestAttributes}
 
+	r.visitRulesFor(requestAttributes, ruleCheckingVisitor.visit)
 	if ruleCheckingVisitor.allowed {
 		return authorizer.DecisionAllow, ruleCheckingVisitor.reason, nil
 	}
 
-	reason := ""
+	reason := fmt.Sprintf("RBAC: %s", describeDenial(requestAttributes))
 	if len(ruleCheckingVisitor.errors) > 0 {
-		reason = fmt.Sprintf("RBAC: %v", utilerrors.NewAggregate(ruleCheckingVisitor.errors))
+		reason = fmt.Sprintf("%s: %v", reason, utilerrors.NewAggregate(ruleCheckingVisitor.errors))
 	}
 	return authorizer.DecisionNoOpinion, reason, nil
 }
+
+// visitRulesFor visits the rules bound to the user and its groups in the scope of the request, from the
+// broadest scope to the narrowest, until the visitor returns false. Global roles apply to all the scopes,
+// workspace roles apply to the workspace and its namespaces, cluster roles apply to the cluster and its
+// namespaces, roles apply to their namespaces. Cluster roles and roles are only visited for the requests
+// of the local cluster, the bindings of other clusters are not known here.
+func (r *RBACAuthorizer) visitRulesFor(requestAttributes authorizer.Attributes, visitor func(source fmt.Stringer, rule *rbacv1.PolicyRule, err error) bool) {
+	u := requestAttributes.GetUser()
+	if u == nil {
+		return
+	}
+
+	globalRoleBindings, err := r.am.ListGlobalRoleBindings(u.GetName(), u.GetGroups())
+	if !visitor(nil, nil, err) {
+		return
+	}
+
+	// the groups of workspaces only apply to the bindings of their workspaces
+	globalGroups := r.am.FilterWorkspaceGroups(u.GetGroups(), "")
+	for _, globalRoleBinding := range globalRoleBindings {
+		describer := func(subject *rbacv1.Subject) fmt.Stringer {
+			return &globalRoleBindingDescriber{binding: globalRoleBinding, subject: subject}
+		}
+		if !r.visitBinding(u, globalGroups, globalRoleBinding.Subjects, globalRoleBinding.RoleRef, "", "", describer, visitor) {
+			return
+		}
+	}
+
+	scope := requestAttributes.GetResourceScope()
+	if scope == request.GlobalScope {
+		return
+	}
+
+	namespace := requestAttributes.GetNamespace()
+	if scope == request.DevOpsScope {
+		// devops projects are backed by namespaces
+		namespace = requestAttributes.GetDevOps()
+	}
+
+	workspace := requestAttributes.GetWorkspace()
+	if workspace == "" && namespace != "" {
+		workspace, err = r.am.GetNamespaceControlledWorkspace(namespace)
+		if !visitor(nil, nil, err) {
+			return
+		}
+	}
+
+	if workspace != "" {
+		workspaceRoleBindings, err := r.am.ListWorkspaceRoleBindings(u.GetName(), u.GetGroups(), workspace)
+		if !visitor(nil, nil, err) {
+			return
+		}
+
+		workspaceGroups := r.am.FilterWorkspaceGroups(u.GetGroups(), workspace)
+		for _, workspaceRoleBinding := range workspaceRoleBindings {
+			describer := func(subject *rbacv1.Subject) fmt.Stringer {
+				return &workspaceRoleBindingDescriber{workspace: workspace, binding: workspaceRoleBinding, subject: subject}
+			}
+			if !r.visitBinding(u, workspaceGroups, workspaceRoleBinding.Subjects, workspaceRoleBinding.RoleRef, workspace, "", describer, visitor) {
+				return
+			}
+		}
+	}
+
+	if scope == request.WorkspaceScope || !r.isLocalCluster(requestAttributes.GetCluster()) {
+		return
+	}
+
+	clusterRoleBindings, err := r.am.ListClusterRoleBindings(u.GetName(), u.GetGroups())
+	if !visitor(nil, nil, err) {
+		return
+	}
+
+	for _, clusterRoleBinding := range clusterRoleBindings {
+		describer := func(subject *rbacv1.Subject) fmt.Stringer {
+			return &clusterRoleBindingDescriber{cluster: requestAttributes.GetCluster(), binding: clusterRoleBinding, subject: subject}
+		}
+		if !r.visitBinding(u, globalGroups, clusterRoleBinding.Subjects, clusterRoleBinding.RoleRef, "", "", describer, visitor) {
+			return
+		}
+	}
+
+	if namespace == "" || (scope != request.NamespaceScope && scope != request.DevOpsScope) {
+		return
+	}
+
+	roleBindings, err := r.am.ListRoleBindings(u.GetName(), u.GetGroups(), namespace)
+	if !visitor(nil, nil, err) {
+		return
+	}
+
+	namespaceWorkspace := workspace
+	if requestAttributes.GetWorkspace() != "" {
+		namespaceWorkspace, err = r.am.GetNamespaceControlledWorkspace(namespace)
+		if !visitor(nil, nil, err) {
+			return
+		}
+	}
+
+	namespaceGroups := r.am.FilterWorkspaceGroups(u.GetGroups(), namespaceWorkspace)
+	for _, roleBinding := range roleBindings {
+		describer := func(subject *rbacv1.Subject) fmt.Stringer {
+			return &roleBindingDescriber{binding: roleBinding, subject: subject}
+		}
+		if !r.visitBinding(u, namespaceGroups, roleBinding.Subjects, roleBinding.RoleRef, "", namespace, describer, visitor) {
+			return
+		}
+	}
+}
+
+// isLocalCluster returns whether the requested cluster is the cluster the role bindings are listed from,
+// the requests without cluster target the local cluster.
+func (r *RBACAuthorizer) isLocalCluster(cluster string) bool {
+	return cluster == "" || cluster == r.clusterName
+}
+
+// visitBinding visits the rules of the role referenced by the binding if the binding applies to the user or
+// the groups applying in the scope of the binding, the rules are described by the binding and the subject
+// it applies by. It returns false if the visitor stopped.
+func (r *RBACAuthorizer) visitBinding(u user.Info, groups []string, subjects []rbacv1.Subject, roleRef rbacv1.RoleRef, workspace string, namespace string,
+	describe func(subject *rbacv1.Subject) fmt.Stringer, visitor func(source fmt.Stringer, rule *rbacv1.PolicyRule, err error) bool) bool {
+	subjectIndex, applies := am.AppliesTo(subjects, u.GetName(), groups, namespace)
+	if !applies {
+		return true
+	}
+
+	rules, err := r.am.GetRoleReferenceRules(roleRef, workspace, namespace)
+	if err != nil {
+		return visitor(nil, nil, err)
+	}
+
+	describer := describe(&subjects[subjectIndex])
+	for i := range rules {
+		if !visitor(describer, &rules[i], nil) {
+			return false
+		}
+	}
+	return true
+}
+
+func ruleAllows(requestAttributes authorizer.Attributes, rule *rbacv1.PolicyRule) bool {
+	if requestAttributes.IsResourceRequest() {
+		combinedResource := requestAttributes.GetResource()
+		if len(requestAttributes.GetSubresource()) > 0 {
+			combinedResource = requestAttributes.GetResource() + "/" + requestAttributes.GetSubresource()
+		}
+
+		return verbMatches(rule, requestAttributes.GetVerb()) &&
+			apiGroupMatches(rule, requestAttributes.GetAPIGroup()) &&
+			resourceMatches(rule, combinedResource, requestAttributes.GetSubresource()) &&
+			resourceNameMatches(rule, requestAttributes.GetName())
+	}
+
+	return verbMatches(rule, requestAttributes.GetVerb()) &&
+		nonResourceURLMatches(rule, requestAttributes.GetPath())
+}
+
+func verbMatches(rule *rbacv1.PolicyRule, requestedVerb string) bool {
+	for _, ruleVerb := range rule.Verbs {
+		if ruleVerb == rbacv1.VerbAll || ruleVerb == requestedVerb {
+			return true
+		}
+	}
+	return false
+}
+
+func apiGroupMatches(rule *rbacv1.PolicyRule, requestedGroup string) bool {
+	for _, ruleGroup := range rule.APIGroups {
+		if ruleGroup == rbacv1.APIGroupAll || ruleGroup == requestedGroup {
+			return true
+		}
+	}
+	return false
+}
+
+func resourceMatches(rule *rbacv1.PolicyRule, combinedRequestedResource, requestedSubresource string) bool {
+	for _, ruleResource := range rule.Resources {
+		// if everything is allowed, we match
+		if ruleResource == rbacv1.ResourceAll {
+			return true
+		}
+		// if we have an exact match, we match
+		if ruleResource == combinedRequestedResource {
+			return true
+		}
+
+		// We can also match a */subresource.
+		// if there isn't a subresource, then continue
+		if len(requestedSubresource) == 0 {
+			continue
+		}
+		// if the rule isn't in the format */subresource, then we don't match, continue
+		if len(ruleResource) == len(requestedSubresource)+2 &&
+			strings.HasPrefix(ruleResource, "*/") &&
+			strings.HasSuffix(ruleResource, requestedSubresource) {
+			return true
+		}
+	}
+	return false
+}
+
+func resourceNameMatches(rule *rbacv1.PolicyRule, requestedName string) bool {
+	if len(rule.ResourceNames) == 0 {
+		return true
+	}
+	for _, ruleName := range rule.ResourceNames {
+		if ruleName == requestedName {
+			return true
+		}
+	}
+	return false
+}
+
+func nonResourceURLMatches(rule *rbacv1.PolicyRule, requestedURL string) bool {
+	for _, ruleURL := range rule.NonResourceURLs {
+		if ruleURL == rbacv1.NonResourceAll {
+			return true
+		}
+		if ruleURL == requestedURL {
+			return true
+		}
+		if strings.HasSuffix(ruleURL, "*") && strings.HasPrefix(requestedURL, strings.TrimRight(ruleURL, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeDenial returns a readable description of the request that was not allowed
+func describeDenial(requestAttributes authorizer.Attributes) string {
+	username := ""
+	if u := requestAttributes.GetUser(); u != nil {
+		username = u.GetName()
+	}
+
+	if !requestAttributes.IsResourceRequest() {
+		return fmt.Sprintf("user %q cannot %s path %q", username, requestAttributes.GetVerb(), requestAttributes.GetPath())
+	}
+
+	resource := requestAttributes.GetResource()
+	if requestAttributes.GetSubresource() != "" {
+		resource = resource + "/" + requestAttributes.GetSubresource()
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "user %q cannot %s resource %q in API group %q", username, requestAttributes.GetVerb(), resource, requestAttributes.GetAPIGroup())
+	if requestAttributes.GetName() != "" {
+		fmt.Fprintf(&b, " with name %q", requestAttributes.GetName())
+	}
+
+	switch requestAttributes.GetResourceScope() {
+	case request.GlobalScope:
+		b.WriteString(" at the global scope")
+	case request.WorkspaceScope:
+		fmt.Fprintf(&b, " in the workspace %q", requestAttributes.GetWorkspace())
+	case request.DevOpsScope:
+		fmt.Fprintf(&b, " in the devops project %q", requestAttributes.GetDevOps())
+	case request.NamespaceScope:
+		fmt.Fprintf(&b, " in the namespace %q", requestAttributes.GetNamespace())
+	default:
+		b.WriteString(" at the cluster scope")
+	}
+	if requestAttributes.GetCluster() != "" {
+		fmt.Fprintf(&b, " of cluster %q", requestAttributes.GetCluster())
+	}
+	return b.String()
+}
+
+type globalRoleBindingDescriber struct {
+	binding *iamv1alpha2.GlobalRoleBinding
+	subject *rbacv1.Subject
+}
+
+func (d *globalRoleBindingDescriber) String() string {
+	return fmt.Sprintf("GlobalRoleBinding %q of %s %q to %s",
+		d.binding.Name,
+		d.binding.RoleRef.Kind,
+		d.binding.RoleRef.Name,
+		describeSubject(d.subject, ""),
+	)
+}
+
+type workspaceRoleBindingDescriber struct {
+	workspace string
+	binding   *iamv1alpha2.WorkspaceRoleBinding
+	subject   *rbacv1.Subject
+}
+
+func (d *workspaceRoleBindingDescriber) String() string {
+	return fmt.Sprintf("WorkspaceRoleBinding %q of %s %q to %s in workspace %q",
+		d.binding.Name,
+		d.binding.RoleRef.Kind,
+		d.binding.RoleRef.Name,
+		describeSubject(d.subject, ""),
+		d.workspace,
+	)
+}
+
+type clusterRoleBindingDescriber struct {
+	cluster string
+	binding *rbacv1.ClusterRoleBinding
+	subject *rbacv1.Subject
+}
+
+func (d *clusterRoleBindingDescriber) String() string {
+	description := fmt.Sprintf("ClusterRoleBinding %q of %s %q to %s",
+		d.binding.Name,
+		d.binding.RoleRef.Kind,
+		d.binding.RoleRef.Name,
+		describeSubject(d.subject, ""),
+	)
+	if d.cluster != "" {
+		description = fmt.Sprintf("%s in cluster %q", description, d.cluster)
+	}
+	return description
+}
+
+type roleBindingDescriber struct {
+	binding *rbacv1.RoleBinding
+	subject *rbacv1.Subject
+}
+
+func (d *roleBindingDescriber) String() string {
+	return fmt.Sprintf("RoleBinding %q of %s %q to %s",
+		d.binding.Name+"/"+d.binding.Namespace,
+		d.binding.RoleRef.Kind,
+		d.binding.RoleRef.Name,
+		describeSubject(d.subject, d.binding.Namespace),
+	)
+}
+
+func describeSubject(s *rbacv1.Subject, bindingNamespace string) string {
+	switch s.Kind {
+	case rbacv1.ServiceAccountKind:
+		if len(s.Namespace) > 0 {
+			return fmt.Sprintf("%s %q", s.Kind, s.Name+"/"+s.Namespace)
+		}
+		return fmt.Sprintf("%s %q", s.Kind, s.Name+"/"+bindingNamespace)
+	default:
+		return fmt.Sprintf("%s %q", s.Kind, s.Name)
+	}
+}
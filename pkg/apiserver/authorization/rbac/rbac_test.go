@@ -0,0 +1,292 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/sunweiwe/horizon/pkg/apiserver/authorization/authorizer"
+	"github.com/sunweiwe/horizon/pkg/apiserver/request"
+	"github.com/sunweiwe/horizon/pkg/informers"
+	"github.com/sunweiwe/horizon/pkg/models/iam/am"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/client-go/kubernetes/fake"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	tenantv1alpha1 "github.com/sunweiwe/api/tenant/v1alpha1"
+	fakeclientset "github.com/sunweiwe/horizon/pkg/client/clientset/fake"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func userSubject(name string) rbacv1.Subject {
+	return rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: name}
+}
+
+func groupSubject(name string) rbacv1.Subject {
+	return rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: name}
+}
+
+func workspaceLabels(workspace string) map[string]string {
+	return map[string]string{tenantv1alpha1.WorkspaceLabel: workspace}
+}
+
+func newGlobalRoleBinding(name string, role string, subject rbacv1.Subject) *iamv1alpha2.GlobalRoleBinding {
+	return &iamv1alpha2.GlobalRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		RoleRef:    rbacv1.RoleRef{APIGroup: iamv1alpha2.SchemeGroupVersion.Group, Kind: iamv1alpha2.ResourceKindGlobalRole, Name: role},
+		Subjects:   []rbacv1.Subject{subject},
+	}
+}
+
+func newWorkspaceRoleBinding(workspace string, name string, role string, subject rbacv1.Subject) *iamv1alpha2.WorkspaceRoleBinding {
+	return &iamv1alpha2.WorkspaceRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: workspaceLabels(workspace)},
+		RoleRef:    rbacv1.RoleRef{APIGroup: iamv1alpha2.SchemeGroupVersion.Group, Kind: iamv1alpha2.ResourceKindWorkspaceRole, Name: role},
+		Subjects:   []rbacv1.Subject{subject},
+	}
+}
+
+func newRoleBinding(namespace string, name string, role string, subject rbacv1.Subject) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: role},
+		Subjects:   []rbacv1.Subject{subject},
+	}
+}
+
+// newTestAuthorizer returns the RBAC authorizer of the host cluster backed by the informers of the fake clientsets
+func newTestAuthorizer(t *testing.T, kubeObjects []runtime.Object, horizonObjects []runtime.Object) *RBACAuthorizer {
+	kubeClient := fake.NewSimpleClientset(kubeObjects...)
+	horizonClient := fakeclientset.NewSimpleClientset(horizonObjects...)
+	factory := informers.NewInformerFactories(kubeClient, horizonClient)
+
+	rbacAuthorizer := NewRBACAuthorizer(am.NewReadOnlyOperator(factory), "host")
+
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	factory.Start(stopCh)
+	factory.KubernetesSharedInformerFactory().WaitForCacheSync(stopCh)
+	factory.HorizonSharedInformerFactory().WaitForCacheSync(stopCh)
+
+	return rbacAuthorizer
+}
+
+func TestRBACAuthorizer(t *testing.T) {
+	podsRule := func(verbs ...string) []rbacv1.PolicyRule {
+		return []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: verbs}}
+	}
+
+	kubeObjects := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1", Labels: workspaceLabels("ws1")}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2", Labels: workspaceLabels("ws2")}},
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "nodes-viewer"},
+			Rules:      []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"get"}}},
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "erin-nodes-viewer"},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "nodes-viewer"},
+			Subjects:   []rbacv1.Subject{userSubject("erin")},
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "ws1-devs-nodes-viewer"},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "nodes-viewer"},
+			Subjects:   []rbacv1.Subject{groupSubject("ws1-devs")},
+		},
+		&rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pods-editor"}, Rules: podsRule("create")},
+		&rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "pods-editor"}, Rules: podsRule("create")},
+		newRoleBinding("ns1", "frank-pods-editor", "pods-editor", userSubject("frank")),
+		newRoleBinding("ns1", "ws1-devs-pods-editor", "pods-editor", groupSubject("ws1-devs")),
+		newRoleBinding("ns2", "ws1-devs-pods-editor", "pods-editor", groupSubject("ws1-devs")),
+	}
+
+	horizonObjects := []runtime.Object{
+		&iamv1alpha2.Group{ObjectMeta: metav1.ObjectMeta{Name: "platform-viewers"}},
+		&iamv1alpha2.Group{ObjectMeta: metav1.ObjectMeta{Name: "ws1-devs", Labels: workspaceLabels("ws1")}},
+		&iamv1alpha2.GlobalRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "users-viewer"},
+			Rules:      []rbacv1.PolicyRule{{APIGroups: []string{iamv1alpha2.SchemeGroupVersion.Group}, Resources: []string{"users"}, Verbs: []string{"get", "list"}}},
+		},
+		&iamv1alpha2.GlobalRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform-admin"},
+			Rules:      []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}},
+		},
+		newGlobalRoleBinding("admin-platform-admin", "platform-admin", userSubject("admin")),
+		newGlobalRoleBinding("platform-viewers-users-viewer", "users-viewer", groupSubject("platform-viewers")),
+		newGlobalRoleBinding("ws1-devs-users-viewer", "users-viewer", groupSubject("ws1-devs")),
+		&iamv1alpha2.WorkspaceRole{ObjectMeta: metav1.ObjectMeta{Name: "ws1-viewer", Labels: workspaceLabels("ws1")}, Rules: podsRule("get", "list")},
+		&iamv1alpha2.WorkspaceRole{ObjectMeta: metav1.ObjectMeta{Name: "ws2-viewer", Labels: workspaceLabels("ws2")}, Rules: podsRule("get", "list")},
+		newWorkspaceRoleBinding("ws1", "alice-ws1-viewer", "ws1-viewer", userSubject("alice")),
+		newWorkspaceRoleBinding("ws1", "ws1-devs-ws1-viewer", "ws1-viewer", groupSubject("ws1-devs")),
+		newWorkspaceRoleBinding("ws2", "ws1-devs-ws2-viewer", "ws2-viewer", groupSubject("ws1-devs")),
+		// the role of ws1 referenced by a binding of ws2
+		newWorkspaceRoleBinding("ws2", "dave-ws1-viewer", "ws1-viewer", userSubject("dave")),
+	}
+
+	usersRequest := func(u user.Info) *authorizer.AtrributesRecord {
+		return &authorizer.AtrributesRecord{
+			User: u, Verb: "list", APIGroup: iamv1alpha2.SchemeGroupVersion.Group, Resource: "users",
+			ResourceScope: request.GlobalScope, ResourceRequest: true,
+		}
+	}
+	workspacePodsRequest := func(u user.Info, workspace string) *authorizer.AtrributesRecord {
+		return &authorizer.AtrributesRecord{
+			User: u, Verb: "list", Workspace: workspace, Resource: "pods",
+			ResourceScope: request.WorkspaceScope, ResourceRequest: true,
+		}
+	}
+	nodesRequest := func(u user.Info, cluster string) *authorizer.AtrributesRecord {
+		return &authorizer.AtrributesRecord{
+			User: u, Verb: "get", Cluster: cluster, Resource: "nodes",
+			ResourceScope: request.ClusterScope, ResourceRequest: true,
+		}
+	}
+	namespacePodsRequest := func(u user.Info, verb string, namespace string) *authorizer.AtrributesRecord {
+		return &authorizer.AtrributesRecord{
+			User: u, Verb: verb, Namespace: namespace, Resource: "pods",
+			ResourceScope: request.NamespaceScope, ResourceRequest: true,
+		}
+	}
+
+	admin := &user.DefaultInfo{Name: "admin"}
+	alice := &user.DefaultInfo{Name: "alice"}
+	bob := &user.DefaultInfo{Name: "bob", Groups: []string{"platform-viewers"}}
+	carol := &user.DefaultInfo{Name: "carol", Groups: []string{"ws1-devs"}}
+	dave := &user.DefaultInfo{Name: "dave"}
+	erin := &user.DefaultInfo{Name: "erin"}
+	frank := &user.DefaultInfo{Name: "frank"}
+
+	tests := []struct {
+		name       string
+		attributes authorizer.Attributes
+		want       authorizer.Decision
+	}{
+		{
+			name:       "global role applies to the global scope",
+			attributes: usersRequest(admin),
+			want:       authorizer.DecisionAllow,
+		},
+		{
+			name:       "global role applies to the namespaces",
+			attributes: namespacePodsRequest(admin, "delete", "ns2"),
+			want:       authorizer.DecisionAllow,
+		},
+		{
+			name:       "global role bound to a group",
+			attributes: usersRequest(bob),
+			want:       authorizer.DecisionAllow,
+		},
+		{
+			name:       "global role bound to a workspace group",
+			attributes: usersRequest(carol),
+			want:       authorizer.DecisionNoOpinion,
+		},
+		{
+			name:       "no bindings",
+			attributes: usersRequest(alice),
+			want:       authorizer.DecisionNoOpinion,
+		},
+		{
+			name:       "workspace role applies to the workspace",
+			attributes: workspacePodsRequest(alice, "ws1"),
+			want:       authorizer.DecisionAllow,
+		},
+		{
+			name:       "workspace role does not apply to other workspaces",
+			attributes: workspacePodsRequest(alice, "ws2"),
+			want:       authorizer.DecisionNoOpinion,
+		},
+		{
+			name:       "workspace role applies to the namespaces of the workspace",
+			attributes: namespacePodsRequest(alice, "list", "ns1"),
+			want:       authorizer.DecisionAllow,
+		},
+		{
+			name:       "workspace role does not apply to the namespaces of other workspaces",
+			attributes: namespacePodsRequest(alice, "list", "ns2"),
+			want:       authorizer.DecisionNoOpinion,
+		},
+		{
+			name:       "workspace role bound to the group of the workspace",
+			attributes: workspacePodsRequest(carol, "ws1"),
+			want:       authorizer.DecisionAllow,
+		},
+		{
+			name:       "workspace role bound to the group of another workspace",
+			attributes: workspacePodsRequest(carol, "ws2"),
+			want:       authorizer.DecisionNoOpinion,
+		},
+		{
+			name:       "workspace role of another workspace",
+			attributes: workspacePodsRequest(dave, "ws2"),
+			want:       authorizer.DecisionNoOpinion,
+		},
+		{
+			name:       "cluster role applies to the local cluster",
+			attributes: nodesRequest(erin, "host"),
+			want:       authorizer.DecisionAllow,
+		},
+		{
+			name:       "cluster role applies to the requests without cluster",
+			attributes: nodesRequest(erin, ""),
+			want:       authorizer.DecisionAllow,
+		},
+		{
+			name:       "cluster role does not apply to other clusters",
+			attributes: nodesRequest(erin, "member"),
+			want:       authorizer.DecisionNoOpinion,
+		},
+		{
+			name:       "cluster role bound to a workspace group",
+			attributes: nodesRequest(carol, "host"),
+			want:       authorizer.DecisionNoOpinion,
+		},
+		{
+			name:       "role applies to its namespace",
+			attributes: namespacePodsRequest(frank, "create", "ns1"),
+			want:       authorizer.DecisionAllow,
+		},
+		{
+			name:       "role does not allow other verbs",
+			attributes: namespacePodsRequest(frank, "delete", "ns1"),
+			want:       authorizer.DecisionNoOpinion,
+		},
+		{
+			name:       "role does not apply to other namespaces",
+			attributes: namespacePodsRequest(frank, "create", "ns2"),
+			want:       authorizer.DecisionNoOpinion,
+		},
+		{
+			name:       "role bound to the group of the workspace of the namespace",
+			attributes: namespacePodsRequest(carol, "create", "ns1"),
+			want:       authorizer.DecisionAllow,
+		},
+		{
+			name:       "role bound to the group of another workspace",
+			attributes: namespacePodsRequest(carol, "create", "ns2"),
+			want:       authorizer.DecisionNoOpinion,
+		},
+		{
+			name: "namespace requested in another workspace",
+			attributes: &authorizer.AtrributesRecord{
+				User: carol, Verb: "create", Workspace: "ws1", Namespace: "ns2", Resource: "pods",
+				ResourceScope: request.NamespaceScope, ResourceRequest: true,
+			},
+			want: authorizer.DecisionNoOpinion,
+		},
+	}
+
+	rbacAuthorizer := newTestAuthorizer(t, kubeObjects, horizonObjects)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			decision, reason, err := rbacAuthorizer.Authorize(test.attributes)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if decision != test.want {
+				t.Errorf("expected decision %v, got %v: %s", test.want, decision, reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package self
+
+import (
+	"github.com/sunweiwe/horizon/pkg/apiserver/authorization/authorizer"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+)
+
+// NewAuthorizer returns an authorizer which accepts the requests of the users to their own
+// records, e.g. reading their profile and login history or changing their password, which
+// can not be granted by the rules of a role. The authorizer has no opinion on all other requests.
+func NewAuthorizer() authorizer.Authorizer {
+	return authorizer.AuthorizerFunc(func(a authorizer.Attributes) (authorizer.Decision, string, error) {
+		u := a.GetUser()
+		if u == nil || !a.IsResourceRequest() {
+			return authorizer.DecisionNoOpinion, "", nil
+		}
+
+		if a.GetAPIGroup() != iamv1alpha2.SchemeGroupVersion.Group ||
+			a.GetResource() != iamv1alpha2.ResourcePluralUser ||
+			a.GetName() == "" || a.GetName() != u.GetName() {
+			return authorizer.DecisionNoOpinion, "", nil
+		}
+
+		switch a.GetSubresource() {
+		case "", "kubeconfig", iamv1alpha2.ResourcePluralLoginRecord:
+			if a.GetVerb() == "get" {
+				return authorizer.DecisionAllow, "", nil
+			}
+		case "password":
+			if a.GetVerb() == "update" {
+				return authorizer.DecisionAllow, "", nil
+			}
+		}
+
+		return authorizer.DecisionNoOpinion, "", nil
+	})
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authorization"
 	"github.com/sunweiwe/horizon/pkg/constants"
 	"github.com/sunweiwe/horizon/pkg/simple/client/k8s"
 	"github.com/sunweiwe/horizon/pkg/simple/client/monitoring/prometheus"
@@ -68,13 +70,19 @@ type Config struct {
 	MonitoringOptions *prometheus.Options `json:"monitoring,omitempty" yaml:"monitoring,omitempty" mapstructure:"monitoring"`
 
 	MultiClusterOptions *multicluster.Options `json:"multicluster,omitempty" yaml:"multicluster,omitempty" mapstructure:"multicluster"`
+
+	AuthenticationOptions *authentication.Options `json:"authentication,omitempty" yaml:"authentication,omitempty" mapstructure:"authentication"`
+
+	AuthorizationOptions *authorization.Options `json:"authorization,omitempty" yaml:"authorization,omitempty" mapstructure:"authorization"`
 }
 
 func New() *Config {
 	return &Config{
-		KubernetesOptions:   k8s.NewKubernetesClientOptions(),
-		MonitoringOptions:   prometheus.NewPrometheusOptions(),
-		MultiClusterOptions: multicluster.NewOptions(),
+		KubernetesOptions:     k8s.NewKubernetesClientOptions(),
+		MonitoringOptions:     prometheus.NewPrometheusOptions(),
+		MultiClusterOptions:   multicluster.NewOptions(),
+		AuthenticationOptions: authentication.NewOptions(),
+		AuthorizationOptions:  authorization.NewOptions(),
 	}
 }
 
@@ -0,0 +1,51 @@
+package filter
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/sunweiwe/horizon/pkg/apiserver/request"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/klog/v2"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8srequest "k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// WithAuthentication installs authentication handler to handler chain.
+// Authenticated user is stored in the request context, requests failed to
+// authenticate are rejected with 401 Unauthorized.
+func WithAuthentication(next http.Handler, authRequest authenticator.Request) http.Handler {
+	if authRequest == nil {
+		klog.Warningf("Authentication is disabled")
+		return next
+	}
+
+	s := serializer.NewCodecFactory(runtime.NewScheme()).WithoutConversion()
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		resp, ok, err := authRequest.AuthenticateRequest(req)
+		if err != nil || !ok {
+			if err != nil {
+				klog.Errorf("Unable to authenticate the request due to error: %v", err)
+			}
+
+			ctx := req.Context()
+			requestInfo, found := request.RequestInfoFrom(ctx)
+			if !found {
+				responsewriters.InternalError(w, req, fmt.Errorf("no RequestInfo found in the context"))
+				return
+			}
+
+			gv := schema.GroupVersion{Group: requestInfo.APIGroup, Version: requestInfo.APIVersion}
+			responsewriters.ErrorNegotiated(apierrors.NewUnauthorized(fmt.Sprintf("Unauthorized: %s", err)), s, gv, w, req)
+			return
+		}
+
+		req = req.WithContext(k8srequest.WithUser(req.Context(), resp.User))
+		next.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,84 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/sunweiwe/horizon/pkg/apiserver/authorization/authorizer"
+	"github.com/sunweiwe/horizon/pkg/apiserver/request"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/klog/v2"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8srequest "k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// WithAuthorization passes all authorized requests on to handler, and returns forbidden error otherwise.
+// It applies to the requests proxied to the Kubernetes apiserver as well, which are forwarded
+// with the privileged credentials of hz-apiserver.
+func WithAuthorization(next http.Handler, authorizers authorizer.Authorizer) http.Handler {
+	if authorizers == nil {
+		klog.Warningf("Authorization is disabled")
+		return next
+	}
+
+	s := serializer.NewCodecFactory(runtime.NewScheme()).WithoutConversion()
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		attributes, err := getAuthorizerAttributes(ctx)
+		if err != nil {
+			responsewriters.InternalError(w, req, err)
+			return
+		}
+
+		decision, reason, err := authorizers.Authorize(attributes)
+		if decision == authorizer.DecisionAllow {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		if err != nil {
+			klog.Errorf("Unable to authorize the request due to error: %v", err)
+		}
+		klog.V(4).Infof("Forbidden: %#v, Reason: %q", req.RequestURI, reason)
+
+		gv := schema.GroupVersion{Group: attributes.GetAPIGroup(), Version: attributes.GetAPIVersion()}
+		gr := schema.GroupResource{Group: attributes.GetAPIGroup(), Resource: attributes.GetResource()}
+		responsewriters.ErrorNegotiated(apierrors.NewForbidden(gr, attributes.GetName(), errors.New(reason)), s, gv, w, req)
+	})
+}
+
+func getAuthorizerAttributes(ctx context.Context) (authorizer.Attributes, error) {
+	requestInfo, found := request.RequestInfoFrom(ctx)
+	if !found {
+		return nil, fmt.Errorf("no RequestInfo found in the context")
+	}
+
+	attributes := &authorizer.AtrributesRecord{
+		Verb:            requestInfo.Verb,
+		Cluster:         requestInfo.Cluster,
+		Workspace:       requestInfo.Workspace,
+		Namespace:       requestInfo.Namespace,
+		DevOps:          requestInfo.DevOps,
+		APIGroup:        requestInfo.APIGroup,
+		APIVersion:      requestInfo.APIVersion,
+		Resource:        requestInfo.Resource,
+		Subresource:     requestInfo.Subresource,
+		Name:            requestInfo.Name,
+		Path:            requestInfo.Path,
+		ResourceScope:   requestInfo.ResourceScope,
+		ResourceRequest: requestInfo.IsResourceRequest,
+	}
+
+	if u, ok := k8srequest.UserFrom(ctx); ok {
+		attributes.User = u
+	}
+
+	return attributes, nil
+}
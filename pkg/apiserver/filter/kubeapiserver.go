@@ -4,21 +4,32 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/sunweiwe/horizon/pkg/apiserver/request"
+	"github.com/sunweiwe/horizon/pkg/models/iam/am"
 	"k8s.io/apimachinery/pkg/util/proxy"
 	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	k8srequest "k8s.io/apiserver/pkg/endpoints/request"
+	k8stransport "k8s.io/client-go/transport"
 )
 
 type kubeAPIProxy struct {
 	next          http.Handler
 	kubeAPIServer *url.URL
 	transport     http.RoundTripper
+	impersonation bool
+	am            am.AccessManagementInterface
 }
 
-func WithKubeAPIServer(next http.Handler, config *rest.Config) http.Handler {
+// WithKubeAPIServer proxies the requests to the Kubernetes apiserver with the credentials of horizon,
+// if impersonation is enabled, the requests are forwarded as the authenticated user. The groups of workspaces
+// are not forwarded, they only apply to the bindings of their workspaces.
+func WithKubeAPIServer(next http.Handler, config *rest.Config, impersonation bool, am am.AccessManagementInterface) http.Handler {
 	kubeAPIServer, _ := url.Parse(config.Host)
 	transport, err := rest.TransportFor(config)
 	if err != nil {
@@ -30,6 +41,8 @@ func WithKubeAPIServer(next http.Handler, config *rest.Config) http.Handler {
 		next:          next,
 		kubeAPIServer: kubeAPIServer,
 		transport:     transport,
+		impersonation: impersonation,
+		am:            am,
 	}
 }
 
@@ -46,17 +59,44 @@ func (k kubeAPIProxy) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		s.Scheme = k.kubeAPIServer.Scheme
 
 		req.Header.Del("Authorization")
+		// the impersonation headers from the clients must never be forwarded with the privileged credentials
+		removeImpersonationHeaders(req.Header)
+		transport := k.transport
+		if k.impersonation {
+			u, ok := k8srequest.UserFrom(req.Context())
+			if !ok {
+				responsewriters.InternalError(w, req, fmt.Errorf("no user found in the context"))
+				return
+			}
+			transport = k8stransport.NewImpersonatingRoundTripper(k8stransport.ImpersonationConfig{
+				UserName: u.GetName(),
+				Groups:   k.am.FilterWorkspaceGroups(u.GetGroups(), ""),
+				Extra:    u.GetExtra(),
+			}, k.transport)
+		}
+
 		httpProxy := proxy.NewUpgradeAwareHandler(
 			&s,
-			k.transport,
+			transport,
 			true,
 			false,
 			&responder{},
 		)
-		httpProxy.UpgradeTransport = proxy.NewUpgradeRequestRoundTripper(k.transport, k.transport)
+		httpProxy.UpgradeTransport = proxy.NewUpgradeRequestRoundTripper(transport, transport)
 		httpProxy.ServeHTTP(w, req)
 		return
 	}
 
 	k.next.ServeHTTP(w, req)
 }
+
+func removeImpersonationHeaders(header http.Header) {
+	for key := range header {
+		if strings.HasPrefix(key, authenticationv1.ImpersonateUserExtraHeaderPrefix) {
+			header.Del(key)
+		}
+	}
+	header.Del(authenticationv1.ImpersonateUserHeader)
+	header.Del(authenticationv1.ImpersonateGroupHeader)
+	header.Del(authenticationv1.ImpersonateUIDHeader)
+}
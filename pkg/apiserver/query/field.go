@@ -4,5 +4,17 @@ type Field string
 type Value string
 
 const (
-	FieldCreationTimeStamp = "creationTimestamp"
+	FieldName                = "name"
+	FieldNames               = "names"
+	FieldUID                 = "uid"
+	FieldCreationTimeStamp   = "creationTimestamp"
+	FieldCreateTime          = "createTime"
+	FieldLastUpdateTimestamp = "lastUpdateTimestamp"
+	FieldUpdateTime          = "updateTime"
+	FieldLabel               = "label"
+	FieldAnnotation          = "annotation"
+	FieldNamespace           = "namespace"
+	FieldStatus              = "status"
+	FieldOwnerReference      = "ownerReference"
+	FieldOwnerKind           = "ownerKind"
 )
@@ -1,10 +1,12 @@
 package query
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/emicklei/go-restful/v3"
 	"github.com/sunweiwe/horizon/pkg/utils/slice"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 const (
@@ -29,6 +31,11 @@ type Query struct {
 	LabelSelector string
 }
 
+type Filter struct {
+	Field Field
+	Value Value
+}
+
 type Pagination struct {
 	Limit int
 
@@ -37,6 +44,38 @@ type Pagination struct {
 
 var NoPagination = newPagination(-1, 0)
 
+// Selector returns the label selector of the query, invalid label selector matches nothing,
+// the queries parsed from requests are rejected by ParseQueryParameter if the selector is invalid
+func (q *Query) Selector() labels.Selector {
+	selector, err := labels.Parse(q.LabelSelector)
+	if err != nil {
+		return labels.Nothing()
+	}
+	return selector
+}
+
+// GetValidPagination returns the start and end index of the items in the page
+func (p *Pagination) GetValidPagination(total int) (startIndex, endIndex int) {
+	// no pagination
+	if p.Limit == NoPagination.Limit {
+		return 0, total
+	}
+
+	// out of range
+	if p.Limit < 0 || p.Offset < 0 || p.Offset > total {
+		return 0, 0
+	}
+
+	startIndex = p.Offset
+	endIndex = startIndex + p.Limit
+
+	if endIndex > total {
+		endIndex = total
+	}
+
+	return startIndex, endIndex
+}
+
 func newPagination(limit int, offset int) *Pagination {
 	return &Pagination{
 		Limit:  limit,
@@ -53,7 +92,8 @@ func New() *Query {
 	}
 }
 
-func ParseQueryParameter(request *restful.Request) *Query {
+// ParseQueryParameter parses the query of the request, returns error if the label selector is invalid
+func ParseQueryParameter(request *restful.Request) (*Query, error) {
 	query := New()
 
 	limit, err := strconv.Atoi(request.QueryParameter(ParameterLimit))
@@ -78,7 +118,10 @@ func ParseQueryParameter(request *restful.Request) *Query {
 		query.Ascending = ascending
 	}
 
-	query.LabelSelector = request.QueryParameter(ParameterFieldSelector)
+	query.LabelSelector = request.QueryParameter(ParameterLabelSelector)
+	if _, err := labels.Parse(query.LabelSelector); err != nil {
+		return nil, fmt.Errorf("invalid label selector %q: %v", query.LabelSelector, err)
+	}
 
 	for key, values := range request.Request.URL.Query() {
 		if !slice.HasString([]string{ParameterPage, ParameterLimit, ParameterOrderBy, ParameterAscending, ParameterLabelSelector}, key) {
@@ -88,7 +131,7 @@ func ParseQueryParameter(request *restful.Request) *Query {
 		}
 	}
 
-	return query
+	return query, nil
 }
 
 func defaultString(value, defaultValue string) string {
@@ -10,8 +10,9 @@ import (
 	"k8s.io/apimachinery/pkg/api/validation/path"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 
+	corev1 "k8s.io/api/core/v1"
 	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
 	metainternalversionscheme "k8s.io/apimachinery/pkg/apis/meta/internalversion/scheme"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,6 +34,16 @@ var specialVerbs = sets.New("proxy", "watch")
 var namespaceSubResources = sets.New("status", "finalize")
 var specialVerbsNoSubResources = sets.New("proxy")
 
+// terminalAPIGroup serves the exec and log of the pods over websocket on behalf of the Kubernetes apiserver
+const terminalAPIGroup = "terminal.horizon.io"
+
+// terminalPodSubresourceVerbs are the verbs the terminal requests to the subresources of pods are authorized
+// with, the same as the Kubernetes apiserver, so that the rules granting pods/exec and pods/log apply to them.
+var terminalPodSubresourceVerbs = map[string]string{
+	"exec": VerbCreate,
+	"log":  VerbGet,
+}
+
 type RequestInfoResolver interface {
 	NewRequestInfo(req *http.Request) (*RequestInfo, error)
 }
@@ -200,7 +211,15 @@ func (r *RequestInfoFactory) NewRequestInfo(req *http.Request) (*RequestInfo, er
 		requestInfo.Resource = requestInfo.Parts[0]
 	}
 
-	requestInfo.ResourceScope = r.resolveResourceScope(requestInfo)
+	if requestInfo.APIGroup == terminalAPIGroup && requestInfo.Resource == "pods" {
+		if verb, ok := terminalPodSubresourceVerbs[requestInfo.Subresource]; ok {
+			requestInfo.APIGroup = corev1.GroupName
+			requestInfo.APIVersion = corev1.SchemeGroupVersion.Version
+			requestInfo.Verb = verb
+		}
+	}
+
+	requestInfo.ResourceScope = r.ResolveResourceScope(requestInfo)
 
 	if len(requestInfo.Name) == 0 && requestInfo.Verb == VerbGet {
 		opts := metainternalversion.ListOptions{}
@@ -267,8 +286,12 @@ const (
 	workspaceSelectorPrefix = "horizon.io/workspace="
 )
 
-func (r *RequestInfoFactory) resolveResourceScope(request RequestInfo) string {
-	if r.isGlobalScopeResource(request.APIGroup, request.Resource) {
+// ResolveResourceScope returns the scope the request is authorized in, the resources in GlobalResources
+// are global unless they are requested in a workspace, the requests without workspace, namespace and
+// devops project are in the cluster scope.
+func (r *RequestInfoFactory) ResolveResourceScope(request RequestInfo) string {
+	// the requests to a named workspace, including the workspace itself, are authorized in the workspace
+	if request.Workspace == "" && r.isGlobalScopeResource(request.APIGroup, request.Resource) {
 		return GlobalScope
 	}
 
@@ -0,0 +1,297 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func newTestRequestInfoFactory() *RequestInfoFactory {
+	return &RequestInfoFactory{
+		APIPrefixes:          sets.New("api", "apis", "hapis", "hapi"),
+		GroupLessAPIPrefixes: sets.New("api", "hapi"),
+		GlobalResources: []schema.GroupResource{
+			{Group: "iam.horizon.io", Resource: "users"},
+			{Group: "tenant.horizon.io", Resource: "workspaces"},
+		},
+	}
+}
+
+func TestNewRequestInfo(t *testing.T) {
+	tests := []struct {
+		name            string
+		method          string
+		url             string
+		wantVerb        string
+		wantAPIGroup    string
+		wantResource    string
+		wantSubresource string
+		wantName        string
+		wantCluster     string
+		wantWorkspace   string
+		wantNamespace   string
+		wantDevOps      string
+		wantScope       string
+		wantKubernetes  bool
+	}{
+		{
+			name:         "global resource",
+			method:       http.MethodGet,
+			url:          "/hapis/iam.horizon.io/v1alpha2/users",
+			wantVerb:     VerbList,
+			wantAPIGroup: "iam.horizon.io",
+			wantResource: "users",
+			wantScope:    GlobalScope,
+		},
+		{
+			name:         "global resource by name",
+			method:       http.MethodDelete,
+			url:          "/hapis/iam.horizon.io/v1alpha2/users/alice",
+			wantVerb:     VerbDelete,
+			wantAPIGroup: "iam.horizon.io",
+			wantResource: "users",
+			wantName:     "alice",
+			wantScope:    GlobalScope,
+		},
+		{
+			name:         "workspaces",
+			method:       http.MethodGet,
+			url:          "/hapis/tenant.horizon.io/v1alpha2/workspaces",
+			wantVerb:     VerbList,
+			wantAPIGroup: "tenant.horizon.io",
+			wantResource: "workspaces",
+			wantScope:    GlobalScope,
+		},
+		{
+			name:          "named workspace",
+			method:        http.MethodGet,
+			url:           "/hapis/tenant.horizon.io/v1alpha2/workspaces/ws1",
+			wantVerb:      VerbGet,
+			wantAPIGroup:  "tenant.horizon.io",
+			wantResource:  "workspaces",
+			wantName:      "ws1",
+			wantWorkspace: "ws1",
+			wantScope:     WorkspaceScope,
+		},
+		{
+			name:          "resource of a workspace",
+			method:        http.MethodPost,
+			url:           "/hapis/iam.horizon.io/v1alpha2/workspaces/ws1/workspaceroles",
+			wantVerb:      VerbCreate,
+			wantAPIGroup:  "iam.horizon.io",
+			wantResource:  "workspaceroles",
+			wantWorkspace: "ws1",
+			wantScope:     WorkspaceScope,
+		},
+		{
+			name:          "global resource of a workspace",
+			method:        http.MethodGet,
+			url:           "/hapis/iam.horizon.io/v1alpha2/workspaces/ws1/users",
+			wantVerb:      VerbList,
+			wantAPIGroup:  "iam.horizon.io",
+			wantResource:  "users",
+			wantWorkspace: "ws1",
+			wantScope:     WorkspaceScope,
+		},
+		{
+			name:           "cluster resource",
+			method:         http.MethodGet,
+			url:            "/api/v1/nodes/node1",
+			wantVerb:       VerbGet,
+			wantResource:   "nodes",
+			wantName:       "node1",
+			wantScope:      ClusterScope,
+			wantKubernetes: true,
+		},
+		{
+			name:           "resource of another cluster",
+			method:         http.MethodGet,
+			url:            "/api/clusters/member/v1/nodes",
+			wantVerb:       VerbList,
+			wantResource:   "nodes",
+			wantCluster:    "member",
+			wantScope:      ClusterScope,
+			wantKubernetes: true,
+		},
+		{
+			name:           "namespaced resource",
+			method:         http.MethodPut,
+			url:            "/apis/apps/v1/namespaces/ns1/deployments/web",
+			wantVerb:       VerbUpdate,
+			wantAPIGroup:   "apps",
+			wantResource:   "deployments",
+			wantName:       "web",
+			wantNamespace:  "ns1",
+			wantScope:      NamespaceScope,
+			wantKubernetes: true,
+		},
+		{
+			name:           "namespace",
+			method:         http.MethodGet,
+			url:            "/api/v1/namespaces/ns1",
+			wantVerb:       VerbGet,
+			wantResource:   "namespaces",
+			wantName:       "ns1",
+			wantNamespace:  "ns1",
+			wantScope:      NamespaceScope,
+			wantKubernetes: true,
+		},
+		{
+			name:           "namespaced collection deleted",
+			method:         http.MethodDelete,
+			url:            "/api/v1/namespaces/ns1/pods",
+			wantVerb:       "deletecollection",
+			wantResource:   "pods",
+			wantNamespace:  "ns1",
+			wantScope:      NamespaceScope,
+			wantKubernetes: true,
+		},
+		{
+			name:           "list by name",
+			method:         http.MethodGet,
+			url:            "/api/v1/namespaces/ns1/pods?fieldSelector=metadata.name%3Dweb",
+			wantVerb:       VerbList,
+			wantResource:   "pods",
+			wantName:       "web",
+			wantNamespace:  "ns1",
+			wantScope:      NamespaceScope,
+			wantKubernetes: true,
+		},
+		{
+			name:           "watch the resources of a workspace",
+			method:         http.MethodGet,
+			url:            "/api/v1/namespaces?watch=true&labelSelector=horizon.io%2Fworkspace%3Dws1",
+			wantVerb:       VerbWatch,
+			wantResource:   "namespaces",
+			wantWorkspace:  "ws1",
+			wantScope:      WorkspaceScope,
+			wantKubernetes: true,
+		},
+		{
+			name:         "resource of a devops project",
+			method:       http.MethodGet,
+			url:          "/hapis/devops.horizon.io/v1alpha3/devops/project1/pipelines",
+			wantVerb:     VerbList,
+			wantAPIGroup: "devops.horizon.io",
+			wantResource: "pipelines",
+			wantDevOps:   "project1",
+			wantScope:    DevOpsScope,
+		},
+		{
+			name:            "terminal exec is authorized as pods/exec",
+			method:          http.MethodGet,
+			url:             "/hapis/terminal.horizon.io/v1alpha2/namespaces/ns1/pods/web/exec",
+			wantVerb:        VerbCreate,
+			wantResource:    "pods",
+			wantSubresource: "exec",
+			wantName:        "web",
+			wantNamespace:   "ns1",
+			wantScope:       NamespaceScope,
+		},
+		{
+			name:            "terminal log is authorized as pods/log",
+			method:          http.MethodGet,
+			url:             "/hapis/terminal.horizon.io/v1alpha2/namespaces/ns1/pods/web/log",
+			wantVerb:        VerbGet,
+			wantResource:    "pods",
+			wantSubresource: "log",
+			wantName:        "web",
+			wantNamespace:   "ns1",
+			wantScope:       NamespaceScope,
+		},
+		{
+			name:            "kubectl of the user",
+			method:          http.MethodGet,
+			url:             "/hapis/terminal.horizon.io/v1alpha2/users/alice/kubectl",
+			wantVerb:        VerbGet,
+			wantAPIGroup:    "terminal.horizon.io",
+			wantResource:    "users",
+			wantSubresource: "kubectl",
+			wantName:        "alice",
+			wantScope:       ClusterScope,
+		},
+	}
+
+	factory := newTestRequestInfoFactory()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.url, nil)
+			info, err := factory.NewRequestInfo(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !info.IsResourceRequest {
+				t.Errorf("expected a resource request")
+			}
+			if info.KubernetesRequest != test.wantKubernetes {
+				t.Errorf("expected kubernetes request %v, got %v", test.wantKubernetes, info.KubernetesRequest)
+			}
+			if info.Verb != test.wantVerb {
+				t.Errorf("expected verb %q, got %q", test.wantVerb, info.Verb)
+			}
+			if info.APIGroup != test.wantAPIGroup {
+				t.Errorf("expected API group %q, got %q", test.wantAPIGroup, info.APIGroup)
+			}
+			if info.Resource != test.wantResource {
+				t.Errorf("expected resource %q, got %q", test.wantResource, info.Resource)
+			}
+			if info.Subresource != test.wantSubresource {
+				t.Errorf("expected subresource %q, got %q", test.wantSubresource, info.Subresource)
+			}
+			if info.Name != test.wantName {
+				t.Errorf("expected name %q, got %q", test.wantName, info.Name)
+			}
+			if info.Cluster != test.wantCluster {
+				t.Errorf("expected cluster %q, got %q", test.wantCluster, info.Cluster)
+			}
+			if info.Workspace != test.wantWorkspace {
+				t.Errorf("expected workspace %q, got %q", test.wantWorkspace, info.Workspace)
+			}
+			if info.Namespace != test.wantNamespace {
+				t.Errorf("expected namespace %q, got %q", test.wantNamespace, info.Namespace)
+			}
+			if info.DevOps != test.wantDevOps {
+				t.Errorf("expected devops project %q, got %q", test.wantDevOps, info.DevOps)
+			}
+			if info.ResourceScope != test.wantScope {
+				t.Errorf("expected scope %q, got %q", test.wantScope, info.ResourceScope)
+			}
+		})
+	}
+}
+
+func TestNewRequestInfoNonResource(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		wantKubernetes bool
+	}{
+		{name: "kubernetes version", url: "/version", wantKubernetes: false},
+		{name: "kubernetes API discovery", url: "/apis", wantKubernetes: true},
+		{name: "horizon API discovery", url: "/hapis/iam.horizon.io", wantKubernetes: false},
+		{name: "oauth", url: "/oauth/authorize", wantKubernetes: false},
+	}
+
+	factory := newTestRequestInfoFactory()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			info, err := factory.NewRequestInfo(httptest.NewRequest(http.MethodGet, test.url, nil))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if info.IsResourceRequest {
+				t.Errorf("expected a non-resource request")
+			}
+			if info.KubernetesRequest != test.wantKubernetes {
+				t.Errorf("expected kubernetes request %v, got %v", test.wantKubernetes, info.KubernetesRequest)
+			}
+			if info.Verb != http.MethodGet {
+				t.Errorf("expected verb %q, got %q", http.MethodGet, info.Verb)
+			}
+		})
+	}
+}
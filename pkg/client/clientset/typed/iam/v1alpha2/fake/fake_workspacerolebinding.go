@@ -0,0 +1,120 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeWorkspaceRoleBindings implements WorkspaceRoleBindingInterface
+type FakeWorkspaceRoleBindings struct {
+	Fake *FakeIamV1alpha2
+}
+
+var workspacerolebindingsResource = v1alpha2.SchemeGroupVersion.WithResource("workspacerolebindings")
+
+var workspacerolebindingsKind = v1alpha2.SchemeGroupVersion.WithKind("WorkspaceRoleBinding")
+
+// Get takes name of the workspaceRoleBinding, and returns the corresponding workspaceRoleBinding object, and an error if there is any.
+func (c *FakeWorkspaceRoleBindings) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha2.WorkspaceRoleBinding, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(workspacerolebindingsResource, name), &v1alpha2.WorkspaceRoleBinding{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha2.WorkspaceRoleBinding), err
+}
+
+// List takes label and field selectors, and returns the list of WorkspaceRoleBindings that match those selectors.
+func (c *FakeWorkspaceRoleBindings) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha2.WorkspaceRoleBindingList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(workspacerolebindingsResource, workspacerolebindingsKind, opts), &v1alpha2.WorkspaceRoleBindingList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha2.WorkspaceRoleBindingList{ListMeta: obj.(*v1alpha2.WorkspaceRoleBindingList).ListMeta}
+	for _, item := range obj.(*v1alpha2.WorkspaceRoleBindingList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested workspaceRoleBindings.
+func (c *FakeWorkspaceRoleBindings) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(workspacerolebindingsResource, opts))
+}
+
+// Create takes the representation of a workspaceRoleBinding and creates it.  Returns the server's representation of the workspaceRoleBinding, and an error, if there is any.
+func (c *FakeWorkspaceRoleBindings) Create(ctx context.Context, workspaceRoleBinding *v1alpha2.WorkspaceRoleBinding, opts v1.CreateOptions) (result *v1alpha2.WorkspaceRoleBinding, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(workspacerolebindingsResource, workspaceRoleBinding), &v1alpha2.WorkspaceRoleBinding{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha2.WorkspaceRoleBinding), err
+}
+
+// Update takes the representation of a workspaceRoleBinding and updates it. Returns the server's representation of the workspaceRoleBinding, and an error, if there is any.
+func (c *FakeWorkspaceRoleBindings) Update(ctx context.Context, workspaceRoleBinding *v1alpha2.WorkspaceRoleBinding, opts v1.UpdateOptions) (result *v1alpha2.WorkspaceRoleBinding, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(workspacerolebindingsResource, workspaceRoleBinding), &v1alpha2.WorkspaceRoleBinding{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha2.WorkspaceRoleBinding), err
+}
+
+// Delete takes name of the workspaceRoleBinding and deletes it. Returns an error if one occurs.
+func (c *FakeWorkspaceRoleBindings) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteActionWithOptions(workspacerolebindingsResource, name, opts), &v1alpha2.WorkspaceRoleBinding{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeWorkspaceRoleBindings) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(workspacerolebindingsResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha2.WorkspaceRoleBindingList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched workspaceRoleBinding.
+func (c *FakeWorkspaceRoleBindings) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha2.WorkspaceRoleBinding, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(workspacerolebindingsResource, name, pt, data, subresources...), &v1alpha2.WorkspaceRoleBinding{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha2.WorkspaceRoleBinding), err
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha2
+
+import (
+	"context"
+	"time"
+
+	v1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	scheme "github.com/sunweiwe/horizon/pkg/client/clientset/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// GroupBindingsGetter has a method to return a GroupBindingInterface.
+// A group's client should implement this interface.
+type GroupBindingsGetter interface {
+	GroupBindings() GroupBindingInterface
+}
+
+// GroupBindingInterface has methods to work with GroupBinding resources.
+type GroupBindingInterface interface {
+	Create(ctx context.Context, groupBinding *v1alpha2.GroupBinding, opts v1.CreateOptions) (*v1alpha2.GroupBinding, error)
+	Update(ctx context.Context, groupBinding *v1alpha2.GroupBinding, opts v1.UpdateOptions) (*v1alpha2.GroupBinding, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha2.GroupBinding, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha2.GroupBindingList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha2.GroupBinding, err error)
+	GroupBindingExpansion
+}
+
+// groupBindings implements GroupBindingInterface
+type groupBindings struct {
+	client rest.Interface
+}
+
+// newGroupBindings returns a GroupBindings
+func newGroupBindings(c *IamV1alpha2Client) *groupBindings {
+	return &groupBindings{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the groupBinding, and returns the corresponding groupBinding object, and an error if there is any.
+func (c *groupBindings) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha2.GroupBinding, err error) {
+	result = &v1alpha2.GroupBinding{}
+	err = c.client.Get().
+		Resource("groupbindings").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of GroupBindings that match those selectors.
+func (c *groupBindings) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha2.GroupBindingList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha2.GroupBindingList{}
+	err = c.client.Get().
+		Resource("groupbindings").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested groupBindings.
+func (c *groupBindings) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("groupbindings").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a groupBinding and creates it.  Returns the server's representation of the groupBinding, and an error, if there is any.
+func (c *groupBindings) Create(ctx context.Context, groupBinding *v1alpha2.GroupBinding, opts v1.CreateOptions) (result *v1alpha2.GroupBinding, err error) {
+	result = &v1alpha2.GroupBinding{}
+	err = c.client.Post().
+		Resource("groupbindings").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(groupBinding).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a groupBinding and updates it. Returns the server's representation of the groupBinding, and an error, if there is any.
+func (c *groupBindings) Update(ctx context.Context, groupBinding *v1alpha2.GroupBinding, opts v1.UpdateOptions) (result *v1alpha2.GroupBinding, err error) {
+	result = &v1alpha2.GroupBinding{}
+	err = c.client.Put().
+		Resource("groupbindings").
+		Name(groupBinding.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(groupBinding).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the groupBinding and deletes it. Returns an error if one occurs.
+func (c *groupBindings) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("groupbindings").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *groupBindings) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("groupbindings").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched groupBinding.
+func (c *groupBindings) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha2.GroupBinding, err error) {
+	result = &v1alpha2.GroupBinding{}
+	err = c.client.Patch(pt).
+		Resource("groupbindings").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha2
+
+import (
+	"context"
+	"time"
+
+	v1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	scheme "github.com/sunweiwe/horizon/pkg/client/clientset/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// LoginRecordsGetter has a method to return a LoginRecordInterface.
+// A group's client should implement this interface.
+type LoginRecordsGetter interface {
+	LoginRecords() LoginRecordInterface
+}
+
+// LoginRecordInterface has methods to work with LoginRecord resources.
+type LoginRecordInterface interface {
+	Create(ctx context.Context, loginRecord *v1alpha2.LoginRecord, opts v1.CreateOptions) (*v1alpha2.LoginRecord, error)
+	Update(ctx context.Context, loginRecord *v1alpha2.LoginRecord, opts v1.UpdateOptions) (*v1alpha2.LoginRecord, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha2.LoginRecord, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha2.LoginRecordList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha2.LoginRecord, err error)
+	LoginRecordExpansion
+}
+
+// loginRecords implements LoginRecordInterface
+type loginRecords struct {
+	client rest.Interface
+}
+
+// newLoginRecords returns a LoginRecords
+func newLoginRecords(c *IamV1alpha2Client) *loginRecords {
+	return &loginRecords{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the loginRecord, and returns the corresponding loginRecord object, and an error if there is any.
+func (c *loginRecords) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha2.LoginRecord, err error) {
+	result = &v1alpha2.LoginRecord{}
+	err = c.client.Get().
+		Resource("loginrecords").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of LoginRecords that match those selectors.
+func (c *loginRecords) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha2.LoginRecordList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha2.LoginRecordList{}
+	err = c.client.Get().
+		Resource("loginrecords").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested loginRecords.
+func (c *loginRecords) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("loginrecords").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a loginRecord and creates it.  Returns the server's representation of the loginRecord, and an error, if there is any.
+func (c *loginRecords) Create(ctx context.Context, loginRecord *v1alpha2.LoginRecord, opts v1.CreateOptions) (result *v1alpha2.LoginRecord, err error) {
+	result = &v1alpha2.LoginRecord{}
+	err = c.client.Post().
+		Resource("loginrecords").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(loginRecord).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a loginRecord and updates it. Returns the server's representation of the loginRecord, and an error, if there is any.
+func (c *loginRecords) Update(ctx context.Context, loginRecord *v1alpha2.LoginRecord, opts v1.UpdateOptions) (result *v1alpha2.LoginRecord, err error) {
+	result = &v1alpha2.LoginRecord{}
+	err = c.client.Put().
+		Resource("loginrecords").
+		Name(loginRecord.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(loginRecord).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the loginRecord and deletes it. Returns an error if one occurs.
+func (c *loginRecords) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("loginrecords").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *loginRecords) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("loginrecords").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched loginRecord.
+func (c *loginRecords) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha2.LoginRecord, err error) {
+	result = &v1alpha2.LoginRecord{}
+	err = c.client.Patch(pt).
+		Resource("loginrecords").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
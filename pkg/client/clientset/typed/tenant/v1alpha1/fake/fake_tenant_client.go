@@ -31,6 +31,14 @@ func (c *FakeTenantV1alpha1) Workspaces() v1alpha1.WorkspaceInterface {
 	return &FakeWorkspaces{c}
 }
 
+func (c *FakeTenantV1alpha1) WorkspaceResourceQuotas() v1alpha1.WorkspaceResourceQuotaInterface {
+	return &FakeWorkspaceResourceQuotas{c}
+}
+
+func (c *FakeTenantV1alpha1) WorkspaceTemplates() v1alpha1.WorkspaceTemplateInterface {
+	return &FakeWorkspaceTemplates{c}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeTenantV1alpha1) RESTClient() rest.Interface {
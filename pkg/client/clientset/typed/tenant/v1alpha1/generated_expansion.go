@@ -18,3 +18,7 @@ limitations under the License.
 package v1alpha1
 
 type WorkspaceExpansion interface{}
+
+type WorkspaceResourceQuotaExpansion interface{}
+
+type WorkspaceTemplateExpansion interface{}
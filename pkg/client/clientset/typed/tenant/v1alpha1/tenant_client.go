@@ -28,6 +28,8 @@ import (
 type TenantV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	WorkspacesGetter
+	WorkspaceResourceQuotasGetter
+	WorkspaceTemplatesGetter
 }
 
 // TenantV1alpha1Client is used to interact with features provided by the tenant.horizon.io group.
@@ -39,6 +41,14 @@ func (c *TenantV1alpha1Client) Workspaces() WorkspaceInterface {
 	return newWorkspaces(c)
 }
 
+func (c *TenantV1alpha1Client) WorkspaceResourceQuotas() WorkspaceResourceQuotaInterface {
+	return newWorkspaceResourceQuotas(c)
+}
+
+func (c *TenantV1alpha1Client) WorkspaceTemplates() WorkspaceTemplateInterface {
+	return newWorkspaceTemplates(c)
+}
+
 // NewForConfig creates a new TenantV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
@@ -58,12 +58,30 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cluster().V1alpha1().Clusters().Informer()}, nil
 
 		// Group=iam.horizon.io, Version=v1alpha2
+	case v1alpha2.SchemeGroupVersion.WithResource("globalroles"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Iam().V1alpha2().GlobalRoles().Informer()}, nil
+	case v1alpha2.SchemeGroupVersion.WithResource("globalrolebindings"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Iam().V1alpha2().GlobalRoleBindings().Informer()}, nil
+	case v1alpha2.SchemeGroupVersion.WithResource("groups"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Iam().V1alpha2().Groups().Informer()}, nil
+	case v1alpha2.SchemeGroupVersion.WithResource("groupbindings"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Iam().V1alpha2().GroupBindings().Informer()}, nil
+	case v1alpha2.SchemeGroupVersion.WithResource("loginrecords"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Iam().V1alpha2().LoginRecords().Informer()}, nil
 	case v1alpha2.SchemeGroupVersion.WithResource("users"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Iam().V1alpha2().Users().Informer()}, nil
+	case v1alpha2.SchemeGroupVersion.WithResource("workspaceroles"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Iam().V1alpha2().WorkspaceRoles().Informer()}, nil
+	case v1alpha2.SchemeGroupVersion.WithResource("workspacerolebindings"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Iam().V1alpha2().WorkspaceRoleBindings().Informer()}, nil
 
 		// Group=tenant.horizon.io, Version=v1alpha1
 	case tenantv1alpha1.SchemeGroupVersion.WithResource("workspaces"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Tenant().V1alpha1().Workspaces().Informer()}, nil
+	case tenantv1alpha1.SchemeGroupVersion.WithResource("workspaceresourcequotas"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Tenant().V1alpha1().WorkspaceResourceQuotas().Informer()}, nil
+	case tenantv1alpha1.SchemeGroupVersion.WithResource("workspacetemplates"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Tenant().V1alpha1().WorkspaceTemplates().Informer()}, nil
 
 	}
 
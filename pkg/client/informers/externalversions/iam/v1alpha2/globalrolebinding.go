@@ -0,0 +1,88 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha2
+
+import (
+	"context"
+	time "time"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	clientset "github.com/sunweiwe/horizon/pkg/client/clientset"
+	internalinterfaces "github.com/sunweiwe/horizon/pkg/client/informers/externalversions/internalinterfaces"
+	v1alpha2 "github.com/sunweiwe/horizon/pkg/client/listers/iam/v1alpha2"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// GlobalRoleBindingInformer provides access to a shared informer and lister for
+// GlobalRoleBindings.
+type GlobalRoleBindingInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1alpha2.GlobalRoleBindingLister
+}
+
+type globalRoleBindingInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// NewGlobalRoleBindingInformer constructs a new informer for GlobalRoleBinding type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewGlobalRoleBindingInformer(client clientset.Interface, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredGlobalRoleBindingInformer(client, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredGlobalRoleBindingInformer constructs a new informer for GlobalRoleBinding type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredGlobalRoleBindingInformer(client clientset.Interface, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.IamV1alpha2().GlobalRoleBindings().List(context.TODO(), options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.IamV1alpha2().GlobalRoleBindings().Watch(context.TODO(), options)
+			},
+		},
+		&iamv1alpha2.GlobalRoleBinding{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *globalRoleBindingInformer) defaultInformer(client clientset.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredGlobalRoleBindingInformer(client, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *globalRoleBindingInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&iamv1alpha2.GlobalRoleBinding{}, f.defaultInformer)
+}
+
+func (f *globalRoleBindingInformer) Lister() v1alpha2.GlobalRoleBindingLister {
+	return v1alpha2.NewGlobalRoleBindingLister(f.Informer().GetIndexer())
+}
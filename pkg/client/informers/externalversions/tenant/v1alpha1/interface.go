@@ -25,6 +25,10 @@ import (
 type Interface interface {
 	// Workspaces returns a WorkspaceInformer.
 	Workspaces() WorkspaceInformer
+	// WorkspaceResourceQuotas returns a WorkspaceResourceQuotaInformer.
+	WorkspaceResourceQuotas() WorkspaceResourceQuotaInformer
+	// WorkspaceTemplates returns a WorkspaceTemplateInformer.
+	WorkspaceTemplates() WorkspaceTemplateInformer
 }
 
 type version struct {
@@ -42,3 +46,13 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (v *version) Workspaces() WorkspaceInformer {
 	return &workspaceInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
+
+// WorkspaceResourceQuotas returns a WorkspaceResourceQuotaInformer.
+func (v *version) WorkspaceResourceQuotas() WorkspaceResourceQuotaInformer {
+	return &workspaceResourceQuotaInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// WorkspaceTemplates returns a WorkspaceTemplateInformer.
+func (v *version) WorkspaceTemplates() WorkspaceTemplateInformer {
+	return &workspaceTemplateInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
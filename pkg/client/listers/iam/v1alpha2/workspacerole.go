@@ -0,0 +1,67 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha2
+
+import (
+	v1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// WorkspaceRoleLister helps list WorkspaceRoles.
+// All objects returned here must be treated as read-only.
+type WorkspaceRoleLister interface {
+	// List lists all WorkspaceRoles in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha2.WorkspaceRole, err error)
+	// Get retrieves the WorkspaceRole from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha2.WorkspaceRole, error)
+	WorkspaceRoleListerExpansion
+}
+
+// workspaceRoleLister implements the WorkspaceRoleLister interface.
+type workspaceRoleLister struct {
+	indexer cache.Indexer
+}
+
+// NewWorkspaceRoleLister returns a new WorkspaceRoleLister.
+func NewWorkspaceRoleLister(indexer cache.Indexer) WorkspaceRoleLister {
+	return &workspaceRoleLister{indexer: indexer}
+}
+
+// List lists all WorkspaceRoles in the indexer.
+func (s *workspaceRoleLister) List(selector labels.Selector) (ret []*v1alpha2.WorkspaceRole, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha2.WorkspaceRole))
+	})
+	return ret, err
+}
+
+// Get retrieves the WorkspaceRole from the index for a given name.
+func (s *workspaceRoleLister) Get(name string) (*v1alpha2.WorkspaceRole, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha2.Resource("workspacerole"), name)
+	}
+	return obj.(*v1alpha2.WorkspaceRole), nil
+}
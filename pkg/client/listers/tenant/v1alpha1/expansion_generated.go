@@ -20,3 +20,11 @@ package v1alpha1
 // WorkspaceListerExpansion allows custom methods to be added to
 // WorkspaceLister.
 type WorkspaceListerExpansion interface{}
+
+// WorkspaceResourceQuotaListerExpansion allows custom methods to be added to
+// WorkspaceResourceQuotaLister.
+type WorkspaceResourceQuotaListerExpansion interface{}
+
+// WorkspaceTemplateListerExpansion allows custom methods to be added to
+// WorkspaceTemplateLister.
+type WorkspaceTemplateListerExpansion interface{}
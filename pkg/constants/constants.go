@@ -12,4 +12,12 @@ const (
 	UserResourceTag = "User's Resources"
 
 	UserTag = "User"
+
+	GroupTag = "Group"
+
+	AccessManagementTag = "Access Management"
+
+	TerminalTag = "Terminal"
+
+	WorkspaceTag = "Workspace"
 )
@@ -0,0 +1,95 @@
+package cluster
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	clusterv1alpha1 "github.com/sunweiwe/api/cluster/v1alpha1"
+	horizon "github.com/sunweiwe/horizon/pkg/client/clientset"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// clientTimeout is the timeout of the requests to the member clusters, so that an unreachable
+// cluster does not block the workers of the controllers.
+const clientTimeout = 30 * time.Second
+
+// ClusterClients caches the clients of the member clusters built from their connections and
+// shares them among the controllers, the clients of a cluster are rebuilt once its kubeconfig changes.
+type ClusterClients struct {
+	mutex   sync.Mutex
+	clients map[string]*clusterClient
+}
+
+type clusterClient struct {
+	kubeConfig []byte
+	kubernetes kubernetes.Interface
+	horizon    horizon.Interface
+}
+
+func NewClusterClients() *ClusterClients {
+	return &ClusterClients{clients: make(map[string]*clusterClient)}
+}
+
+// Kubernetes returns the kubernetes client of the cluster.
+func (c *ClusterClients) Kubernetes(cluster *clusterv1alpha1.Cluster) (kubernetes.Interface, error) {
+	client, err := c.get(cluster)
+	if err != nil {
+		return nil, err
+	}
+	return client.kubernetes, nil
+}
+
+// Horizon returns the horizon client of the cluster.
+func (c *ClusterClients) Horizon(cluster *clusterv1alpha1.Cluster) (horizon.Interface, error) {
+	client, err := c.get(cluster)
+	if err != nil {
+		return nil, err
+	}
+	return client.horizon, nil
+}
+
+// Remove drops the clients of the cluster, e.g. once the cluster is deleted.
+func (c *ClusterClients) Remove(name string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.clients, name)
+}
+
+func (c *ClusterClients) get(cluster *clusterv1alpha1.Cluster) (*clusterClient, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if client, ok := c.clients[cluster.Name]; ok && bytes.Equal(client.kubeConfig, cluster.Spec.Connection.KubeConfig) {
+		return client, nil
+	}
+
+	config, err := ClusterConfig(cluster)
+	if err != nil {
+		return nil, err
+	}
+	config.Timeout = clientTimeout
+
+	// both clients share the transport of the cluster
+	httpClient, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return nil, err
+	}
+	kubernetesClient, err := kubernetes.NewForConfigAndClient(config, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	horizonClient, err := horizon.NewForConfigAndClient(config, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &clusterClient{
+		kubeConfig: cluster.Spec.Connection.KubeConfig,
+		kubernetes: kubernetesClient,
+		horizon:    horizonClient,
+	}
+	c.clients[cluster.Name] = client
+	return client, nil
+}
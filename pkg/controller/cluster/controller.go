@@ -16,7 +16,6 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilRuntime "k8s.io/apimachinery/pkg/util/runtime"
-	clientCmd "k8s.io/client-go/tools/clientcmd"
 
 	"github.com/sunweiwe/horizon/pkg/apiserver/config"
 	"github.com/sunweiwe/horizon/pkg/client/clientset/scheme"
@@ -34,7 +33,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 const (
@@ -78,6 +77,8 @@ type clusterController struct {
 
 	horizonClient horizon.Interface
 
+	clusterClients *ClusterClients
+
 	clusterLister    clusterLister.ClusterLister
 	clusterHasSynced cache.InformerSynced
 
@@ -95,6 +96,7 @@ func NewClusterController(
 	horizonClient horizon.Interface,
 	config *rest.Config,
 	clusterInformer clusterInformer.ClusterInformer,
+	clusterClients *ClusterClients,
 	resyncPeriod time.Duration,
 	hostClusterName string,
 ) *clusterController {
@@ -114,6 +116,7 @@ func NewClusterController(
 		horizonClient:    horizonClient,
 		k8sClient:        k8sClient,
 		hostConfig:       config,
+		clusterClients:   clusterClients,
 	}
 	c.clusterLister = clusterInformer.Lister()
 	c.clusterHasSynced = clusterInformer.Informer().HasSynced
@@ -255,6 +258,8 @@ func (c *clusterController) syncCluster(key string) error {
 	cluster, err := c.clusterLister.Get(name)
 	if err != nil {
 		if errors.IsNotFound(err) {
+			// the cluster is deleted, its clients are no longer shared
+			c.clusterClients.Remove(name)
 			return nil
 		}
 		klog.Errorf("Failed to get cluster with name %s, %#v", name, err)
@@ -293,12 +298,12 @@ func (c *clusterController) syncCluster(key string) error {
 		return nil
 	}
 
-	clusterConfig, err := clientCmd.RESTConfigFromKubeConfig(cluster.Spec.Connection.KubeConfig)
+	clusterConfig, err := ClusterConfig(cluster)
 	if err != nil {
-		return fmt.Errorf("Failed to create cluster config for %s: %s", cluster.Name, err)
+		return err
 	}
 
-	clusterClient, err := kubernetes.NewForConfig(clusterConfig)
+	clusterClient, err := c.clusterClients.Kubernetes(cluster)
 	if err != nil {
 		return fmt.Errorf("Failed to create cluster client for %s: %s", cluster.Name, err)
 	}
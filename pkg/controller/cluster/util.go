@@ -3,11 +3,13 @@ package cluster
 import (
 	"fmt"
 
+	clusterv1alpha1 "github.com/sunweiwe/api/cluster/v1alpha1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 )
 
 func ClusterServiceAccountName(joiningClusterName, hostClusterName string) string {
@@ -35,3 +37,15 @@ func HostClientset(config *rest.Config) (*kubernetes.Clientset, error) {
 func ClusterClientset(config *rest.Config) (*kubernetes.Clientset, error) {
 	return kubernetes.NewForConfig(config)
 }
+
+// ClusterConfig builds the rest config of the cluster from its connection kubeconfig.
+func ClusterConfig(cluster *clusterv1alpha1.Cluster) (*rest.Config, error) {
+	if len(cluster.Spec.Connection.KubeConfig) == 0 {
+		return nil, fmt.Errorf("the kubeconfig of cluster %s is empty", cluster.Name)
+	}
+	config, err := clientcmd.RESTConfigFromKubeConfig(cluster.Spec.Connection.KubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create cluster config for %s: %s", cluster.Name, err)
+	}
+	return config, nil
+}
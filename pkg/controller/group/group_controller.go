@@ -0,0 +1,128 @@
+package group
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	tenantv1alpha1 "github.com/sunweiwe/api/tenant/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	controllerName = "group-controller"
+
+	failedSynced = "FailedSync"
+)
+
+// Reconciler makes the groups owned by their parent groups, so that the child groups are
+// garbage collected along with the parent group.
+type Reconciler struct {
+	client.Client
+	Logger                  logr.Logger
+	Recorder                record.EventRecorder
+	MaxConcurrentReconciles int
+}
+
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Client == nil {
+		r.Client = mgr.GetClient()
+	}
+
+	if r.Logger.GetSink() == nil {
+		r.Logger = ctrl.Log.WithName("controllers").WithName(controllerName)
+	}
+
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor(controllerName)
+	}
+
+	if r.MaxConcurrentReconciles <= 0 {
+		r.MaxConcurrentReconciles = 1
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).Named(controllerName).WithOptions(controller.Options{
+		MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+	}).For(&iamv1alpha2.Group{}).Complete(r)
+}
+
+// +kubebuilder:rbac:groups=iam.horizon.io,resources=groups,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Logger.WithValues("group", req.NamespacedName)
+
+	group := &iamv1alpha2.Group{}
+	if err := r.Get(ctx, req.NamespacedName, group); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !group.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.syncParent(ctx, group); err != nil {
+		logger.Error(err, "failed to sync parent group")
+		r.Recorder.Event(group, corev1.EventTypeWarning, failedSynced, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// syncParent sets the owner reference to the parent group and inherits the workspace of the parent group,
+// the owner references of the former parent groups are removed.
+func (r *Reconciler) syncParent(ctx context.Context, group *iamv1alpha2.Group) error {
+	updated := group.DeepCopy()
+	parentName := group.Labels[iamv1alpha2.GroupParent]
+
+	ownerReferences := make([]metav1.OwnerReference, 0, len(updated.OwnerReferences))
+	for _, ownerReference := range updated.OwnerReferences {
+		if ownerReference.APIVersion == iamv1alpha2.SchemeGroupVersion.String() &&
+			ownerReference.Kind == iamv1alpha2.ResourceKindGroup && ownerReference.Name != parentName {
+			continue
+		}
+		ownerReferences = append(ownerReferences, ownerReference)
+	}
+	updated.OwnerReferences = ownerReferences
+
+	if parentName != "" {
+		parent := &iamv1alpha2.Group{}
+		if err := r.Get(ctx, client.ObjectKey{Name: parentName}, parent); err != nil {
+			return err
+		}
+
+		if err := controllerutil.SetOwnerReference(parent, updated, r.Scheme()); err != nil {
+			return err
+		}
+
+		if workspace, ok := parent.Labels[tenantv1alpha1.WorkspaceLabel]; ok && updated.Labels[tenantv1alpha1.WorkspaceLabel] == "" {
+			updated.Labels[tenantv1alpha1.WorkspaceLabel] = workspace
+		}
+	}
+
+	if equalOwnerReferences(group.OwnerReferences, updated.OwnerReferences) &&
+		group.Labels[tenantv1alpha1.WorkspaceLabel] == updated.Labels[tenantv1alpha1.WorkspaceLabel] {
+		return nil
+	}
+
+	return r.Update(ctx, updated)
+}
+
+func equalOwnerReferences(left, right []metav1.OwnerReference) bool {
+	if len(left) != len(right) {
+		return false
+	}
+	for i := range left {
+		if left[i].UID != right[i].UID {
+			return false
+		}
+	}
+	return true
+}
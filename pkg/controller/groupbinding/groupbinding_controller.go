@@ -0,0 +1,102 @@
+package groupbinding
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	tenantv1alpha1 "github.com/sunweiwe/api/tenant/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	controllerName = "groupbinding-controller"
+
+	failedSynced = "FailedSync"
+)
+
+// Reconciler makes the group bindings owned by the groups they reference, so that the group bindings
+// are garbage collected along with the group. The groups of the users are synced by the user controller.
+type Reconciler struct {
+	client.Client
+	Logger                  logr.Logger
+	Recorder                record.EventRecorder
+	MaxConcurrentReconciles int
+}
+
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Client == nil {
+		r.Client = mgr.GetClient()
+	}
+
+	if r.Logger.GetSink() == nil {
+		r.Logger = ctrl.Log.WithName("controllers").WithName(controllerName)
+	}
+
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor(controllerName)
+	}
+
+	if r.MaxConcurrentReconciles <= 0 {
+		r.MaxConcurrentReconciles = 1
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).Named(controllerName).WithOptions(controller.Options{
+		MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+	}).For(&iamv1alpha2.GroupBinding{}).Complete(r)
+}
+
+// +kubebuilder:rbac:groups=iam.horizon.io,resources=groupbindings,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=iam.horizon.io,resources=groups,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Logger.WithValues("groupbinding", req.NamespacedName)
+
+	groupBinding := &iamv1alpha2.GroupBinding{}
+	if err := r.Get(ctx, req.NamespacedName, groupBinding); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !groupBinding.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	group := &iamv1alpha2.Group{}
+	if err := r.Get(ctx, client.ObjectKey{Name: groupBinding.GroupRef.Name}, group); err != nil {
+		logger.Error(err, "failed to get the group referenced by the group binding")
+		r.Recorder.Event(groupBinding, corev1.EventTypeWarning, failedSynced, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	updated := groupBinding.DeepCopy()
+	if updated.Labels == nil {
+		updated.Labels = make(map[string]string)
+	}
+	updated.Labels[iamv1alpha2.GroupReferenceLabel] = group.Name
+	if workspace, ok := group.Labels[tenantv1alpha1.WorkspaceLabel]; ok {
+		updated.Labels[tenantv1alpha1.WorkspaceLabel] = workspace
+	}
+	if err := controllerutil.SetOwnerReference(group, updated, r.Scheme()); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(updated.OwnerReferences) == len(groupBinding.OwnerReferences) &&
+		updated.Labels[iamv1alpha2.GroupReferenceLabel] == groupBinding.Labels[iamv1alpha2.GroupReferenceLabel] &&
+		updated.Labels[tenantv1alpha1.WorkspaceLabel] == groupBinding.Labels[tenantv1alpha1.WorkspaceLabel] {
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.Update(ctx, updated); err != nil {
+		logger.Error(err, "failed to update group binding")
+		r.Recorder.Event(groupBinding, corev1.EventTypeWarning, failedSynced, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,265 @@
+package kubeconfig
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/token"
+	"github.com/sunweiwe/horizon/pkg/constants"
+	"github.com/sunweiwe/horizon/pkg/models/kubeconfig"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	controllerName = "kubeconfig-controller"
+
+	failedSynced = "FailedSync"
+)
+
+// Reconciler generates a kubeconfig for each active user and stores it in a Secret owned by the user.
+// The kubeconfig points at hz-apiserver with an access token recorded in the token store, so that the
+// requests to the Kubernetes apiserver are authorized by Horizon rather than Kubernetes, and the token
+// is revoked along with the other tokens of the user. The token is renewed halfway through its lifetime.
+type Reconciler struct {
+	client.Client
+	Logger                  logr.Logger
+	Recorder                record.EventRecorder
+	MaxConcurrentReconciles int
+	AuthenticationOptions   *authentication.Options
+	// TokenStore must be shared with hz-apiserver, the tokens not recorded in it are rejected
+	TokenStore token.Store
+	// Server is the address of hz-apiserver written to the kubeconfig
+	Server string
+
+	issuer token.Issuer
+}
+
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Client == nil {
+		r.Client = mgr.GetClient()
+	}
+
+	if r.Logger.GetSink() == nil {
+		r.Logger = ctrl.Log.WithName("controllers").WithName(controllerName)
+	}
+
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor(controllerName)
+	}
+
+	if r.MaxConcurrentReconciles <= 0 {
+		r.MaxConcurrentReconciles = 1
+	}
+
+	if r.AuthenticationOptions == nil {
+		r.AuthenticationOptions = authentication.NewOptions()
+	}
+
+	if r.Server == "" {
+		r.Server = kubeconfig.DefaultServer
+	}
+
+	if r.TokenStore == nil {
+		return fmt.Errorf("token store of %s is required", controllerName)
+	}
+
+	r.issuer = token.NewTokenIssuer(r.AuthenticationOptions.JwtSecret, r.AuthenticationOptions.MaximumClockSkew)
+
+	return ctrl.NewControllerManagedBy(mgr).Named(controllerName).WithOptions(controller.Options{
+		MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+	}).
+		For(&iamv1alpha2.User{}).
+		// the kubeconfig is regenerated once its Secret is changed, the Secrets of the token store are not
+		// watched, otherwise revoking all the tokens of the user would hand out a new one right away,
+		// the revoked token is replaced on the next sync of the user
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.mapSecretToUser)).
+		Complete(r)
+}
+
+func (r *Reconciler) mapSecretToUser(_ context.Context, obj client.Object) []reconcile.Request {
+	username := obj.GetLabels()[kubeconfig.UserReferenceLabel]
+	if obj.GetNamespace() != constants.HorizonNamespace || username == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: username}}}
+}
+
+// +kubebuilder:rbac:groups=iam.horizon.io,resources=users,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Logger.WithValues("user", req.NamespacedName)
+
+	u := &iamv1alpha2.User{}
+	if err := r.Get(ctx, req.NamespacedName, u); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// the Secret is garbage collected along with the user
+	if !u.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: constants.HorizonNamespace, Name: kubeconfig.SecretName(u.Name)}, secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	found := err == nil
+
+	// the users not active are not allowed to access the clusters
+	if u.Status.State != iamv1alpha2.UserActive {
+		if found {
+			if err := r.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if found {
+		renewAt, valid := r.renewTime(u, secret)
+		// the Secrets labelled before the dedicated label of the kubeconfig are relabelled
+		if valid && secret.Labels[kubeconfig.UserReferenceLabel] == u.Name {
+			return ctrl.Result{RequeueAfter: time.Until(renewAt)}, nil
+		}
+	}
+
+	data, expiresAt, err := r.newKubeConfig(u)
+	if err != nil {
+		logger.Error(err, "failed to generate kubeconfig")
+		r.Recorder.Event(u, corev1.EventTypeWarning, failedSynced, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	if !found {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        kubeconfig.SecretName(u.Name),
+				Namespace:   constants.HorizonNamespace,
+				Labels:      map[string]string{kubeconfig.UserReferenceLabel: u.Name},
+				Annotations: map[string]string{kubeconfig.TokenExpiresAtAnnotation: expiresAt.Format(time.RFC3339)},
+			},
+			Type: kubeconfig.SecretTypeKubeConfig,
+			Data: map[string][]byte{kubeconfig.FileName: data},
+		}
+		if err := controllerutil.SetControllerReference(u, secret, r.Scheme()); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.Create(ctx, secret); err != nil {
+			logger.Error(err, "failed to create kubeconfig")
+			r.Recorder.Event(u, corev1.EventTypeWarning, failedSynced, err.Error())
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: r.renewPeriod()}, nil
+	}
+
+	updated := secret.DeepCopy()
+	if updated.Data == nil {
+		updated.Data = make(map[string][]byte)
+	}
+	if updated.Annotations == nil {
+		updated.Annotations = make(map[string]string)
+	}
+	if updated.Labels == nil {
+		updated.Labels = make(map[string]string)
+	}
+	// the label shared with the Secrets of the token store is replaced by the label of the kubeconfig
+	delete(updated.Labels, iamv1alpha2.UserReferenceLabel)
+	updated.Labels[kubeconfig.UserReferenceLabel] = u.Name
+	updated.Data[kubeconfig.FileName] = data
+	updated.Annotations[kubeconfig.TokenExpiresAtAnnotation] = expiresAt.Format(time.RFC3339)
+	if err := controllerutil.SetControllerReference(u, updated, r.Scheme()); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.Update(ctx, updated); err != nil {
+		logger.Error(err, "failed to update kubeconfig")
+		r.Recorder.Event(u, corev1.EventTypeWarning, failedSynced, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	// the previous token is revoked once replaced
+	if _, previous, err := kubeconfig.Parse(secret.Data[kubeconfig.FileName]); err == nil && previous != "" {
+		if err := r.TokenStore.Revoke(u.Name, previous); err != nil {
+			logger.Error(err, "failed to revoke the previous token")
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: r.renewPeriod()}, nil
+}
+
+// renewTime returns when the token in the kubeconfig should be renewed, and false if the kubeconfig must be
+// regenerated right away, e.g. it points at another server, the token was revoked or the jwt secret is rotated.
+func (r *Reconciler) renewTime(u *iamv1alpha2.User, secret *corev1.Secret) (time.Time, bool) {
+	data, ok := secret.Data[kubeconfig.FileName]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, secret.Annotations[kubeconfig.TokenExpiresAtAnnotation])
+	if err != nil {
+		return time.Time{}, false
+	}
+	renewAt := expiresAt.Add(-r.AuthenticationOptions.KubeconfigTokenMaxAge / 2)
+	if !time.Now().Before(renewAt) {
+		return time.Time{}, false
+	}
+
+	server, tokenString, err := kubeconfig.Parse(data)
+	if err != nil || server != r.Server {
+		return time.Time{}, false
+	}
+
+	info, tokenType, err := r.issuer.Verify(tokenString)
+	if err != nil || tokenType != token.AccessToken || info.GetName() != u.Name {
+		return time.Time{}, false
+	}
+
+	exists, err := r.TokenStore.Exists(u.Name, tokenString)
+	if err != nil || !exists {
+		return time.Time{}, false
+	}
+
+	return renewAt, true
+}
+
+// newKubeConfig issues an access token to the user and records it in the store, so that it can be revoked.
+func (r *Reconciler) newKubeConfig(u *iamv1alpha2.User) ([]byte, time.Time, error) {
+	maxAge := r.AuthenticationOptions.KubeconfigTokenMaxAge
+	expiresAt := time.Now().Add(maxAge)
+
+	tokenString, err := r.issuer.IssueTo(&user.DefaultInfo{Name: u.Name}, token.AccessToken, maxAge)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if err := r.TokenStore.Add(u.Name, tokenString, maxAge); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	data, err := kubeconfig.New(r.Server, u.Name, tokenString)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return data, expiresAt, nil
+}
+
+func (r *Reconciler) renewPeriod() time.Duration {
+	return r.AuthenticationOptions.KubeconfigTokenMaxAge / 2
+}
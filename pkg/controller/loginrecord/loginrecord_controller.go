@@ -0,0 +1,114 @@
+package loginrecord
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	controllerName = "loginrecord-controller"
+
+	failedSynced = "FailedSync"
+)
+
+type Reconciler struct {
+	client.Client
+	Logger                  logr.Logger
+	Recorder                record.EventRecorder
+	AuthenticationOptions   *authentication.Options
+	MaxConcurrentReconciles int
+}
+
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Client == nil {
+		r.Client = mgr.GetClient()
+	}
+
+	if r.Logger.GetSink() == nil {
+		r.Logger = ctrl.Log.WithName("controllers").WithName(controllerName)
+	}
+
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor(controllerName)
+	}
+
+	if r.AuthenticationOptions == nil {
+		r.AuthenticationOptions = authentication.NewOptions()
+	}
+
+	if r.MaxConcurrentReconciles <= 0 {
+		r.MaxConcurrentReconciles = 1
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).Named(controllerName).WithOptions(controller.Options{
+		MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+	}).For(&iamv1alpha2.LoginRecord{}).Complete(r)
+}
+
+// +kubebuilder:rbac:groups=iam.horizon.io,resources=loginrecords,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=iam.horizon.io,resources=users,verbs=get;list;watch
+// +kubebuilder:rbac:groups=iam.horizon.io,resources=users/status,verbs=get;update;patch
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Logger.WithValues("loginrecord", req.NamespacedName)
+
+	loginRecord := &iamv1alpha2.LoginRecord{}
+	if err := r.Get(ctx, req.NamespacedName, loginRecord); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !loginRecord.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// login records beyond the retention period will be deleted
+	expiresAt := loginRecord.CreationTimestamp.Add(r.AuthenticationOptions.LoginHistoryRetentionPeriod)
+	remaining := time.Until(expiresAt)
+	if remaining <= 0 {
+		if err := r.Delete(ctx, loginRecord); err != nil {
+			logger.Error(err, "failed to delete expired login record")
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.updateUserLastLoginTime(ctx, loginRecord); err != nil {
+		logger.Error(err, "failed to update last login time of user")
+		r.Recorder.Event(loginRecord, corev1.EventTypeWarning, failedSynced, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: remaining}, nil
+}
+
+// updateUserLastLoginTime updates UserStatus.LastLoginTime with the successful login record
+func (r *Reconciler) updateUserLastLoginTime(ctx context.Context, loginRecord *iamv1alpha2.LoginRecord) error {
+	username, ok := loginRecord.Labels[iamv1alpha2.UserReferenceLabel]
+	if !ok || username == "" || !loginRecord.Spec.Success {
+		return nil
+	}
+
+	user := &iamv1alpha2.User{}
+	if err := r.Get(ctx, client.ObjectKey{Name: username}, user); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	loginTime := loginRecord.CreationTimestamp
+	if user.Status.LastLoginTime != nil && !user.Status.LastLoginTime.Before(&loginTime) {
+		return nil
+	}
+
+	user = user.DeepCopy()
+	user.Status.LastLoginTime = &metav1.Time{Time: loginTime.Time}
+	return r.Status().Update(ctx, user)
+}
@@ -2,21 +2,40 @@ package namespace
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
-	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	tenantv1alpha1 "github.com/sunweiwe/api/tenant/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
 const (
 	controllerName = "namespace-controller"
+
+	failedSynced = "FailedSync"
+
+	// adminRole is the default role of the namespaces in workspaces, it is bound to the workspace admins
+	adminRole = "admin"
+
+	workspaceAdminRoleFormat = "%s-admin"
 )
 
+// Reconciler binds the namespaces labelled with tenantv1alpha1.WorkspaceLabel to their workspaces,
+// the namespaces are owned by the workspaces and the workspace admins are the admins of the namespaces.
 type Reconciler struct {
 	client.Client
 	Logger                  logr.Logger
@@ -44,16 +63,198 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	return ctrl.NewControllerManagedBy(mgr).Named(controllerName).WithOptions(controller.Options{
 		MaxConcurrentReconciles: r.MaxConcurrentReconciles,
-	}).For(&corev1.Namespace{}).Complete(r)
+	}).
+		For(&corev1.Namespace{}).
+		Watches(&tenantv1alpha1.Workspace{}, handler.EnqueueRequestsFromMapFunc(r.mapWorkspaceToNamespaces)).
+		Watches(&iamv1alpha2.WorkspaceRoleBinding{}, handler.EnqueueRequestsFromMapFunc(r.mapWorkspaceToNamespaces)).
+		Complete(r)
 
 }
 
-// TODO
+// mapWorkspaceToNamespaces enqueues the namespaces of the workspace the object belongs to.
+func (r *Reconciler) mapWorkspaceToNamespaces(ctx context.Context, obj client.Object) []reconcile.Request {
+	workspace := obj.GetName()
+	if _, ok := obj.(*tenantv1alpha1.Workspace); !ok {
+		workspace = obj.GetLabels()[tenantv1alpha1.WorkspaceLabel]
+	}
+	if workspace == "" {
+		return nil
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaces, client.MatchingLabels{tenantv1alpha1.WorkspaceLabel: workspace}); err != nil {
+		r.Logger.Error(err, "failed to list namespaces", "workspace", workspace)
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(namespaces.Items))
+	for _, namespace := range namespaces.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: namespace.Name}})
+	}
+	return requests
+}
+
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=get;list;watch
+// +kubebuilder:rbac:groups=tenant.horizon.io,resources=workspaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=iam.horizon.io,resources=workspacerolebindings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	klog.V(0).Infof("Namespace reconcile starting")
+	logger := r.Logger.WithValues("namespace", req.NamespacedName)
+
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, req.NamespacedName, namespace); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !namespace.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	var workspace *tenantv1alpha1.Workspace
+	if workspaceName := namespace.Labels[tenantv1alpha1.WorkspaceLabel]; workspaceName != "" {
+		workspace = &tenantv1alpha1.Workspace{}
+		if err := r.Get(ctx, types.NamespacedName{Name: workspaceName}, workspace); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+			// the namespace is bound once the workspace is created
+			logger.V(4).Info("workspace not found", "workspace", workspaceName)
+			workspace = nil
+		}
+	}
+
+	if workspace != nil && !workspace.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.bindWorkspace(ctx, namespace, workspace); err != nil {
+		logger.Error(err, "failed to bind workspace")
+		r.Recorder.Event(namespace, corev1.EventTypeWarning, failedSynced, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	if workspace == nil {
+		// the namespace left its workspace, the admins of the workspace are no longer its admins
+		if err := r.removeStaleRoleBindings(ctx, namespace, "", nil); err != nil {
+			logger.Error(err, "failed to remove stale role bindings")
+			r.Recorder.Event(namespace, corev1.EventTypeWarning, failedSynced, err.Error())
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.initDefaultRoleBindings(ctx, namespace, workspace); err != nil {
+		logger.Error(err, "failed to init default role bindings")
+		r.Recorder.Event(namespace, corev1.EventTypeWarning, failedSynced, err.Error())
+		return ctrl.Result{}, err
+	}
 
 	return ctrl.Result{}, nil
 }
+
+// bindWorkspace makes the workspace the owner of the namespace and removes the owner references
+// of the workspaces the namespace no longer belongs to.
+func (r *Reconciler) bindWorkspace(ctx context.Context, namespace *corev1.Namespace, workspace *tenantv1alpha1.Workspace) error {
+	ownerReferences := make([]metav1.OwnerReference, 0, len(namespace.OwnerReferences))
+	bound := false
+	for _, ownerReference := range namespace.OwnerReferences {
+		if ownerReference.Kind == tenantv1alpha1.ResourceKindWorkspace &&
+			ownerReference.APIVersion == tenantv1alpha1.SchemeGroupVersion.String() {
+			if workspace == nil || ownerReference.UID != workspace.UID {
+				continue
+			}
+			bound = true
+		}
+		ownerReferences = append(ownerReferences, ownerReference)
+	}
+
+	if len(ownerReferences) == len(namespace.OwnerReferences) && (workspace == nil || bound) {
+		return nil
+	}
+
+	namespace = namespace.DeepCopy()
+	namespace.OwnerReferences = ownerReferences
+	if workspace != nil && !bound {
+		if err := controllerutil.SetOwnerReference(workspace, namespace, r.Scheme()); err != nil {
+			return err
+		}
+	}
+	return r.Update(ctx, namespace)
+}
+
+// initDefaultRoleBindings creates the admin role of the namespace and binds it to the workspace admins,
+// the role bindings are labelled with the workspace and removed once the users are no longer its admins.
+func (r *Reconciler) initDefaultRoleBindings(ctx context.Context, namespace *corev1.Namespace, workspace *tenantv1alpha1.Workspace) error {
+	role := &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: adminRole, Namespace: namespace.Name}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, role, func() error {
+		role.Rules = []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{rbacv1.APIGroupAll},
+				Resources: []string{rbacv1.ResourceAll},
+				Verbs:     []string{rbacv1.VerbAll},
+			},
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	workspaceRoleBindings := &iamv1alpha2.WorkspaceRoleBindingList{}
+	if err := r.List(ctx, workspaceRoleBindings, client.MatchingLabels{
+		tenantv1alpha1.WorkspaceLabel:  workspace.Name,
+		iamv1alpha2.RoleReferenceLabel: fmt.Sprintf(workspaceAdminRoleFormat, workspace.Name),
+	}); err != nil {
+		return err
+	}
+
+	admins := sets.New[string]()
+	for _, workspaceRoleBinding := range workspaceRoleBindings.Items {
+		username := workspaceRoleBinding.Labels[iamv1alpha2.UserReferenceLabel]
+		if username == "" || !workspaceRoleBinding.DeletionTimestamp.IsZero() {
+			continue
+		}
+		admins.Insert(username)
+		roleBinding := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-%s", username, adminRole), Namespace: namespace.Name}}
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, roleBinding, func() error {
+			if roleBinding.Labels == nil {
+				roleBinding.Labels = make(map[string]string)
+			}
+			roleBinding.Labels[iamv1alpha2.UserReferenceLabel] = username
+			roleBinding.Labels[iamv1alpha2.RoleReferenceLabel] = adminRole
+			roleBinding.Labels[tenantv1alpha1.WorkspaceLabel] = workspace.Name
+			roleBinding.Subjects = workspaceRoleBinding.Subjects
+			roleBinding.RoleRef = rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "Role",
+				Name:     adminRole,
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return r.removeStaleRoleBindings(ctx, namespace, workspace.Name, admins)
+}
+
+// removeStaleRoleBindings removes the role bindings generated for the admins of the workspace which are not
+// in admins, or generated for the admins of another workspace the namespace belonged to.
+func (r *Reconciler) removeStaleRoleBindings(ctx context.Context, namespace *corev1.Namespace, workspace string, admins sets.Set[string]) error {
+	roleBindings := &rbacv1.RoleBindingList{}
+	if err := r.List(ctx, roleBindings, client.InNamespace(namespace.Name),
+		client.MatchingLabels{iamv1alpha2.RoleReferenceLabel: adminRole},
+		client.HasLabels{tenantv1alpha1.WorkspaceLabel}); err != nil {
+		return err
+	}
+
+	for i := range roleBindings.Items {
+		roleBinding := &roleBindings.Items[i]
+		if roleBinding.Labels[tenantv1alpha1.WorkspaceLabel] == workspace && admins.Has(roleBinding.Labels[iamv1alpha2.UserReferenceLabel]) {
+			continue
+		}
+		if err := r.Delete(ctx, roleBinding); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package roletemplate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const globalRoleControllerName = "globalrole-aggregation-controller"
+
+// GlobalRoleReconciler aggregates the rules of the global role templates referenced by the
+// aggregation role templates annotation into the annotated global roles.
+type GlobalRoleReconciler struct {
+	client.Client
+	Logger                  logr.Logger
+	Recorder                record.EventRecorder
+	MaxConcurrentReconciles int
+}
+
+func (r *GlobalRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Client == nil {
+		r.Client = mgr.GetClient()
+	}
+
+	if r.Logger.GetSink() == nil {
+		r.Logger = ctrl.Log.WithName("controllers").WithName(globalRoleControllerName)
+	}
+
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor(globalRoleControllerName)
+	}
+
+	if r.MaxConcurrentReconciles <= 0 {
+		r.MaxConcurrentReconciles = 1
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).Named(globalRoleControllerName).WithOptions(controller.Options{
+		MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+	}).
+		For(&iamv1alpha2.GlobalRole{}).
+		Watches(&iamv1alpha2.GlobalRole{}, handler.EnqueueRequestsFromMapFunc(r.mapTemplateToAggregatedRoles)).
+		Complete(r)
+}
+
+func (r *GlobalRoleReconciler) mapTemplateToAggregatedRoles(ctx context.Context, obj client.Object) []reconcile.Request {
+	if !isRoleTemplate(obj) {
+		return nil
+	}
+
+	globalRoles := &iamv1alpha2.GlobalRoleList{}
+	if err := r.List(ctx, globalRoles); err != nil {
+		r.Logger.Error(err, "failed to list global roles")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, globalRole := range globalRoles.Items {
+		if aggregatesTemplate(&globalRole, obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: globalRole.Name}})
+		}
+	}
+	return requests
+}
+
+// +kubebuilder:rbac:groups=iam.horizon.io,resources=globalroles,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+func (r *GlobalRoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Logger.WithValues("globalrole", req.NamespacedName)
+
+	globalRole := &iamv1alpha2.GlobalRole{}
+	if err := r.Get(ctx, req.NamespacedName, globalRole); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !globalRole.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	templates, err := aggregationRoleTemplates(globalRole)
+	if err != nil {
+		// the annotation must be fixed by the user, retrying does not help
+		r.Recorder.Event(globalRole, corev1.EventTypeWarning, failedSynced, err.Error())
+		return ctrl.Result{}, nil
+	}
+	if templates == nil {
+		return ctrl.Result{}, nil
+	}
+
+	rules := make([]rbacv1.PolicyRule, 0)
+	for _, name := range templates {
+		if name == globalRole.Name {
+			continue
+		}
+		template := &iamv1alpha2.GlobalRole{}
+		if err := r.Get(ctx, types.NamespacedName{Name: name}, template); err != nil {
+			if apierrors.IsNotFound(err) {
+				r.Recorder.Event(globalRole, corev1.EventTypeWarning, failedSynced, fmt.Sprintf(messageTemplateNotFound, name))
+				continue
+			}
+			return ctrl.Result{}, err
+		}
+		if !isRoleTemplate(template) {
+			r.Recorder.Event(globalRole, corev1.EventTypeWarning, failedSynced, fmt.Sprintf(messageNotTemplate, name))
+			continue
+		}
+		rules = mergeRules(rules, template.Rules)
+	}
+
+	if equality.Semantic.DeepEqual(rules, globalRole.Rules) {
+		return ctrl.Result{}, nil
+	}
+
+	updated := globalRole.DeepCopy()
+	updated.Rules = rules
+	if err := r.Update(ctx, updated); err != nil {
+		logger.Error(err, "failed to update global role")
+		r.Recorder.Event(globalRole, corev1.EventTypeWarning, failedSynced, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	r.Recorder.Event(globalRole, corev1.EventTypeNormal, aggregated, fmt.Sprintf(messageAggregated, templates))
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,157 @@
+package roletemplate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/sunweiwe/horizon/pkg/constants"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const roleControllerName = "role-aggregation-controller"
+
+// RoleReconciler aggregates the rules of the role templates referenced by the aggregation role
+// templates annotation into the annotated roles. The templates are looked up in the namespace of the role,
+// then in the horizon namespace, where the built-in templates shared by all the namespaces are installed.
+type RoleReconciler struct {
+	client.Client
+	Logger                  logr.Logger
+	Recorder                record.EventRecorder
+	MaxConcurrentReconciles int
+}
+
+func (r *RoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Client == nil {
+		r.Client = mgr.GetClient()
+	}
+
+	if r.Logger.GetSink() == nil {
+		r.Logger = ctrl.Log.WithName("controllers").WithName(roleControllerName)
+	}
+
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor(roleControllerName)
+	}
+
+	if r.MaxConcurrentReconciles <= 0 {
+		r.MaxConcurrentReconciles = 1
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).Named(roleControllerName).WithOptions(controller.Options{
+		MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+	}).
+		For(&rbacv1.Role{}).
+		Watches(&rbacv1.Role{}, handler.EnqueueRequestsFromMapFunc(r.mapTemplateToAggregatedRoles)).
+		Complete(r)
+}
+
+func (r *RoleReconciler) mapTemplateToAggregatedRoles(ctx context.Context, obj client.Object) []reconcile.Request {
+	if !isRoleTemplate(obj) {
+		return nil
+	}
+
+	// the built-in templates are aggregated by the roles of all the namespaces
+	listOptions := make([]client.ListOption, 0)
+	if obj.GetNamespace() != constants.HorizonNamespace {
+		listOptions = append(listOptions, client.InNamespace(obj.GetNamespace()))
+	}
+
+	roles := &rbacv1.RoleList{}
+	if err := r.List(ctx, roles, listOptions...); err != nil {
+		r.Logger.Error(err, "failed to list roles")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, role := range roles.Items {
+		if aggregatesTemplate(&role, obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: role.Namespace, Name: role.Name}})
+		}
+	}
+	return requests
+}
+
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+func (r *RoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Logger.WithValues("role", req.NamespacedName)
+
+	role := &rbacv1.Role{}
+	if err := r.Get(ctx, req.NamespacedName, role); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !role.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	templates, err := aggregationRoleTemplates(role)
+	if err != nil {
+		// the annotation must be fixed by the user, retrying does not help
+		r.Recorder.Event(role, corev1.EventTypeWarning, failedSynced, err.Error())
+		return ctrl.Result{}, nil
+	}
+	if templates == nil {
+		return ctrl.Result{}, nil
+	}
+
+	rules := make([]rbacv1.PolicyRule, 0)
+	for _, name := range templates {
+		if name == role.Name {
+			continue
+		}
+		template, err := r.getTemplate(ctx, role.Namespace, name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				r.Recorder.Event(role, corev1.EventTypeWarning, failedSynced, fmt.Sprintf(messageTemplateNotFound, name))
+				continue
+			}
+			return ctrl.Result{}, err
+		}
+		if !isRoleTemplate(template) {
+			r.Recorder.Event(role, corev1.EventTypeWarning, failedSynced, fmt.Sprintf(messageNotTemplate, name))
+			continue
+		}
+		rules = mergeRules(rules, template.Rules)
+	}
+
+	if equality.Semantic.DeepEqual(rules, role.Rules) {
+		return ctrl.Result{}, nil
+	}
+
+	updated := role.DeepCopy()
+	updated.Rules = rules
+	if err := r.Update(ctx, updated); err != nil {
+		logger.Error(err, "failed to update role")
+		r.Recorder.Event(role, corev1.EventTypeWarning, failedSynced, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	r.Recorder.Event(role, corev1.EventTypeNormal, aggregated, fmt.Sprintf(messageAggregated, templates))
+	return ctrl.Result{}, nil
+}
+
+// getTemplate returns the role template in the namespace, the built-in template of the name is returned if
+// the namespace has none.
+func (r *RoleReconciler) getTemplate(ctx context.Context, namespace string, name string) (*rbacv1.Role, error) {
+	template := &rbacv1.Role{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, template)
+	if err == nil || !apierrors.IsNotFound(err) || namespace == constants.HorizonNamespace {
+		return template, err
+	}
+
+	err = r.Get(ctx, types.NamespacedName{Namespace: constants.HorizonNamespace, Name: name}, template)
+	return template, err
+}
@@ -0,0 +1,74 @@
+package roletemplate
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+const (
+	failedSynced = "FailedSync"
+	aggregated   = "Aggregated"
+
+	messageTemplateNotFound = "role template %s not found"
+	messageNotTemplate      = "role %s is not a role template"
+	messageAggregated       = "rules aggregated from role templates %v"
+)
+
+// isRoleTemplate returns true if the role is labelled as a role template.
+func isRoleTemplate(role v1.Object) bool {
+	return role.GetLabels()[iamv1alpha2.RoleTemplateLabel] == "true"
+}
+
+// aggregationRoleTemplates returns the names of the role templates aggregated by the role,
+// nil is returned if the role is not an aggregated role.
+func aggregationRoleTemplates(role v1.Object) ([]string, error) {
+	value, ok := role.GetAnnotations()[iamv1alpha2.AggregationRoleTemplatesAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	templates := make([]string, 0)
+	if err := json.Unmarshal([]byte(value), &templates); err != nil {
+		return nil, fmt.Errorf("invalid annotation %s: %v", iamv1alpha2.AggregationRoleTemplatesAnnotation, err)
+	}
+	return templates, nil
+}
+
+// aggregatesTemplate returns true if the role aggregates the given role template.
+func aggregatesTemplate(role v1.Object, template string) bool {
+	templates, err := aggregationRoleTemplates(role)
+	if err != nil {
+		return false
+	}
+	for _, name := range templates {
+		if name == template {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeRules appends the rules which are not yet contained in the aggregated rules.
+func mergeRules(aggregatedRules []rbacv1.PolicyRule, rules []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	for _, rule := range rules {
+		if !containsRule(aggregatedRules, rule) {
+			aggregatedRules = append(aggregatedRules, rule)
+		}
+	}
+	return aggregatedRules
+}
+
+func containsRule(rules []rbacv1.PolicyRule, rule rbacv1.PolicyRule) bool {
+	for _, r := range rules {
+		if equality.Semantic.DeepEqual(r, rule) {
+			return true
+		}
+	}
+	return false
+}
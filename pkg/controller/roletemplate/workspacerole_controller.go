@@ -0,0 +1,147 @@
+package roletemplate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	tenantv1alpha1 "github.com/sunweiwe/api/tenant/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const workspaceRoleControllerName = "workspacerole-aggregation-controller"
+
+// WorkspaceRoleReconciler aggregates the rules of the workspace role templates referenced by the
+// aggregation role templates annotation into the annotated workspace roles, only the templates
+// of the same workspace and the built-in templates, which belong to no workspace, are aggregated.
+type WorkspaceRoleReconciler struct {
+	client.Client
+	Logger                  logr.Logger
+	Recorder                record.EventRecorder
+	MaxConcurrentReconciles int
+}
+
+func (r *WorkspaceRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Client == nil {
+		r.Client = mgr.GetClient()
+	}
+
+	if r.Logger.GetSink() == nil {
+		r.Logger = ctrl.Log.WithName("controllers").WithName(workspaceRoleControllerName)
+	}
+
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor(workspaceRoleControllerName)
+	}
+
+	if r.MaxConcurrentReconciles <= 0 {
+		r.MaxConcurrentReconciles = 1
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).Named(workspaceRoleControllerName).WithOptions(controller.Options{
+		MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+	}).
+		For(&iamv1alpha2.WorkspaceRole{}).
+		Watches(&iamv1alpha2.WorkspaceRole{}, handler.EnqueueRequestsFromMapFunc(r.mapTemplateToAggregatedRoles)).
+		Complete(r)
+}
+
+func (r *WorkspaceRoleReconciler) mapTemplateToAggregatedRoles(ctx context.Context, obj client.Object) []reconcile.Request {
+	if !isRoleTemplate(obj) {
+		return nil
+	}
+
+	workspaceRoles := &iamv1alpha2.WorkspaceRoleList{}
+	if err := r.List(ctx, workspaceRoles); err != nil {
+		r.Logger.Error(err, "failed to list workspace roles")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, workspaceRole := range workspaceRoles.Items {
+		if templateOfWorkspace(obj, workspaceRole.Labels[tenantv1alpha1.WorkspaceLabel]) &&
+			aggregatesTemplate(&workspaceRole, obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: workspaceRole.Name}})
+		}
+	}
+	return requests
+}
+
+// +kubebuilder:rbac:groups=iam.horizon.io,resources=workspaceroles,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+func (r *WorkspaceRoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Logger.WithValues("workspacerole", req.NamespacedName)
+
+	workspaceRole := &iamv1alpha2.WorkspaceRole{}
+	if err := r.Get(ctx, req.NamespacedName, workspaceRole); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !workspaceRole.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	templates, err := aggregationRoleTemplates(workspaceRole)
+	if err != nil {
+		// the annotation must be fixed by the user, retrying does not help
+		r.Recorder.Event(workspaceRole, corev1.EventTypeWarning, failedSynced, err.Error())
+		return ctrl.Result{}, nil
+	}
+	if templates == nil {
+		return ctrl.Result{}, nil
+	}
+
+	rules := make([]rbacv1.PolicyRule, 0)
+	for _, name := range templates {
+		if name == workspaceRole.Name {
+			continue
+		}
+		template := &iamv1alpha2.WorkspaceRole{}
+		if err := r.Get(ctx, types.NamespacedName{Name: name}, template); err != nil {
+			if apierrors.IsNotFound(err) {
+				r.Recorder.Event(workspaceRole, corev1.EventTypeWarning, failedSynced, fmt.Sprintf(messageTemplateNotFound, name))
+				continue
+			}
+			return ctrl.Result{}, err
+		}
+		if !isRoleTemplate(template) || !templateOfWorkspace(template, workspaceRole.Labels[tenantv1alpha1.WorkspaceLabel]) {
+			r.Recorder.Event(workspaceRole, corev1.EventTypeWarning, failedSynced, fmt.Sprintf(messageNotTemplate, name))
+			continue
+		}
+		rules = mergeRules(rules, template.Rules)
+	}
+
+	if equality.Semantic.DeepEqual(rules, workspaceRole.Rules) {
+		return ctrl.Result{}, nil
+	}
+
+	updated := workspaceRole.DeepCopy()
+	updated.Rules = rules
+	if err := r.Update(ctx, updated); err != nil {
+		logger.Error(err, "failed to update workspace role")
+		r.Recorder.Event(workspaceRole, corev1.EventTypeWarning, failedSynced, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	r.Recorder.Event(workspaceRole, corev1.EventTypeNormal, aggregated, fmt.Sprintf(messageAggregated, templates))
+	return ctrl.Result{}, nil
+}
+
+// templateOfWorkspace returns whether the workspace role template can be aggregated by the roles of the workspace,
+// the built-in templates are not labelled with any workspace and are shared by all the workspaces.
+func templateOfWorkspace(template client.Object, workspace string) bool {
+	templateWorkspace := template.GetLabels()[tenantv1alpha1.WorkspaceLabel]
+	return templateWorkspace == "" || templateWorkspace == workspace
+}
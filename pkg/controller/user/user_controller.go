@@ -0,0 +1,269 @@
+package user
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication"
+	"golang.org/x/crypto/bcrypt"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	controllerName = "user-controller"
+
+	successSynced         = "Synced"
+	messageResourceSynced = "User synced successfully"
+	failedSynced          = "FailedSync"
+
+	pendingConfirmationReason = "pending confirmation by administrator"
+	authLimitExceededReason   = "too many failed login attempts"
+)
+
+type Reconciler struct {
+	client.Client
+	Logger                  logr.Logger
+	Recorder                record.EventRecorder
+	AuthenticationOptions   *authentication.Options
+	MaxConcurrentReconciles int
+}
+
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Client == nil {
+		r.Client = mgr.GetClient()
+	}
+
+	if r.Logger.GetSink() == nil {
+		r.Logger = ctrl.Log.WithName("controllers").WithName(controllerName)
+	}
+
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor(controllerName)
+	}
+
+	if r.AuthenticationOptions == nil {
+		r.AuthenticationOptions = authentication.NewOptions()
+	}
+
+	if r.MaxConcurrentReconciles <= 0 {
+		r.MaxConcurrentReconciles = 1
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).Named(controllerName).WithOptions(controller.Options{
+		MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+	}).For(&iamv1alpha2.User{}).
+		Watches(&iamv1alpha2.GroupBinding{}, handler.EnqueueRequestsFromMapFunc(r.mapGroupBindingToUsers)).
+		Watches(&iamv1alpha2.LoginRecord{}, handler.EnqueueRequestsFromMapFunc(r.mapFailedLoginRecordToUser)).
+		Complete(r)
+}
+
+// mapFailedLoginRecordToUser enqueues the user of the failed login record, so that the failed attempts are counted
+func (r *Reconciler) mapFailedLoginRecordToUser(_ context.Context, obj client.Object) []reconcile.Request {
+	loginRecord, ok := obj.(*iamv1alpha2.LoginRecord)
+	if !ok || loginRecord.Spec.Success {
+		return nil
+	}
+
+	username := loginRecord.Labels[iamv1alpha2.UserReferenceLabel]
+	if username == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Name: username}}}
+}
+
+// mapGroupBindingToUsers enqueues the users of the group binding, both the old and the new users are enqueued on update
+func (r *Reconciler) mapGroupBindingToUsers(_ context.Context, obj client.Object) []reconcile.Request {
+	groupBinding, ok := obj.(*iamv1alpha2.GroupBinding)
+	if !ok {
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(groupBinding.Users))
+	for _, username := range groupBinding.Users {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKey{Name: username}})
+	}
+	return requests
+}
+
+// +kubebuilder:rbac:groups=iam.horizon.io,resources=users,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=iam.horizon.io,resources=users/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=iam.horizon.io,resources=groupbindings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=iam.horizon.io,resources=loginrecords,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Logger.WithValues("user", req.NamespacedName)
+
+	user := &iamv1alpha2.User{}
+	if err := r.Get(ctx, req.NamespacedName, user); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !user.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// the update of the password triggers another reconciliation
+	if updated, err := r.encryptPassword(ctx, user); err != nil || updated {
+		if err != nil {
+			logger.Error(err, "failed to encrypt user password")
+			r.Recorder.Event(user, corev1.EventTypeWarning, failedSynced, err.Error())
+		}
+		return ctrl.Result{}, err
+	}
+
+	if updated, err := r.syncUserGroups(ctx, user); err != nil || updated {
+		if err != nil {
+			logger.Error(err, "failed to sync user groups")
+			r.Recorder.Event(user, corev1.EventTypeWarning, failedSynced, err.Error())
+		}
+		return ctrl.Result{}, err
+	}
+
+	requeueAfter, err := r.syncUserStatus(ctx, user)
+	if err != nil {
+		logger.Error(err, "failed to sync user status")
+		r.Recorder.Event(user, corev1.EventTypeWarning, failedSynced, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	r.Recorder.Event(user, corev1.EventTypeNormal, successSynced, messageResourceSynced)
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// syncUserStatus initializes the state of new users, locks the active users whose failed login attempts reach
+// AuthenticateRateLimiterMaxTries within AuthenticateRateLimiterDuration and unlocks them once the duration has
+// elapsed, returns the duration until the user should be unlocked.
+func (r *Reconciler) syncUserStatus(ctx context.Context, user *iamv1alpha2.User) (time.Duration, error) {
+	switch user.Status.State {
+	case iamv1alpha2.UserActive:
+		exceeded, err := r.authLimitExceeded(ctx, user)
+		if err != nil || !exceeded {
+			return 0, err
+		}
+		return r.AuthenticationOptions.AuthenticateRateLimiterDuration, r.updateUserState(ctx, user, iamv1alpha2.UserAuthLimitExceeded, authLimitExceededReason)
+	case "":
+		// the users created by identity providers with manual mapping method need to be confirmed by administrators
+		if user.Annotations[iamv1alpha2.PendingConfirmationAnnotation] == "true" {
+			return 0, r.updateUserState(ctx, user, iamv1alpha2.UserDisabled, pendingConfirmationReason)
+		}
+		return 0, r.updateUserState(ctx, user, iamv1alpha2.UserActive, "")
+	case iamv1alpha2.UserAuthLimitExceeded:
+		if user.Status.LastTransitionTime == nil {
+			return 0, r.updateUserState(ctx, user, iamv1alpha2.UserActive, "")
+		}
+		unlockAt := user.Status.LastTransitionTime.Add(r.AuthenticationOptions.AuthenticateRateLimiterDuration)
+		if remaining := time.Until(unlockAt); remaining > 0 {
+			return remaining, nil
+		}
+		return 0, r.updateUserState(ctx, user, iamv1alpha2.UserActive, "")
+	}
+	return 0, nil
+}
+
+// authLimitExceeded returns whether the failed login attempts of the user within AuthenticateRateLimiterDuration
+// reach AuthenticateRateLimiterMaxTries, the attempts before the last state transition are not counted, so that
+// the unlocked users are not locked again by the same attempts.
+func (r *Reconciler) authLimitExceeded(ctx context.Context, user *iamv1alpha2.User) (bool, error) {
+	if r.AuthenticationOptions.AuthenticateRateLimiterMaxTries <= 0 {
+		return false, nil
+	}
+
+	loginRecords := &iamv1alpha2.LoginRecordList{}
+	if err := r.List(ctx, loginRecords, client.MatchingLabels{iamv1alpha2.UserReferenceLabel: user.Name}); err != nil {
+		return false, err
+	}
+
+	since := time.Now().Add(-r.AuthenticationOptions.AuthenticateRateLimiterDuration)
+	if user.Status.LastTransitionTime != nil && user.Status.LastTransitionTime.After(since) {
+		since = user.Status.LastTransitionTime.Time
+	}
+
+	failures := 0
+	for _, loginRecord := range loginRecords.Items {
+		if !loginRecord.Spec.Success && loginRecord.CreationTimestamp.After(since) {
+			failures++
+		}
+	}
+	return failures >= r.AuthenticationOptions.AuthenticateRateLimiterMaxTries, nil
+}
+
+func (r *Reconciler) updateUserState(ctx context.Context, user *iamv1alpha2.User, state iamv1alpha2.UserState, reason string) error {
+	now := metav1.Now()
+	user = user.DeepCopy()
+	user.Status.State = state
+	user.Status.Reason = reason
+	user.Status.LastTransitionTime = &now
+	return r.Status().Update(ctx, user)
+}
+
+// syncUserGroups sets the groups of the user to the groups it's bound to by group bindings, returns whether the user is updated
+func (r *Reconciler) syncUserGroups(ctx context.Context, user *iamv1alpha2.User) (bool, error) {
+	groupBindings := &iamv1alpha2.GroupBindingList{}
+	if err := r.List(ctx, groupBindings); err != nil {
+		return false, err
+	}
+
+	groupSet := make(map[string]struct{})
+	for _, groupBinding := range groupBindings.Items {
+		// the group bindings of deleted groups are being garbage collected
+		if !groupBinding.DeletionTimestamp.IsZero() {
+			continue
+		}
+		for _, username := range groupBinding.Users {
+			if username == user.Name {
+				groupSet[groupBinding.GroupRef.Name] = struct{}{}
+				break
+			}
+		}
+	}
+
+	groups := make([]string, 0, len(groupSet))
+	for group := range groupSet {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	current := append([]string{}, user.Spec.Groups...)
+	sort.Strings(current)
+	if reflect.DeepEqual(groups, current) {
+		return false, nil
+	}
+
+	user = user.DeepCopy()
+	user.Spec.Groups = groups
+	return true, r.Update(ctx, user)
+}
+
+// encryptPassword replaces the plaintext password with bcrypt hash, returns whether the user is updated
+func (r *Reconciler) encryptPassword(ctx context.Context, user *iamv1alpha2.User) (bool, error) {
+	if user.Spec.EncryptedPassword == "" || isEncrypted(user.Spec.EncryptedPassword) {
+		return false, nil
+	}
+
+	encrypted, err := bcrypt.GenerateFromPassword([]byte(user.Spec.EncryptedPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return false, err
+	}
+
+	user = user.DeepCopy()
+	user.Spec.EncryptedPassword = string(encrypted)
+	return true, r.Update(ctx, user)
+}
+
+// isEncrypted returns whether the password is a valid bcrypt hash
+func isEncrypted(password string) bool {
+	_, err := bcrypt.Cost([]byte(password))
+	return err == nil
+}
@@ -0,0 +1,233 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	tenantv1alpha1 "github.com/sunweiwe/api/tenant/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	controllerName = "workspace-controller"
+
+	failedSynced = "FailedSync"
+
+	workspaceAdminRoleFormat = "%s-admin"
+)
+
+// Reconciler creates the admin role of each workspace and binds it to the manager of the workspace,
+// the role and the binding are owned by the workspace, so that they are garbage collected along with it.
+// The namespaces of the workspace are owned by the workspace as well, they are orphaned before the
+// workspace is deleted unless CascadeDeleteNamespaces is set.
+type Reconciler struct {
+	client.Client
+	Logger                  logr.Logger
+	Recorder                record.EventRecorder
+	MaxConcurrentReconciles int
+	CascadeDeleteNamespaces bool
+}
+
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Client == nil {
+		r.Client = mgr.GetClient()
+	}
+
+	if r.Logger.GetSink() == nil {
+		r.Logger = ctrl.Log.WithName("controllers").WithName(controllerName)
+	}
+
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor(controllerName)
+	}
+
+	if r.MaxConcurrentReconciles <= 0 {
+		r.MaxConcurrentReconciles = 1
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).Named(controllerName).WithOptions(controller.Options{
+		MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+	}).
+		For(&tenantv1alpha1.Workspace{}).
+		Owns(&iamv1alpha2.WorkspaceRole{}).
+		Owns(&iamv1alpha2.WorkspaceRoleBinding{}).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.mapNamespaceToWorkspace)).
+		Complete(r)
+}
+
+func (r *Reconciler) mapNamespaceToWorkspace(_ context.Context, obj client.Object) []reconcile.Request {
+	workspace := obj.GetLabels()[tenantv1alpha1.WorkspaceLabel]
+	if workspace == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: workspace}}}
+}
+
+// +kubebuilder:rbac:groups=tenant.horizon.io,resources=workspaces,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=iam.horizon.io,resources=workspaceroles;workspacerolebindings,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Logger.WithValues("workspace", req.NamespacedName)
+
+	workspace := &tenantv1alpha1.Workspace{}
+	if err := r.Get(ctx, req.NamespacedName, workspace); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if workspace.ObjectMeta.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(workspace, tenantv1alpha1.Finalizer) {
+			controllerutil.AddFinalizer(workspace, tenantv1alpha1.Finalizer)
+			if err := r.Update(ctx, workspace); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	} else {
+		if controllerutil.ContainsFinalizer(workspace, tenantv1alpha1.Finalizer) {
+			if !r.CascadeDeleteNamespaces {
+				if err := r.orphanNamespaces(ctx, workspace); err != nil {
+					logger.Error(err, "failed to orphan namespaces")
+					r.Recorder.Event(workspace, corev1.EventTypeWarning, failedSynced, err.Error())
+					return ctrl.Result{}, err
+				}
+			}
+			controllerutil.RemoveFinalizer(workspace, tenantv1alpha1.Finalizer)
+			if err := r.Update(ctx, workspace); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	adminRole, err := r.initWorkspaceAdminRole(ctx, workspace)
+	if err != nil {
+		logger.Error(err, "failed to init workspace admin role")
+		r.Recorder.Event(workspace, corev1.EventTypeWarning, failedSynced, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	if workspace.Spec.Manager != "" {
+		if err := r.initManagerRoleBinding(ctx, workspace, adminRole); err != nil {
+			logger.Error(err, "failed to bind workspace admin role to the manager")
+			r.Recorder.Event(workspace, corev1.EventTypeWarning, failedSynced, err.Error())
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.updateStatus(ctx, workspace); err != nil {
+		logger.Error(err, "failed to update workspace status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *Reconciler) listNamespaces(ctx context.Context, workspace *tenantv1alpha1.Workspace) ([]corev1.Namespace, error) {
+	namespaces := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaces, client.MatchingLabels{tenantv1alpha1.WorkspaceLabel: workspace.Name}); err != nil {
+		return nil, err
+	}
+	return namespaces.Items, nil
+}
+
+// updateStatus counts the namespaces of the workspace.
+func (r *Reconciler) updateStatus(ctx context.Context, workspace *tenantv1alpha1.Workspace) error {
+	namespaces, err := r.listNamespaces(ctx, workspace)
+	if err != nil {
+		return err
+	}
+	if workspace.Status.Namespaces == len(namespaces) {
+		return nil
+	}
+	workspace = workspace.DeepCopy()
+	workspace.Status.Namespaces = len(namespaces)
+	return r.Update(ctx, workspace)
+}
+
+// orphanNamespaces removes the owner references to the workspace from its namespaces,
+// so that the namespaces are not garbage collected along with the workspace.
+func (r *Reconciler) orphanNamespaces(ctx context.Context, workspace *tenantv1alpha1.Workspace) error {
+	namespaces, err := r.listNamespaces(ctx, workspace)
+	if err != nil {
+		return err
+	}
+	for i := range namespaces {
+		namespace := &namespaces[i]
+		ownerReferences := make([]metav1.OwnerReference, 0, len(namespace.OwnerReferences))
+		for _, ownerReference := range namespace.OwnerReferences {
+			if ownerReference.UID != workspace.UID {
+				ownerReferences = append(ownerReferences, ownerReference)
+			}
+		}
+		if len(ownerReferences) == len(namespace.OwnerReferences) {
+			continue
+		}
+		namespace.OwnerReferences = ownerReferences
+		if err := r.Update(ctx, namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initWorkspaceAdminRole creates the role which allows everything in the workspace.
+func (r *Reconciler) initWorkspaceAdminRole(ctx context.Context, workspace *tenantv1alpha1.Workspace) (*iamv1alpha2.WorkspaceRole, error) {
+	role := &iamv1alpha2.WorkspaceRole{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf(workspaceAdminRoleFormat, workspace.Name)}}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, role, func() error {
+		if role.Labels == nil {
+			role.Labels = make(map[string]string)
+		}
+		role.Labels[tenantv1alpha1.WorkspaceLabel] = workspace.Name
+		role.Rules = []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{rbacv1.APIGroupAll},
+				Resources: []string{rbacv1.ResourceAll},
+				Verbs:     []string{rbacv1.VerbAll},
+			},
+		}
+		return controllerutil.SetControllerReference(workspace, role, r.Scheme())
+	})
+	return role, err
+}
+
+// initManagerRoleBinding binds the admin role to the manager, named and labelled the same as the
+// workspace members added through the access management API.
+func (r *Reconciler) initManagerRoleBinding(ctx context.Context, workspace *tenantv1alpha1.Workspace, role *iamv1alpha2.WorkspaceRole) error {
+	manager := workspace.Spec.Manager
+	roleBinding := &iamv1alpha2.WorkspaceRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-%s", manager, role.Name)}}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, roleBinding, func() error {
+		if roleBinding.Labels == nil {
+			roleBinding.Labels = make(map[string]string)
+		}
+		roleBinding.Labels[iamv1alpha2.UserReferenceLabel] = manager
+		roleBinding.Labels[iamv1alpha2.RoleReferenceLabel] = role.Name
+		roleBinding.Labels[tenantv1alpha1.WorkspaceLabel] = workspace.Name
+		roleBinding.Subjects = []rbacv1.Subject{
+			{
+				APIGroup: rbacv1.GroupName,
+				Kind:     rbacv1.UserKind,
+				Name:     manager,
+			},
+		}
+		roleBinding.RoleRef = rbacv1.RoleRef{
+			APIGroup: iamv1alpha2.SchemeGroupVersion.Group,
+			Kind:     iamv1alpha2.ResourceKindWorkspaceRole,
+			Name:     role.Name,
+		}
+		return controllerutil.SetControllerReference(workspace, roleBinding, r.Scheme())
+	})
+	return err
+}
@@ -0,0 +1,86 @@
+package workspaceresourcequota
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tenantv1alpha1 "github.com/sunweiwe/api/tenant/v1alpha1"
+)
+
+const (
+	resourcePods                   corev1.ResourceName = "pods"
+	resourcePersistentVolumeClaims corev1.ResourceName = "persistentvolumeclaims"
+)
+
+// podUsage returns the resources used by the pod, named the same as ResourceQuota,
+// the pods in terminal phases use nothing.
+func podUsage(pod *corev1.Pod) corev1.ResourceList {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return corev1.ResourceList{}
+	}
+
+	requests := corev1.ResourceList{}
+	limits := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		requests = quotav1.Add(requests, container.Resources.Requests)
+		limits = quotav1.Add(limits, container.Resources.Limits)
+	}
+	// the init containers run one by one, so the max of them is taken
+	for _, container := range pod.Spec.InitContainers {
+		requests = quotav1.Max(requests, container.Resources.Requests)
+		limits = quotav1.Max(limits, container.Resources.Limits)
+	}
+
+	usage := corev1.ResourceList{resourcePods: *resource.NewQuantity(1, resource.DecimalSI)}
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if quantity, ok := requests[name]; ok {
+			usage[name] = quantity
+			usage[corev1.ResourceName("requests."+name)] = quantity
+		}
+		if quantity, ok := limits[name]; ok {
+			usage[corev1.ResourceName("limits."+name)] = quantity
+		}
+	}
+	return usage
+}
+
+// persistentVolumeClaimUsage returns the resources used by the persistent volume claim, named the same as ResourceQuota.
+func persistentVolumeClaimUsage(pvc *corev1.PersistentVolumeClaim) corev1.ResourceList {
+	usage := corev1.ResourceList{resourcePersistentVolumeClaims: *resource.NewQuantity(1, resource.DecimalSI)}
+	if quantity, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		usage[corev1.ResourceRequestsStorage] = quantity
+	}
+	return usage
+}
+
+// workspaceUsage sums the resources used by the pods and the persistent volume claims of all the namespaces of the workspace.
+func workspaceUsage(ctx context.Context, c client.Client, workspace string) (corev1.ResourceList, error) {
+	namespaces := &corev1.NamespaceList{}
+	if err := c.List(ctx, namespaces, client.MatchingLabels{tenantv1alpha1.WorkspaceLabel: workspace}); err != nil {
+		return nil, err
+	}
+
+	usage := corev1.ResourceList{}
+	for _, namespace := range namespaces.Items {
+		pods := &corev1.PodList{}
+		if err := c.List(ctx, pods, client.InNamespace(namespace.Name)); err != nil {
+			return nil, err
+		}
+		for i := range pods.Items {
+			usage = quotav1.Add(usage, podUsage(&pods.Items[i]))
+		}
+
+		pvcs := &corev1.PersistentVolumeClaimList{}
+		if err := c.List(ctx, pvcs, client.InNamespace(namespace.Name)); err != nil {
+			return nil, err
+		}
+		for i := range pvcs.Items {
+			usage = quotav1.Add(usage, persistentVolumeClaimUsage(&pvcs.Items[i]))
+		}
+	}
+	return usage, nil
+}
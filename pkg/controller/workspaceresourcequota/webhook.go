@@ -0,0 +1,157 @@
+package workspaceresourcequota
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	tenantv1alpha1 "github.com/sunweiwe/api/tenant/v1alpha1"
+)
+
+// WebhookPath is the path the validating webhook is served on
+const WebhookPath = "/validate-workspace-resource-quota"
+
+// Validator rejects the pods and the persistent volume claims that exceed the quotas of their workspace.
+// The usage is taken from the status of the quotas, the resources requested are added to it with optimistic
+// concurrency before the request is allowed, so that the concurrent requests can not exceed the quotas
+// together. The usage is recomputed from the objects by the Reconciler afterwards, which releases the
+// resources reserved for the requests rejected later on, e.g. by other admission webhooks.
+// +kubebuilder:webhook:path=/validate-workspace-resource-quota,mutating=false,failurePolicy=Fail,sideEffects=NoneOnDryRun,groups="",resources=pods;persistentvolumeclaims,verbs=create;update,versions=v1,name=workspaceresourcequotas.tenant.horizon.io,admissionReviewVersions=v1
+type Validator struct {
+	client.Client
+	// APIReader reads the quotas bypassing the cache, the reservations conflict with the stale quotas
+	APIReader client.Reader
+	Decoder   *admission.Decoder
+}
+
+func (v *Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	delta, err := v.requestUsage(req)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if quotav1.IsZero(delta) {
+		return admission.Allowed("")
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := v.Get(ctx, types.NamespacedName{Name: req.Namespace}, namespace); err != nil {
+		if apierrors.IsNotFound(err) {
+			return admission.Allowed("")
+		}
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	workspace := namespace.Labels[tenantv1alpha1.WorkspaceLabel]
+	if workspace == "" {
+		return admission.Allowed("")
+	}
+
+	quotas, err := listWorkspaceQuotas(ctx, v.Client, workspace)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	for _, quota := range quotas {
+		if quotav1.IsZero(quotav1.Mask(delta, quotav1.ResourceNames(quota.Spec.Hard))) {
+			continue
+		}
+		if denied := v.reserve(ctx, types.NamespacedName{Name: quota.Name}, delta, req.DryRun != nil && *req.DryRun); denied != nil {
+			return *denied
+		}
+	}
+
+	return admission.Allowed("")
+}
+
+// reserve adds the resources requested to the usage of the quota, the request is denied if the quota is
+// exceeded. The usage is only checked for the dry run requests.
+func (v *Validator) reserve(ctx context.Context, key types.NamespacedName, delta corev1.ResourceList, dryRun bool) *admission.Response {
+	var denied *admission.Response
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		denied = nil
+		quota := &tenantv1alpha1.WorkspaceResourceQuota{}
+		if err := v.APIReader.Get(ctx, key, quota); err != nil {
+			return err
+		}
+
+		hard := quota.Spec.Hard
+		requested := quotav1.Mask(delta, quotav1.ResourceNames(hard))
+		used := quotav1.Add(quota.Status.Used, requested)
+		if allowed, exceeded := quotav1.LessThanOrEqual(used, hard); !allowed {
+			response := admission.Denied(exceededMessage(quota.Name, exceeded, requested, quota.Status.Used, hard))
+			denied = &response
+			return nil
+		}
+		if dryRun {
+			return nil
+		}
+
+		quota.Status.Used = quotav1.Mask(used, quotav1.ResourceNames(hard))
+		return v.Update(ctx, quota)
+	})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		response := admission.Errored(http.StatusInternalServerError, err)
+		return &response
+	}
+	return denied
+}
+
+// requestUsage returns the resources added by the request, the usage of the old object is
+// subtracted on update, so that only the increase is checked.
+func (v *Validator) requestUsage(req admission.Request) (corev1.ResourceList, error) {
+	switch req.Kind.Kind {
+	case "Pod":
+		// the resources of the pods are immutable
+		if req.Operation != admissionv1.Create {
+			return corev1.ResourceList{}, nil
+		}
+		pod := &corev1.Pod{}
+		if err := v.Decoder.Decode(req, pod); err != nil {
+			return nil, err
+		}
+		return podUsage(pod), nil
+	case "PersistentVolumeClaim":
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := v.Decoder.Decode(req, pvc); err != nil {
+			return nil, err
+		}
+		usage := persistentVolumeClaimUsage(pvc)
+		if req.Operation == admissionv1.Update {
+			old := &corev1.PersistentVolumeClaim{}
+			if err := v.Decoder.DecodeRaw(req.OldObject, old); err != nil {
+				return nil, err
+			}
+			usage = quotav1.SubtractWithNonNegativeResult(usage, persistentVolumeClaimUsage(old))
+		}
+		return usage, nil
+	default:
+		return corev1.ResourceList{}, nil
+	}
+}
+
+func exceededMessage(quota string, exceeded []corev1.ResourceName, requested, used, hard corev1.ResourceList) string {
+	formatted := make([]string, 0, len(exceeded))
+	for _, name := range exceeded {
+		requestedQuantity, usedQuantity, hardQuantity := requested[name], used[name], hard[name]
+		formatted = append(formatted, fmt.Sprintf("%s: requested %s, used %s, limited %s",
+			name, requestedQuantity.String(), usedQuantity.String(), hardQuantity.String()))
+	}
+	return fmt.Sprintf("exceeded workspace quota: %s, %s", quota, strings.Join(formatted, "; "))
+}
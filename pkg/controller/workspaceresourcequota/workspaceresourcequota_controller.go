@@ -0,0 +1,182 @@
+package workspaceresourcequota
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	tenantv1alpha1 "github.com/sunweiwe/api/tenant/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	controllerName = "workspaceresourcequota-controller"
+
+	failedSynced = "FailedSync"
+)
+
+// Reconciler computes the resources used by the workspaces in the status of their quotas,
+// the quotas are owned by the workspaces labelled on them.
+type Reconciler struct {
+	client.Client
+	Logger                  logr.Logger
+	Recorder                record.EventRecorder
+	MaxConcurrentReconciles int
+}
+
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Client == nil {
+		r.Client = mgr.GetClient()
+	}
+
+	if r.Logger.GetSink() == nil {
+		r.Logger = ctrl.Log.WithName("controllers").WithName(controllerName)
+	}
+
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor(controllerName)
+	}
+
+	if r.MaxConcurrentReconciles <= 0 {
+		r.MaxConcurrentReconciles = 1
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).Named(controllerName).WithOptions(controller.Options{
+		MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+	}).
+		For(&tenantv1alpha1.WorkspaceResourceQuota{}).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.mapNamespaceToQuotas)).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.mapNamespacedObjectToQuotas)).
+		Watches(&corev1.PersistentVolumeClaim{}, handler.EnqueueRequestsFromMapFunc(r.mapNamespacedObjectToQuotas)).
+		Complete(r)
+}
+
+func (r *Reconciler) mapNamespaceToQuotas(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.workspaceQuotaRequests(ctx, obj.GetLabels()[tenantv1alpha1.WorkspaceLabel])
+}
+
+func (r *Reconciler) mapNamespacedObjectToQuotas(ctx context.Context, obj client.Object) []reconcile.Request {
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: obj.GetNamespace()}, namespace); err != nil {
+		return nil
+	}
+	return r.workspaceQuotaRequests(ctx, namespace.Labels[tenantv1alpha1.WorkspaceLabel])
+}
+
+func (r *Reconciler) workspaceQuotaRequests(ctx context.Context, workspace string) []reconcile.Request {
+	if workspace == "" {
+		return nil
+	}
+
+	quotas, err := listWorkspaceQuotas(ctx, r.Client, workspace)
+	if err != nil {
+		r.Logger.Error(err, "failed to list workspace resource quotas", "workspace", workspace)
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(quotas))
+	for _, quota := range quotas {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: quota.Name}})
+	}
+	return requests
+}
+
+// +kubebuilder:rbac:groups=tenant.horizon.io,resources=workspaceresourcequotas,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=tenant.horizon.io,resources=workspaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces;pods;persistentvolumeclaims,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Logger.WithValues("workspaceresourcequota", req.NamespacedName)
+
+	quota := &tenantv1alpha1.WorkspaceResourceQuota{}
+	if err := r.Get(ctx, req.NamespacedName, quota); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !quota.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	workspace := quota.Labels[tenantv1alpha1.WorkspaceLabel]
+	if workspace == "" {
+		logger.V(4).Info("workspace resource quota is not labelled with a workspace")
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.bindWorkspace(ctx, quota, workspace); err != nil {
+		logger.Error(err, "failed to bind workspace")
+		r.Recorder.Event(quota, corev1.EventTypeWarning, failedSynced, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	usage, err := workspaceUsage(ctx, r.Client, workspace)
+	if err != nil {
+		logger.Error(err, "failed to compute workspace usage")
+		r.Recorder.Event(quota, corev1.EventTypeWarning, failedSynced, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	used := quotav1.Mask(usage, quotav1.ResourceNames(quota.Spec.Hard))
+	// the resources not used yet are reported as zero
+	for _, name := range quotav1.ResourceNames(quota.Spec.Hard) {
+		if _, ok := used[name]; !ok {
+			quantity := quota.Spec.Hard[name].DeepCopy()
+			quantity.Set(0)
+			used[name] = quantity
+		}
+	}
+
+	if quotav1.Equals(quota.Status.Hard, quota.Spec.Hard) && quotav1.Equals(quota.Status.Used, used) {
+		return ctrl.Result{}, nil
+	}
+
+	quota = quota.DeepCopy()
+	quota.Status.Hard = quota.Spec.Hard
+	quota.Status.Used = used
+	if err := r.Update(ctx, quota); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// bindWorkspace makes the workspace the owner of the quota, so that the quota is garbage collected along with it.
+func (r *Reconciler) bindWorkspace(ctx context.Context, quota *tenantv1alpha1.WorkspaceResourceQuota, name string) error {
+	workspace := &tenantv1alpha1.Workspace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, workspace); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, ownerReference := range quota.OwnerReferences {
+		if ownerReference.UID == workspace.UID {
+			return nil
+		}
+	}
+
+	if err := controllerutil.SetOwnerReference(workspace, quota, r.Scheme()); err != nil {
+		return err
+	}
+	return r.Update(ctx, quota)
+}
+
+// listWorkspaceQuotas lists the quotas labelled with the workspace.
+func listWorkspaceQuotas(ctx context.Context, c client.Client, workspace string) ([]tenantv1alpha1.WorkspaceResourceQuota, error) {
+	quotas := &tenantv1alpha1.WorkspaceResourceQuotaList{}
+	if err := c.List(ctx, quotas, client.MatchingLabels{tenantv1alpha1.WorkspaceLabel: workspace}); err != nil {
+		return nil, err
+	}
+	return quotas.Items, nil
+}
@@ -0,0 +1,306 @@
+package workspacetemplate
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/sunweiwe/horizon/pkg/client/clientset"
+	"github.com/sunweiwe/horizon/pkg/controller/cluster"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clusterv1alpha1 "github.com/sunweiwe/api/cluster/v1alpha1"
+	tenantv1alpha1 "github.com/sunweiwe/api/tenant/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	controllerName = "workspacetemplate-controller"
+
+	failedSynced = "FailedSync"
+
+	// retryPeriod is the period to retry the placement on the clusters failed
+	retryPeriod = time.Minute
+
+	// removalTimeout is the period to retry the removal of the workspaces on a deleted workspace template,
+	// the workspaces on the clusters still unreachable after it are left behind
+	removalTimeout = 10 * time.Minute
+)
+
+// Reconciler places the workspace of each workspace template on the selected member clusters
+// through the clients built from the cluster connections, and reports the placement per cluster.
+type Reconciler struct {
+	client.Client
+	Logger                  logr.Logger
+	Recorder                record.EventRecorder
+	MaxConcurrentReconciles int
+	ClusterClients          *cluster.ClusterClients
+}
+
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Client == nil {
+		r.Client = mgr.GetClient()
+	}
+
+	if r.Logger.GetSink() == nil {
+		r.Logger = ctrl.Log.WithName("controllers").WithName(controllerName)
+	}
+
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor(controllerName)
+	}
+
+	if r.MaxConcurrentReconciles <= 0 {
+		r.MaxConcurrentReconciles = 1
+	}
+
+	if r.ClusterClients == nil {
+		r.ClusterClients = cluster.NewClusterClients()
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).Named(controllerName).WithOptions(controller.Options{
+		MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+	}).
+		For(&tenantv1alpha1.WorkspaceTemplate{}).
+		Watches(&clusterv1alpha1.Cluster{}, handler.EnqueueRequestsFromMapFunc(r.mapClusterToWorkspaceTemplates)).
+		Complete(r)
+}
+
+// mapClusterToWorkspaceTemplates enqueues all the workspace templates, since any of them may select the cluster.
+func (r *Reconciler) mapClusterToWorkspaceTemplates(ctx context.Context, _ client.Object) []reconcile.Request {
+	workspaceTemplates := &tenantv1alpha1.WorkspaceTemplateList{}
+	if err := r.List(ctx, workspaceTemplates); err != nil {
+		r.Logger.Error(err, "failed to list workspace templates")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(workspaceTemplates.Items))
+	for _, workspaceTemplate := range workspaceTemplates.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: workspaceTemplate.Name}})
+	}
+	return requests
+}
+
+// +kubebuilder:rbac:groups=tenant.horizon.io,resources=workspacetemplates,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=cluster.horizon.io,resources=clusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Logger.WithValues("workspacetemplate", req.NamespacedName)
+
+	workspaceTemplate := &tenantv1alpha1.WorkspaceTemplate{}
+	if err := r.Get(ctx, req.NamespacedName, workspaceTemplate); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if workspaceTemplate.ObjectMeta.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(workspaceTemplate, tenantv1alpha1.Finalizer) {
+			controllerutil.AddFinalizer(workspaceTemplate, tenantv1alpha1.Finalizer)
+			if err := r.Update(ctx, workspaceTemplate); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	} else {
+		if controllerutil.ContainsFinalizer(workspaceTemplate, tenantv1alpha1.Finalizer) {
+			for _, status := range workspaceTemplate.Status.Clusters {
+				if err := r.removeWorkspace(ctx, status.Name, workspaceTemplate); err != nil {
+					// the deletion is not blocked forever by the clusters unreachable
+					if time.Since(workspaceTemplate.DeletionTimestamp.Time) < removalTimeout {
+						logger.Error(err, "failed to remove workspace", "cluster", status.Name)
+						r.Recorder.Event(workspaceTemplate, corev1.EventTypeWarning, failedSynced, err.Error())
+						return ctrl.Result{RequeueAfter: retryPeriod}, nil
+					}
+					logger.Error(err, "failed to remove workspace, leaving it on the cluster", "cluster", status.Name)
+					r.Recorder.Eventf(workspaceTemplate, corev1.EventTypeWarning, failedSynced,
+						"Failed to remove workspace from cluster %s in %s, leaving it on the cluster: %v", status.Name, removalTimeout, err)
+				}
+			}
+			controllerutil.RemoveFinalizer(workspaceTemplate, tenantv1alpha1.Finalizer)
+			if err := r.Update(ctx, workspaceTemplate); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	selected, err := r.selectClusters(ctx, workspaceTemplate.Spec.Placement)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// remove the workspaces from the clusters no longer selected
+	for _, status := range workspaceTemplate.Status.Clusters {
+		if selected.Has(status.Name) {
+			continue
+		}
+		if err := r.removeWorkspace(ctx, status.Name, workspaceTemplate); err != nil {
+			logger.Error(err, "failed to remove workspace", "cluster", status.Name)
+			r.Recorder.Event(workspaceTemplate, corev1.EventTypeWarning, failedSynced, err.Error())
+			return ctrl.Result{}, err
+		}
+	}
+
+	failed := false
+	clusters := make([]tenantv1alpha1.ClusterPlacementStatus, 0, selected.Len())
+	for _, name := range sets.List(selected) {
+		status := tenantv1alpha1.ClusterPlacementStatus{Name: name, Phase: tenantv1alpha1.PlacementPlaced}
+		if err := r.placeWorkspace(ctx, name, workspaceTemplate); err != nil {
+			logger.Error(err, "failed to place workspace", "cluster", name)
+			status.Phase = tenantv1alpha1.PlacementFailed
+			status.Message = err.Error()
+			failed = true
+		}
+		clusters = append(clusters, status)
+	}
+
+	if err := r.updateStatus(ctx, workspaceTemplate, clusters); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if failed {
+		return ctrl.Result{RequeueAfter: retryPeriod}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// selectClusters returns the names of the clusters listed in the placement or matching its selector.
+func (r *Reconciler) selectClusters(ctx context.Context, placement tenantv1alpha1.Placement) (sets.Set[string], error) {
+	selected := sets.New[string]()
+	for _, reference := range placement.Clusters {
+		selected.Insert(reference.Name)
+	}
+
+	if placement.ClusterSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(placement.ClusterSelector)
+		if err != nil {
+			return nil, err
+		}
+		if !selector.Empty() {
+			clusters := &clusterv1alpha1.ClusterList{}
+			if err := r.List(ctx, clusters, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+				return nil, err
+			}
+			for _, cluster := range clusters.Items {
+				selected.Insert(cluster.Name)
+			}
+		}
+	}
+
+	return selected, nil
+}
+
+// clusterClient returns the shared client of the member cluster, which is rebuilt once the connection changes.
+func (r *Reconciler) clusterClient(ctx context.Context, name string) (clientset.Interface, error) {
+	memberCluster := &clusterv1alpha1.Cluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, memberCluster); err != nil {
+		return nil, err
+	}
+
+	return r.ClusterClients.Horizon(memberCluster)
+}
+
+// placeWorkspace creates or updates the workspace on the cluster, the workspaces which are not
+// placed by the workspace template are left untouched.
+func (r *Reconciler) placeWorkspace(ctx context.Context, clusterName string, workspaceTemplate *tenantv1alpha1.WorkspaceTemplate) error {
+	clusterClient, err := r.clusterClient(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+
+	template := workspaceTemplate.Spec.Template
+	workspace, err := clusterClient.TenantV1alpha1().Workspaces().Get(ctx, workspaceTemplate.Name, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		workspace = &tenantv1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        workspaceTemplate.Name,
+				Labels:      labels.Merge(template.Labels, labels.Set{tenantv1alpha1.WorkspaceTemplateLabel: workspaceTemplate.Name}),
+				Annotations: template.Annotations,
+			},
+			Spec: template.Spec,
+		}
+		_, err = clusterClient.TenantV1alpha1().Workspaces().Create(ctx, workspace, metav1.CreateOptions{})
+		return err
+	}
+
+	if workspace.Labels[tenantv1alpha1.WorkspaceTemplateLabel] != workspaceTemplate.Name {
+		return fmt.Errorf("workspace %s is not placed by the workspace template", workspace.Name)
+	}
+
+	updated := workspace.DeepCopy()
+	updated.Labels = labels.Merge(updated.Labels, template.Labels)
+	if len(template.Annotations) > 0 {
+		updated.Annotations = labels.Merge(updated.Annotations, template.Annotations)
+	}
+	updated.Spec = template.Spec
+	if reflect.DeepEqual(workspace, updated) {
+		return nil
+	}
+
+	_, err = clusterClient.TenantV1alpha1().Workspaces().Update(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
+
+// removeWorkspace deletes the workspace placed by the workspace template from the cluster,
+// nothing is deleted if the cluster is gone. The requests to an unreachable cluster time out.
+func (r *Reconciler) removeWorkspace(ctx context.Context, clusterName string, workspaceTemplate *tenantv1alpha1.WorkspaceTemplate) error {
+	clusterClient, err := r.clusterClient(ctx, clusterName)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	workspace, err := clusterClient.TenantV1alpha1().Workspaces().Get(ctx, workspaceTemplate.Name, metav1.GetOptions{})
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if workspace.Labels[tenantv1alpha1.WorkspaceTemplateLabel] != workspaceTemplate.Name {
+		return nil
+	}
+
+	err = clusterClient.TenantV1alpha1().Workspaces().Delete(ctx, workspace.Name, metav1.DeleteOptions{})
+	return client.IgnoreNotFound(err)
+}
+
+// updateStatus updates the placement status, the last update time of a cluster is only
+// refreshed when its phase or message changes.
+func (r *Reconciler) updateStatus(ctx context.Context, workspaceTemplate *tenantv1alpha1.WorkspaceTemplate, clusters []tenantv1alpha1.ClusterPlacementStatus) error {
+	previous := make(map[string]tenantv1alpha1.ClusterPlacementStatus, len(workspaceTemplate.Status.Clusters))
+	for _, status := range workspaceTemplate.Status.Clusters {
+		previous[status.Name] = status
+	}
+
+	changed := len(clusters) != len(workspaceTemplate.Status.Clusters)
+	now := metav1.Now()
+	for i := range clusters {
+		old, ok := previous[clusters[i].Name]
+		if ok && old.Phase == clusters[i].Phase && old.Message == clusters[i].Message {
+			clusters[i].LastUpdateTime = old.LastUpdateTime
+			continue
+		}
+		clusters[i].LastUpdateTime = now
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+
+	workspaceTemplate = workspaceTemplate.DeepCopy()
+	workspaceTemplate.Status.Clusters = clusters
+	return r.Update(ctx, workspaceTemplate)
+}
@@ -1,45 +1,860 @@
 package v1alpha2
 
 import (
+	"fmt"
+	"io"
+	"net/http"
+
 	"github.com/emicklei/go-restful/v3"
 	"github.com/sunweiwe/horizon/pkg/api"
 	"github.com/sunweiwe/horizon/pkg/apiserver/authorization/authorizer"
 	"github.com/sunweiwe/horizon/pkg/apiserver/query"
+	"github.com/sunweiwe/horizon/pkg/apiserver/request"
+	"github.com/sunweiwe/horizon/pkg/models/iam/am"
+	"github.com/sunweiwe/horizon/pkg/models/iam/group"
 	"github.com/sunweiwe/horizon/pkg/models/iam/im"
+	"github.com/sunweiwe/horizon/pkg/models/kubeconfig"
+	"k8s.io/apiserver/pkg/authentication/user"
 
 	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8srequest "k8s.io/apiserver/pkg/endpoints/request"
 )
 
+type PasswordReset struct {
+	CurrentPassword string `json:"currentPassword"`
+	Password        string `json:"password"`
+}
+
+type UserStateUpdate struct {
+	State iamv1alpha2.UserState `json:"state"`
+}
+
+// Member binds a role of the scope to the user
+type Member struct {
+	Username string `json:"username"`
+	RoleRef  string `json:"roleRef"`
+}
+
+// SubjectAccessReview checks whether a user can perform an action, the Horizon-scope analogue of
+// the Kubernetes SubjectAccessReview.
+type SubjectAccessReview struct {
+	Spec   SubjectAccessReviewSpec   `json:"spec"`
+	Status SubjectAccessReviewStatus `json:"status,omitempty"`
+}
+
+// SubjectAccessReviewSpec holds the attributes of the action to review, the user and groups are
+// ignored by the self subject access review.
+type SubjectAccessReviewSpec struct {
+	User   string   `json:"user,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+
+	Verb            string `json:"verb,omitempty"`
+	Cluster         string `json:"cluster,omitempty"`
+	Workspace       string `json:"workspace,omitempty"`
+	Namespace       string `json:"namespace,omitempty"`
+	DevOps          string `json:"devops,omitempty"`
+	APIGroup        string `json:"apiGroup,omitempty"`
+	APIVersion      string `json:"apiVersion,omitempty"`
+	Resource        string `json:"resource,omitempty"`
+	Subresource     string `json:"subresource,omitempty"`
+	Name            string `json:"name,omitempty"`
+	Path            string `json:"path,omitempty"`
+	ResourceScope   string `json:"resourceScope,omitempty"`
+	ResourceRequest bool   `json:"resourceRequest"`
+}
+
+type SubjectAccessReviewStatus struct {
+	Allowed         bool   `json:"allowed"`
+	Denied          bool   `json:"denied,omitempty"`
+	Reason          string `json:"reason,omitempty"`
+	EvaluationError string `json:"evaluationError,omitempty"`
+}
+
 type iamHandler struct {
 	im         im.IdentityManagementInterface
+	am         am.AccessManagementInterface
+	group      group.GroupOperator
+	kubeconfig kubeconfig.Interface
 	authorizer authorizer.Authorizer
+	// requestInfoResolver resolves the scope of the access reviews as the requests are resolved
+	requestInfoResolver *request.RequestInfoFactory
 }
 
-func newHandler(im im.IdentityManagementInterface, authorizer authorizer.Authorizer) *iamHandler {
+func newHandler(im im.IdentityManagementInterface, am am.AccessManagementInterface, group group.GroupOperator, kubeconfig kubeconfig.Interface,
+	authorizer authorizer.Authorizer, requestInfoResolver *request.RequestInfoFactory) *iamHandler {
 	return &iamHandler{
-		im:         im,
-		authorizer: authorizer,
+		im:                  im,
+		am:                  am,
+		group:               group,
+		kubeconfig:          kubeconfig,
+		authorizer:          authorizer,
+		requestInfoResolver: requestInfoResolver,
+	}
+}
+
+func (h *iamHandler) CreateUser(request *restful.Request, response *restful.Response) {
+	var user iamv1alpha2.User
+	if err := request.ReadEntity(&user); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
 	}
+
+	created, err := h.im.CreateUser(&user)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusCreated, created)
 }
 
 func (h *iamHandler) ListUsers(request *restful.Request, response *restful.Response) {
-	queryParam := query.ParseQueryParameter(request)
-	data, err := h.im.ListUsers(queryParam)
+	queryParam, err := query.ParseQueryParameter(request)
+	if err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+	result, err := h.im.ListUsers(queryParam)
 	if err != nil {
 		api.HandleInternalError(response, request, err)
 		return
 	}
 
-	for i, item := range data.Items {
-		user := item.(*iamv1alpha2.User)
-		user = user.DeepCopy()
+	response.WriteEntity(result)
+}
+
+func (h *iamHandler) DescribeUser(request *restful.Request, response *restful.Response) {
+	username := request.PathParameter("user")
+	user, err := h.im.DescribeUser(username)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(user)
+}
+
+func (h *iamHandler) UpdateUser(request *restful.Request, response *restful.Response) {
+	username := request.PathParameter("user")
+
+	var user iamv1alpha2.User
+	if err := request.ReadEntity(&user); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	if user.Name != username {
+		api.HandleBadRequest(response, request, fmt.Errorf("the name of the object (%s) does not match the name on the URL (%s)", user.Name, username))
+		return
+	}
+
+	updated, err := h.im.UpdateUser(&user)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(updated)
+}
+
+func (h *iamHandler) PatchUser(request *restful.Request, response *restful.Response) {
+	username := request.PathParameter("user")
+
+	patch, err := io.ReadAll(request.Request.Body)
+	if err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	patched, err := h.im.PatchUser(username, patch)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(patched)
+}
+
+func (h *iamHandler) DeleteUser(request *restful.Request, response *restful.Response) {
+	username := request.PathParameter("user")
+	if err := h.im.DeleteUser(username); err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+}
+
+func (h *iamHandler) UpdateUserState(request *restful.Request, response *restful.Response) {
+	username := request.PathParameter("user")
+
+	var stateUpdate UserStateUpdate
+	if err := request.ReadEntity(&stateUpdate); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	user, err := h.im.UpdateUserState(username, stateUpdate.State)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(user)
+}
+
+func (h *iamHandler) ModifyPassword(request *restful.Request, response *restful.Response) {
+	username := request.PathParameter("user")
+
+	var passwordReset PasswordReset
+	if err := request.ReadEntity(&passwordReset); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	operator, ok := k8srequest.UserFrom(request.Request.Context())
+	if !ok {
+		err := apierrors.NewForbidden(iamv1alpha2.Resource("users"), username, fmt.Errorf("password can only be modified by the user"))
+		api.HandleForbidden(response, request, err)
+		return
+	}
+
+	if passwordReset.Password == "" {
+		api.HandleBadRequest(response, request, fmt.Errorf("password must not be empty"))
+		return
+	}
+
+	// the user changes the password with the current one, the operators granted to update the passwords
+	// of all the users reset it without, e.g. when the user forgot it
+	if operator.GetName() == username {
+		if err := h.im.PasswordVerify(username, passwordReset.CurrentPassword); err != nil {
+			if err == im.ErrIncorrectPassword {
+				api.HandleBadRequest(response, request, fmt.Errorf("incorrect current password"))
+				return
+			}
+			api.HandleInternalError(response, request, err)
+			return
+		}
+	} else {
+		decision, reason, err := h.authorizePasswordReset(operator, username)
+		if err != nil {
+			api.HandleInternalError(response, request, err)
+			return
+		}
+		if decision != authorizer.DecisionAllow {
+			err = apierrors.NewForbidden(iamv1alpha2.Resource("users"), username, fmt.Errorf("password can only be reset by the user or the operators granted at the global scope: %s", reason))
+			api.HandleForbidden(response, request, err)
+			return
+		}
+	}
+
+	if err := h.im.ModifyPassword(username, passwordReset.Password); err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+}
+
+// authorizePasswordReset authorizes the operator to reset the password of the user, it's granted by the
+// global roles since the users are global resources
+func (h *iamHandler) authorizePasswordReset(operator user.Info, username string) (authorizer.Decision, string, error) {
+	return h.authorizer.Authorize(&authorizer.AtrributesRecord{
+		User:            operator,
+		Verb:            request.VerbUpdate,
+		APIGroup:        iamv1alpha2.SchemeGroupVersion.Group,
+		APIVersion:      iamv1alpha2.SchemeGroupVersion.Version,
+		Resource:        iamv1alpha2.ResourcePluralUser,
+		Subresource:     "password",
+		Name:            username,
+		ResourceScope:   request.GlobalScope,
+		ResourceRequest: true,
+	})
+}
+
+func (h *iamHandler) GetKubeConfig(request *restful.Request, response *restful.Response) {
+	username := request.PathParameter("user")
+
+	// the kubeconfig carries the token of the user, it is only handed to the user themself
+	operator, ok := k8srequest.UserFrom(request.Request.Context())
+	if !ok || operator.GetName() != username {
+		err := apierrors.NewForbidden(iamv1alpha2.Resource("users"), username, fmt.Errorf("kubeconfig can only be retrieved by the user"))
+		api.HandleForbidden(response, request, err)
+		return
+	}
+
+	data, err := h.kubeconfig.GetKubeConfig(username)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.Write([]byte(data))
+}
+
+func (h *iamHandler) ListUserLoginRecords(request *restful.Request, response *restful.Response) {
+	username := request.PathParameter("user")
+	queryParam, err := query.ParseQueryParameter(request)
+	if err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+	result, err := h.im.ListLoginRecords(username, queryParam)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(result)
+}
+
+func (h *iamHandler) ListWorkspaceGroups(request *restful.Request, response *restful.Response) {
+	workspace := request.PathParameter("workspace")
+	queryParam, err := query.ParseQueryParameter(request)
+	if err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	result, err := h.group.ListGroups(workspace, queryParam)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(result)
+}
+
+func (h *iamHandler) CreateGroup(request *restful.Request, response *restful.Response) {
+	workspace := request.PathParameter("workspace")
+
+	var group iamv1alpha2.Group
+	if err := request.ReadEntity(&group); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	created, err := h.group.CreateGroup(workspace, &group)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusCreated, created)
+}
+
+func (h *iamHandler) DescribeGroup(request *restful.Request, response *restful.Response) {
+	workspace := request.PathParameter("workspace")
+	groupName := request.PathParameter("group")
+
+	group, err := h.group.DescribeGroup(workspace, groupName)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(group)
+}
+
+func (h *iamHandler) UpdateGroup(request *restful.Request, response *restful.Response) {
+	workspace := request.PathParameter("workspace")
+	groupName := request.PathParameter("group")
+
+	var group iamv1alpha2.Group
+	if err := request.ReadEntity(&group); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	if group.Name != groupName {
+		api.HandleBadRequest(response, request, fmt.Errorf("the name of the object (%s) does not match the name on the URL (%s)", group.Name, groupName))
+		return
+	}
+
+	updated, err := h.group.UpdateGroup(workspace, &group)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(updated)
+}
+
+func (h *iamHandler) DeleteGroup(request *restful.Request, response *restful.Response) {
+	workspace := request.PathParameter("workspace")
+	groupName := request.PathParameter("group")
+
+	if err := h.group.DeleteGroup(workspace, groupName); err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+}
+
+func (h *iamHandler) ListGroupMembers(request *restful.Request, response *restful.Response) {
+	workspace := request.PathParameter("workspace")
+	groupName := request.PathParameter("group")
+	queryParam, err := query.ParseQueryParameter(request)
+	if err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	result, err := h.group.ListGroupMembers(workspace, groupName, queryParam)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(result)
+}
+
+func (h *iamHandler) ListGroupBindings(request *restful.Request, response *restful.Response) {
+	workspace := request.PathParameter("workspace")
+	queryParam, err := query.ParseQueryParameter(request)
+	if err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	result, err := h.group.ListGroupBindings(workspace, queryParam)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(result)
+}
+
+func (h *iamHandler) CreateGroupBinding(request *restful.Request, response *restful.Response) {
+	workspace := request.PathParameter("workspace")
+
+	var groupBinding iamv1alpha2.GroupBinding
+	if err := request.ReadEntity(&groupBinding); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	created, err := h.group.CreateGroupBinding(workspace, &groupBinding)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusCreated, created)
+}
+
+func (h *iamHandler) DeleteGroupBinding(request *restful.Request, response *restful.Response) {
+	workspace := request.PathParameter("workspace")
+	groupBinding := request.PathParameter("groupbinding")
+
+	if err := h.group.DeleteGroupBinding(workspace, groupBinding); err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+}
+
+func (h *iamHandler) ListGlobalRoles(request *restful.Request, response *restful.Response) {
+	queryParam, err := query.ParseQueryParameter(request)
+	if err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+	result, err := h.am.ListGlobalRoles(queryParam)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(result)
+}
+
+func (h *iamHandler) DescribeGlobalRole(request *restful.Request, response *restful.Response) {
+	globalRole, err := h.am.GetGlobalRole(request.PathParameter("globalrole"))
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(globalRole)
+}
+
+func (h *iamHandler) CreateOrUpdateGlobalRole(request *restful.Request, response *restful.Response) {
+	var globalRole iamv1alpha2.GlobalRole
+	if err := request.ReadEntity(&globalRole); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	if name := request.PathParameter("globalrole"); name != "" && globalRole.Name != name {
+		api.HandleBadRequest(response, request, fmt.Errorf("the name of the object (%s) does not match the name on the URL (%s)", globalRole.Name, name))
+		return
+	}
+
+	updated, err := h.am.CreateOrUpdateGlobalRole(&globalRole)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(updated)
+}
+
+func (h *iamHandler) DeleteGlobalRole(request *restful.Request, response *restful.Response) {
+	if err := h.am.DeleteGlobalRole(request.PathParameter("globalrole")); err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+}
+
+func (h *iamHandler) RetrieveGlobalRoleOfUser(request *restful.Request, response *restful.Response) {
+	globalRole, err := h.am.GetGlobalRoleOfUser(request.PathParameter("user"))
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(globalRole)
+}
+
+func (h *iamHandler) UpdateGlobalRoleOfUser(request *restful.Request, response *restful.Response) {
+	username := request.PathParameter("user")
 
-		data.Items[i] = user
+	var member Member
+	if err := request.ReadEntity(&member); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	if _, err := h.im.DescribeUser(username); err != nil {
+		api.HandleError(response, request, err)
+		return
 	}
 
-	u := iamv1alpha2.User{}
-	u.Name = "sun"
-	data.Items = append(data.Items, u)
+	if err := h.am.CreateOrUpdateGlobalRoleBinding(username, member.RoleRef); err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
 
-	response.WriteEntity(nil)
+	response.WriteEntity(Member{Username: username, RoleRef: member.RoleRef})
+}
+
+func (h *iamHandler) ListWorkspaceRoles(request *restful.Request, response *restful.Response) {
+	queryParam, err := query.ParseQueryParameter(request)
+	if err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+	result, err := h.am.ListWorkspaceRoles(request.PathParameter("workspace"), queryParam)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(result)
+}
+
+func (h *iamHandler) DescribeWorkspaceRole(request *restful.Request, response *restful.Response) {
+	workspaceRole, err := h.am.GetWorkspaceRole(request.PathParameter("workspace"), request.PathParameter("workspacerole"))
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(workspaceRole)
+}
+
+func (h *iamHandler) CreateOrUpdateWorkspaceRole(request *restful.Request, response *restful.Response) {
+	var workspaceRole iamv1alpha2.WorkspaceRole
+	if err := request.ReadEntity(&workspaceRole); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	if name := request.PathParameter("workspacerole"); name != "" && workspaceRole.Name != name {
+		api.HandleBadRequest(response, request, fmt.Errorf("the name of the object (%s) does not match the name on the URL (%s)", workspaceRole.Name, name))
+		return
+	}
+
+	updated, err := h.am.CreateOrUpdateWorkspaceRole(request.PathParameter("workspace"), &workspaceRole)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(updated)
+}
+
+func (h *iamHandler) DeleteWorkspaceRole(request *restful.Request, response *restful.Response) {
+	if err := h.am.DeleteWorkspaceRole(request.PathParameter("workspace"), request.PathParameter("workspacerole")); err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+}
+
+func (h *iamHandler) RetrieveWorkspaceRolesOfMember(request *restful.Request, response *restful.Response) {
+	user, err := h.im.DescribeUser(request.PathParameter("workspacemember"))
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	workspaceRoles, err := h.am.GetWorkspaceRoleOfUser(user.Name, user.Spec.Groups, request.PathParameter("workspace"))
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	result := &api.ListResult{Items: make([]interface{}, 0), TotalItems: len(workspaceRoles)}
+	for _, workspaceRole := range workspaceRoles {
+		result.Items = append(result.Items, workspaceRole)
+	}
+	response.WriteEntity(result)
+}
+
+func (h *iamHandler) CreateWorkspaceMembers(request *restful.Request, response *restful.Response) {
+	workspace := request.PathParameter("workspace")
+
+	var members []Member
+	if err := request.ReadEntity(&members); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	for _, member := range members {
+		if err := h.ensureMemberExists(member.Username); err != nil {
+			api.HandleError(response, request, err)
+			return
+		}
+		if err := h.am.CreateOrUpdateWorkspaceRoleBinding(member.Username, workspace, member.RoleRef); err != nil {
+			api.HandleError(response, request, err)
+			return
+		}
+	}
+
+	response.WriteEntity(members)
+}
+
+func (h *iamHandler) RemoveWorkspaceMember(request *restful.Request, response *restful.Response) {
+	if err := h.am.RemoveUserFromWorkspace(request.PathParameter("workspacemember"), request.PathParameter("workspace")); err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+}
+
+func (h *iamHandler) ListRoles(request *restful.Request, response *restful.Response) {
+	queryParam, err := query.ParseQueryParameter(request)
+	if err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+	result, err := h.am.ListRoles(request.PathParameter("namespace"), queryParam)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(result)
+}
+
+func (h *iamHandler) DescribeNamespaceRole(request *restful.Request, response *restful.Response) {
+	role, err := h.am.GetNamespaceRole(request.PathParameter("namespace"), request.PathParameter("role"))
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(role)
+}
+
+func (h *iamHandler) CreateOrUpdateNamespaceRole(request *restful.Request, response *restful.Response) {
+	var role rbacv1.Role
+	if err := request.ReadEntity(&role); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	if name := request.PathParameter("role"); name != "" && role.Name != name {
+		api.HandleBadRequest(response, request, fmt.Errorf("the name of the object (%s) does not match the name on the URL (%s)", role.Name, name))
+		return
+	}
+
+	updated, err := h.am.CreateOrUpdateNamespaceRole(request.PathParameter("namespace"), &role)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(updated)
+}
+
+func (h *iamHandler) DeleteNamespaceRole(request *restful.Request, response *restful.Response) {
+	if err := h.am.DeleteNamespaceRole(request.PathParameter("namespace"), request.PathParameter("role")); err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+}
+
+func (h *iamHandler) RetrieveRolesOfNamespaceMember(request *restful.Request, response *restful.Response) {
+	user, err := h.im.DescribeUser(request.PathParameter("member"))
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	roles, err := h.am.GetNamespaceRoleOfUser(user.Name, user.Spec.Groups, request.PathParameter("namespace"))
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	result := &api.ListResult{Items: make([]interface{}, 0), TotalItems: len(roles)}
+	for _, role := range roles {
+		result.Items = append(result.Items, role)
+	}
+	response.WriteEntity(result)
+}
+
+func (h *iamHandler) CreateNamespaceMembers(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+
+	var members []Member
+	if err := request.ReadEntity(&members); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	for _, member := range members {
+		if err := h.ensureMemberExists(member.Username); err != nil {
+			api.HandleError(response, request, err)
+			return
+		}
+		if err := h.am.CreateOrUpdateRoleBinding(member.Username, namespace, member.RoleRef); err != nil {
+			api.HandleError(response, request, err)
+			return
+		}
+	}
+
+	response.WriteEntity(members)
+}
+
+func (h *iamHandler) RemoveNamespaceMember(request *restful.Request, response *restful.Response) {
+	if err := h.am.RemoveUserFromNamespace(request.PathParameter("member"), request.PathParameter("namespace")); err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+}
+
+// ensureMemberExists returns a bad request error if the user to be bound does not exist
+func (h *iamHandler) ensureMemberExists(username string) error {
+	if _, err := h.im.DescribeUser(username); err != nil {
+		if apierrors.IsNotFound(err) {
+			return apierrors.NewBadRequest(fmt.Sprintf("user %s not found", username))
+		}
+		return err
+	}
+	return nil
+}
+
+func (h *iamHandler) CreateSubjectAccessReview(request *restful.Request, response *restful.Response) {
+	var review SubjectAccessReview
+	if err := request.ReadEntity(&review); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	if review.Spec.User == "" {
+		api.HandleBadRequest(response, request, fmt.Errorf("user must not be empty"))
+		return
+	}
+
+	groups := review.Spec.Groups
+	// the groups of Horizon users are looked up if not given
+	if groups == nil {
+		u, err := h.im.DescribeUser(review.Spec.User)
+		if err != nil {
+			api.HandleError(response, request, err)
+			return
+		}
+		groups = append(append([]string{}, u.Spec.Groups...), user.AllAuthenticated)
+	}
+
+	review.Status = h.reviewAccess(&user.DefaultInfo{Name: review.Spec.User, Groups: groups}, review.Spec)
+	response.WriteHeaderAndEntity(http.StatusCreated, review)
+}
+
+func (h *iamHandler) CreateSelfSubjectAccessReview(request *restful.Request, response *restful.Response) {
+	var review SubjectAccessReview
+	if err := request.ReadEntity(&review); err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	operator, ok := k8srequest.UserFrom(request.Request.Context())
+	if !ok {
+		api.HandleForbidden(response, request, fmt.Errorf("no user found in the request"))
+		return
+	}
+
+	review.Spec.User = operator.GetName()
+	review.Spec.Groups = operator.GetGroups()
+	review.Status = h.reviewAccess(operator, review.Spec)
+	response.WriteHeaderAndEntity(http.StatusCreated, review)
+}
+
+func (h *iamHandler) reviewAccess(u user.Info, spec SubjectAccessReviewSpec) SubjectAccessReviewStatus {
+	attributes := &authorizer.AtrributesRecord{
+		User:            u,
+		Verb:            spec.Verb,
+		Cluster:         spec.Cluster,
+		Workspace:       spec.Workspace,
+		Namespace:       spec.Namespace,
+		DevOps:          spec.DevOps,
+		APIGroup:        spec.APIGroup,
+		APIVersion:      spec.APIVersion,
+		Resource:        spec.Resource,
+		Subresource:     spec.Subresource,
+		Name:            spec.Name,
+		Path:            spec.Path,
+		ResourceScope:   spec.ResourceScope,
+		ResourceRequest: spec.ResourceRequest,
+	}
+	if attributes.ResourceScope == "" {
+		attributes.ResourceScope = h.requestInfoResolver.ResolveResourceScope(request.RequestInfo{
+			RequestInfo: &k8srequest.RequestInfo{
+				APIGroup:  spec.APIGroup,
+				Resource:  spec.Resource,
+				Namespace: spec.Namespace,
+			},
+			Workspace: spec.Workspace,
+			DevOps:    spec.DevOps,
+			Cluster:   spec.Cluster,
+		})
+	}
+
+	decision, reason, err := h.authorizer.Authorize(attributes)
+	status := SubjectAccessReviewStatus{
+		Allowed: decision == authorizer.DecisionAllow,
+		Denied:  decision == authorizer.DecisionDeny,
+		Reason:  reason,
+	}
+	if err != nil {
+		status.EvaluationError = err.Error()
+	}
+	return status
 }
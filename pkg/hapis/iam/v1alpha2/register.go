@@ -8,9 +8,15 @@ import (
 	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
 	"github.com/sunweiwe/horizon/pkg/api"
 	"github.com/sunweiwe/horizon/pkg/apiserver/authorization/authorizer"
+	"github.com/sunweiwe/horizon/pkg/apiserver/query"
+	"github.com/sunweiwe/horizon/pkg/apiserver/request"
 	"github.com/sunweiwe/horizon/pkg/apiserver/runtime"
 	"github.com/sunweiwe/horizon/pkg/constants"
+	"github.com/sunweiwe/horizon/pkg/models/iam/am"
+	"github.com/sunweiwe/horizon/pkg/models/iam/group"
 	"github.com/sunweiwe/horizon/pkg/models/iam/im"
+	"github.com/sunweiwe/horizon/pkg/models/kubeconfig"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -20,16 +26,369 @@ const (
 
 var GroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1alpha2"}
 
-func AddToContainer(container *restful.Container, authorizer authorizer.Authorizer, im im.IdentityManagementInterface) error {
+func AddToContainer(container *restful.Container, authorizer authorizer.Authorizer, im im.IdentityManagementInterface, am am.AccessManagementInterface, group group.GroupOperator,
+	requestInfoResolver *request.RequestInfoFactory, kubeconfig kubeconfig.Interface) error {
 	service := runtime.NewWebService(GroupVersion)
-	handler := newHandler(im, authorizer)
+	handler := newHandler(im, am, group, kubeconfig, authorizer, requestInfoResolver)
 
 	// user
+	service.Route(service.POST("/users").
+		To(handler.CreateUser).
+		Doc("Create a user, the password in the spec is encrypted by the user controller.").
+		Reads(iamv1alpha2.User{}).
+		Returns(http.StatusCreated, api.StatusOK, iamv1alpha2.User{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.UserTag}))
+
 	service.Route(service.GET("/users").
 		To(handler.ListUsers).
 		Doc("List all users.").
 		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{iamv1alpha2.User{}}}).
 		Metadata(restfulspec.KeyOpenAPITags, []string{constants.UserTag}))
 
+	service.Route(service.GET("/users/{user}").
+		To(handler.DescribeUser).
+		Doc("Retrieve the specified user.").
+		Param(service.PathParameter("user", "username")).
+		Returns(http.StatusOK, api.StatusOK, iamv1alpha2.User{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.UserTag}))
+
+	service.Route(service.PUT("/users/{user}").
+		To(handler.UpdateUser).
+		Doc("Update the specified user, the password is left unchanged.").
+		Param(service.PathParameter("user", "username")).
+		Reads(iamv1alpha2.User{}).
+		Returns(http.StatusOK, api.StatusOK, iamv1alpha2.User{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.UserTag}))
+
+	service.Route(service.PATCH("/users/{user}").
+		To(handler.PatchUser).
+		Consumes(restful.MIME_JSON, runtime.MimeMergePatchJson).
+		Doc("Patch the specified user with JSON merge patch, the password can not be patched.").
+		Param(service.PathParameter("user", "username")).
+		Reads(iamv1alpha2.User{}).
+		Returns(http.StatusOK, api.StatusOK, iamv1alpha2.User{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.UserTag}))
+
+	service.Route(service.DELETE("/users/{user}").
+		To(handler.DeleteUser).
+		Doc("Delete the specified user.").
+		Param(service.PathParameter("user", "username")).
+		Returns(http.StatusOK, api.StatusOK, nil).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.UserTag}))
+
+	service.Route(service.PUT("/users/{user}/state").
+		To(handler.UpdateUserState).
+		Doc("Enable or disable the specified user, enabling a user also confirms the user pending confirmation.").
+		Param(service.PathParameter("user", "username")).
+		Reads(UserStateUpdate{}).
+		Returns(http.StatusOK, api.StatusOK, iamv1alpha2.User{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.UserTag}))
+
+	service.Route(service.PUT("/users/{user}/password").
+		To(handler.ModifyPassword).
+		Doc("Modify the password of the specified user, the current password is required unless it is reset by the operators granted to update users/password at the global scope.").
+		Param(service.PathParameter("user", "username")).
+		Reads(PasswordReset{}).
+		Returns(http.StatusOK, api.StatusOK, nil).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.UserTag}))
+
+	service.Route(service.GET("/users/{user}/kubeconfig").
+		To(handler.GetKubeConfig).
+		Doc("Retrieve the kubeconfig of the specified user, the requests are proxied and authorized by hz-apiserver.").
+		Param(service.PathParameter("user", "username")).
+		Returns(http.StatusOK, api.StatusOK, nil).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.UserTag}))
+
+	service.Route(service.GET("/users/{user}/loginrecords").
+		To(handler.ListUserLoginRecords).
+		Doc("List login records of the specified user.").
+		Param(service.PathParameter("user", "username")).
+		Param(service.QueryParameter(query.ParameterPage, "page").Required(false).DataFormat("page=%d").DefaultValue("page=1")).
+		Param(service.QueryParameter(query.ParameterLimit, "limit").Required(false)).
+		Param(service.QueryParameter(query.ParameterAscending, "sort parameters, e.g. ascending=false").Required(false).DefaultValue("ascending=false")).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{iamv1alpha2.LoginRecord{}}}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.UserTag}))
+
+	// group
+	service.Route(service.GET("/workspaces/{workspace}/groups").
+		To(handler.ListWorkspaceGroups).
+		Doc("List groups of the specified workspace, ?parent= lists the top level groups.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Param(service.QueryParameter(query.ParameterPage, "page").Required(false).DataFormat("page=%d").DefaultValue("page=1")).
+		Param(service.QueryParameter(query.ParameterLimit, "limit").Required(false)).
+		Param(service.QueryParameter(query.ParameterAscending, "sort parameters, e.g. ascending=false").Required(false).DefaultValue("ascending=false")).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{iamv1alpha2.Group{}}}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.GroupTag}))
+
+	service.Route(service.POST("/workspaces/{workspace}/groups").
+		To(handler.CreateGroup).
+		Doc("Create a group in the specified workspace, the parent group is specified with the label iam.horizon.io/group-parent.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Reads(iamv1alpha2.Group{}).
+		Returns(http.StatusCreated, api.StatusOK, iamv1alpha2.Group{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.GroupTag}))
+
+	service.Route(service.GET("/workspaces/{workspace}/groups/{group}").
+		To(handler.DescribeGroup).
+		Doc("Retrieve the specified group.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Param(service.PathParameter("group", "group name")).
+		Returns(http.StatusOK, api.StatusOK, iamv1alpha2.Group{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.GroupTag}))
+
+	service.Route(service.PUT("/workspaces/{workspace}/groups/{group}").
+		To(handler.UpdateGroup).
+		Doc("Update the specified group.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Param(service.PathParameter("group", "group name")).
+		Reads(iamv1alpha2.Group{}).
+		Returns(http.StatusOK, api.StatusOK, iamv1alpha2.Group{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.GroupTag}))
+
+	service.Route(service.DELETE("/workspaces/{workspace}/groups/{group}").
+		To(handler.DeleteGroup).
+		Doc("Delete the specified group along with its child groups and group bindings.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Param(service.PathParameter("group", "group name")).
+		Returns(http.StatusOK, api.StatusOK, nil).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.GroupTag}))
+
+	service.Route(service.GET("/workspaces/{workspace}/groups/{group}/members").
+		To(handler.ListGroupMembers).
+		Doc("List users bound to the specified group.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Param(service.PathParameter("group", "group name")).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{iamv1alpha2.User{}}}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.GroupTag}))
+
+	service.Route(service.GET("/workspaces/{workspace}/groupbindings").
+		To(handler.ListGroupBindings).
+		Doc("List group bindings of the specified workspace, ?group= and ?user= filter the bindings.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{iamv1alpha2.GroupBinding{}}}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.GroupTag}))
+
+	service.Route(service.POST("/workspaces/{workspace}/groupbindings").
+		To(handler.CreateGroupBinding).
+		Doc("Bind users to a group of the specified workspace.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Reads(iamv1alpha2.GroupBinding{}).
+		Returns(http.StatusCreated, api.StatusOK, iamv1alpha2.GroupBinding{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.GroupTag}))
+
+	service.Route(service.DELETE("/workspaces/{workspace}/groupbindings/{groupbinding}").
+		To(handler.DeleteGroupBinding).
+		Doc("Delete the specified group binding.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Param(service.PathParameter("groupbinding", "group binding name")).
+		Returns(http.StatusOK, api.StatusOK, nil).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.GroupTag}))
+
+	// global role
+	service.Route(service.GET("/globalroles").
+		To(handler.ListGlobalRoles).
+		Doc("List all global roles.").
+		Param(service.QueryParameter(query.ParameterPage, "page").Required(false).DataFormat("page=%d").DefaultValue("page=1")).
+		Param(service.QueryParameter(query.ParameterLimit, "limit").Required(false)).
+		Param(service.QueryParameter(query.ParameterAscending, "sort parameters, e.g. ascending=false").Required(false).DefaultValue("ascending=false")).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{iamv1alpha2.GlobalRole{}}}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.POST("/globalroles").
+		To(handler.CreateOrUpdateGlobalRole).
+		Doc("Create a global role, the existing global role with the same name is updated.").
+		Reads(iamv1alpha2.GlobalRole{}).
+		Returns(http.StatusOK, api.StatusOK, iamv1alpha2.GlobalRole{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.GET("/globalroles/{globalrole}").
+		To(handler.DescribeGlobalRole).
+		Doc("Retrieve the specified global role.").
+		Param(service.PathParameter("globalrole", "global role name")).
+		Returns(http.StatusOK, api.StatusOK, iamv1alpha2.GlobalRole{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.PUT("/globalroles/{globalrole}").
+		To(handler.CreateOrUpdateGlobalRole).
+		Doc("Update the specified global role.").
+		Param(service.PathParameter("globalrole", "global role name")).
+		Reads(iamv1alpha2.GlobalRole{}).
+		Returns(http.StatusOK, api.StatusOK, iamv1alpha2.GlobalRole{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.DELETE("/globalroles/{globalrole}").
+		To(handler.DeleteGlobalRole).
+		Doc("Delete the specified global role along with the global role bindings created for it.").
+		Param(service.PathParameter("globalrole", "global role name")).
+		Returns(http.StatusOK, api.StatusOK, nil).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.GET("/users/{user}/globalroles").
+		To(handler.RetrieveGlobalRoleOfUser).
+		Doc("Retrieve the global role bound to the specified user.").
+		Param(service.PathParameter("user", "username")).
+		Returns(http.StatusOK, api.StatusOK, iamv1alpha2.GlobalRole{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.PUT("/users/{user}/globalroles").
+		To(handler.UpdateGlobalRoleOfUser).
+		Doc("Bind the global role to the specified user, the global role bound before is unbound.").
+		Param(service.PathParameter("user", "username")).
+		Reads(Member{}).
+		Returns(http.StatusOK, api.StatusOK, Member{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	// workspace role
+	service.Route(service.GET("/workspaces/{workspace}/workspaceroles").
+		To(handler.ListWorkspaceRoles).
+		Doc("List roles of the specified workspace.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Param(service.QueryParameter(query.ParameterPage, "page").Required(false).DataFormat("page=%d").DefaultValue("page=1")).
+		Param(service.QueryParameter(query.ParameterLimit, "limit").Required(false)).
+		Param(service.QueryParameter(query.ParameterAscending, "sort parameters, e.g. ascending=false").Required(false).DefaultValue("ascending=false")).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{iamv1alpha2.WorkspaceRole{}}}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.POST("/workspaces/{workspace}/workspaceroles").
+		To(handler.CreateOrUpdateWorkspaceRole).
+		Doc("Create a role in the specified workspace, the existing role with the same name is updated.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Reads(iamv1alpha2.WorkspaceRole{}).
+		Returns(http.StatusOK, api.StatusOK, iamv1alpha2.WorkspaceRole{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.GET("/workspaces/{workspace}/workspaceroles/{workspacerole}").
+		To(handler.DescribeWorkspaceRole).
+		Doc("Retrieve the specified role of the workspace.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Param(service.PathParameter("workspacerole", "workspace role name")).
+		Returns(http.StatusOK, api.StatusOK, iamv1alpha2.WorkspaceRole{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.PUT("/workspaces/{workspace}/workspaceroles/{workspacerole}").
+		To(handler.CreateOrUpdateWorkspaceRole).
+		Doc("Update the specified role of the workspace.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Param(service.PathParameter("workspacerole", "workspace role name")).
+		Reads(iamv1alpha2.WorkspaceRole{}).
+		Returns(http.StatusOK, api.StatusOK, iamv1alpha2.WorkspaceRole{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.DELETE("/workspaces/{workspace}/workspaceroles/{workspacerole}").
+		To(handler.DeleteWorkspaceRole).
+		Doc("Delete the specified role of the workspace along with the workspace role bindings created for it.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Param(service.PathParameter("workspacerole", "workspace role name")).
+		Returns(http.StatusOK, api.StatusOK, nil).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.GET("/workspaces/{workspace}/workspacemembers/{workspacemember}/workspaceroles").
+		To(handler.RetrieveWorkspaceRolesOfMember).
+		Doc("Retrieve the roles of the workspace bound to the member and its groups.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Param(service.PathParameter("workspacemember", "username")).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{iamv1alpha2.WorkspaceRole{}}}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.POST("/workspaces/{workspace}/workspacemembers").
+		To(handler.CreateWorkspaceMembers).
+		Doc("Bind roles of the workspace to the users, the roles bound before are unbound.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Reads([]Member{}).
+		Returns(http.StatusOK, api.StatusOK, []Member{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.DELETE("/workspaces/{workspace}/workspacemembers/{workspacemember}").
+		To(handler.RemoveWorkspaceMember).
+		Doc("Remove the member from the workspace and the namespaces of the workspace.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Param(service.PathParameter("workspacemember", "username")).
+		Returns(http.StatusOK, api.StatusOK, nil).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	// namespace role
+	service.Route(service.GET("/namespaces/{namespace}/roles").
+		To(handler.ListRoles).
+		Doc("List roles of the specified namespace.").
+		Param(service.PathParameter("namespace", "namespace")).
+		Param(service.QueryParameter(query.ParameterPage, "page").Required(false).DataFormat("page=%d").DefaultValue("page=1")).
+		Param(service.QueryParameter(query.ParameterLimit, "limit").Required(false)).
+		Param(service.QueryParameter(query.ParameterAscending, "sort parameters, e.g. ascending=false").Required(false).DefaultValue("ascending=false")).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{rbacv1.Role{}}}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.POST("/namespaces/{namespace}/roles").
+		To(handler.CreateOrUpdateNamespaceRole).
+		Doc("Create a role in the specified namespace, the existing role with the same name is updated.").
+		Param(service.PathParameter("namespace", "namespace")).
+		Reads(rbacv1.Role{}).
+		Returns(http.StatusOK, api.StatusOK, rbacv1.Role{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.GET("/namespaces/{namespace}/roles/{role}").
+		To(handler.DescribeNamespaceRole).
+		Doc("Retrieve the specified role of the namespace.").
+		Param(service.PathParameter("namespace", "namespace")).
+		Param(service.PathParameter("role", "role name")).
+		Returns(http.StatusOK, api.StatusOK, rbacv1.Role{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.PUT("/namespaces/{namespace}/roles/{role}").
+		To(handler.CreateOrUpdateNamespaceRole).
+		Doc("Update the specified role of the namespace.").
+		Param(service.PathParameter("namespace", "namespace")).
+		Param(service.PathParameter("role", "role name")).
+		Reads(rbacv1.Role{}).
+		Returns(http.StatusOK, api.StatusOK, rbacv1.Role{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.DELETE("/namespaces/{namespace}/roles/{role}").
+		To(handler.DeleteNamespaceRole).
+		Doc("Delete the specified role of the namespace along with the role bindings created for it.").
+		Param(service.PathParameter("namespace", "namespace")).
+		Param(service.PathParameter("role", "role name")).
+		Returns(http.StatusOK, api.StatusOK, nil).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.GET("/namespaces/{namespace}/members/{member}/roles").
+		To(handler.RetrieveRolesOfNamespaceMember).
+		Doc("Retrieve the roles of the namespace bound to the member and its groups.").
+		Param(service.PathParameter("namespace", "namespace")).
+		Param(service.PathParameter("member", "username")).
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{rbacv1.Role{}}}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.POST("/namespaces/{namespace}/members").
+		To(handler.CreateNamespaceMembers).
+		Doc("Bind roles of the namespace to the users, the roles bound before are unbound.").
+		Param(service.PathParameter("namespace", "namespace")).
+		Reads([]Member{}).
+		Returns(http.StatusOK, api.StatusOK, []Member{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.DELETE("/namespaces/{namespace}/members/{member}").
+		To(handler.RemoveNamespaceMember).
+		Doc("Remove the member from the namespace.").
+		Param(service.PathParameter("namespace", "namespace")).
+		Param(service.PathParameter("member", "username")).
+		Returns(http.StatusOK, api.StatusOK, nil).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	// access review
+	service.Route(service.POST("/subjectaccessreviews").
+		To(handler.CreateSubjectAccessReview).
+		Doc("Review whether the user can perform the action, the groups of the user are looked up if not given.").
+		Reads(SubjectAccessReview{}).
+		Returns(http.StatusCreated, api.StatusOK, SubjectAccessReview{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	service.Route(service.POST("/selfsubjectaccessreviews").
+		To(handler.CreateSelfSubjectAccessReview).
+		Doc("Review whether the current user can perform the action.").
+		Reads(SubjectAccessReview{}).
+		Returns(http.StatusCreated, api.StatusOK, SubjectAccessReview{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AccessManagementTag}))
+
+	container.Add(service)
 	return nil
 }
@@ -0,0 +1,361 @@
+package oauth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/oauth"
+	"github.com/sunweiwe/horizon/pkg/models/auth"
+	"github.com/sunweiwe/horizon/pkg/models/iam/im"
+	"github.com/sunweiwe/horizon/pkg/utils/ip"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// oauthStateCookie binds the state of logging in with the identity provider to the user-agent,
+	// so that the callback of a login started by another user-agent is rejected
+	oauthStateCookie = "horizon_oauth_state"
+	oauthStateMaxAge = 10 * 60
+)
+
+type handler struct {
+	im                    im.IdentityManagementInterface
+	tokenOperator         auth.TokenManagementInterface
+	passwordAuthenticator auth.PasswordAuthenticator
+	oauthAuthenticator    auth.OAuthAuthenticator
+	loginRecorder         auth.LoginRecorder
+	options               *authentication.Options
+}
+
+func newHandler(im im.IdentityManagementInterface,
+	tokenOperator auth.TokenManagementInterface,
+	passwordAuthenticator auth.PasswordAuthenticator,
+	oauthAuthenticator auth.OAuthAuthenticator,
+	loginRecorder auth.LoginRecorder,
+	options *authentication.Options) *handler {
+	return &handler{
+		im:                    im,
+		tokenOperator:         tokenOperator,
+		passwordAuthenticator: passwordAuthenticator,
+		oauthAuthenticator:    oauthAuthenticator,
+		loginRecorder:         loginRecorder,
+		options:               options,
+	}
+}
+
+// authorize issues an authorization code to the authenticated user and redirects
+// the user-agent back to the client, for more details: https://tools.ietf.org/html/rfc6749#section-4.1
+func (h *handler) authorize(req *restful.Request, response *restful.Response) {
+	responseType := req.QueryParameter("response_type")
+	clientID := req.QueryParameter("client_id")
+	redirectURI := req.QueryParameter("redirect_uri")
+	state := req.QueryParameter("state")
+
+	client, err := h.options.OAuthOptions.OAuthClient(clientID)
+	if err != nil {
+		response.WriteHeaderAndEntity(http.StatusBadRequest, oauth.NewInvalidClient(err))
+		return
+	}
+
+	redirectURL, err := client.ResolveRedirectURL(redirectURI)
+	if err != nil {
+		response.WriteHeaderAndEntity(http.StatusBadRequest, oauth.NewInvalidRequest(err))
+		return
+	}
+
+	if responseType != oauth.ResponseTypeCode {
+		err := fmt.Errorf("response type %s is not supported", responseType)
+		response.WriteHeaderAndEntity(http.StatusBadRequest, oauth.NewError(oauth.UnsupportedResponseType, err))
+		return
+	}
+
+	authenticated, ok := request.UserFrom(req.Request.Context())
+	if !ok || authenticated.GetName() == user.Anonymous {
+		err := errors.New("the resource owner must be authenticated")
+		response.WriteHeaderAndEntity(http.StatusUnauthorized, oauth.NewError(oauth.AccessDenied, err))
+		return
+	}
+
+	code, err := h.tokenOperator.IssueAuthorizationCode(authenticated, client, redirectURL.String())
+	if err != nil {
+		klog.Error(err)
+		response.WriteHeaderAndEntity(http.StatusInternalServerError, oauth.NewServerError(err))
+		return
+	}
+
+	values := redirectURL.Query()
+	values.Set("code", code)
+	if state != "" {
+		values.Set("state", state)
+	}
+	redirectURL.RawQuery = values.Encode()
+
+	http.Redirect(response, req.Request, redirectURL.String(), http.StatusSeeOther)
+}
+
+// token handles the access token request, for more details: https://tools.ietf.org/html/rfc6749#section-3.2
+func (h *handler) token(req *restful.Request, response *restful.Response) {
+	clientID, _ := req.BodyParameter("client_id")
+	clientSecret, _ := req.BodyParameter("client_secret")
+
+	client, err := h.options.OAuthOptions.OAuthClient(clientID)
+	if err != nil || subtle.ConstantTimeCompare([]byte(client.Secret), []byte(clientSecret)) != 1 {
+		err := errors.New("invalid client credentials")
+		response.WriteHeaderAndEntity(http.StatusUnauthorized, oauth.NewInvalidClient(err))
+		return
+	}
+
+	grantType, _ := req.BodyParameter("grant_type")
+	switch grantType {
+	case oauth.GrantTypePassword:
+		username, _ := req.BodyParameter("username")
+		password, _ := req.BodyParameter("password")
+		h.passwordGrant(username, password, client, req, response)
+	case oauth.GrantTypeRefreshToken:
+		refreshToken, _ := req.BodyParameter("refresh_token")
+		h.refreshTokenGrant(refreshToken, client, req, response)
+	case oauth.GrantTypeAuthorizationCode:
+		code, _ := req.BodyParameter("code")
+		redirectURI, _ := req.BodyParameter("redirect_uri")
+		h.codeGrant(code, redirectURI, client, req, response)
+	default:
+		err := fmt.Errorf("grant type %s is not supported", grantType)
+		response.WriteHeaderAndEntity(http.StatusBadRequest, oauth.NewError(oauth.UnsupportedGrantType, err))
+	}
+}
+
+// passwordGrant handles the Resource Owner Password Credentials Grant,
+// for more details: https://tools.ietf.org/html/rfc6749#section-4.3
+func (h *handler) passwordGrant(username, password string, client oauth.Client, req *restful.Request, response *restful.Response) {
+	authenticated, provider, err := h.passwordAuthenticator.Authenticate(req.Request.Context(), username, password)
+	if err != nil {
+		if statusCode, ok := authenticationFailed(err); ok {
+			h.recordLogin(username, iamv1alpha2.Token, provider, req, err)
+			if err == auth.ErrIncorrectPassword {
+				err = errors.New("incorrect username or password")
+			}
+			response.WriteHeaderAndEntity(statusCode, oauth.NewInvalidGrant(err))
+			return
+		}
+		klog.Error(err)
+		response.WriteHeaderAndEntity(http.StatusInternalServerError, oauth.NewServerError(err))
+		return
+	}
+
+	h.recordLogin(authenticated.GetName(), iamv1alpha2.Token, provider, req, nil)
+	h.issueTokenTo(authenticated, client, response)
+}
+
+// login redirects the user-agent to the identity provider, the state passed to the identity provider is
+// recorded and bound to the user-agent by a cookie, both are checked by the callback.
+func (h *handler) login(req *restful.Request, response *restful.Response) {
+	provider := req.PathParameter("callback")
+	client, err := h.options.OAuthOptions.OAuthClient(req.QueryParameter("client_id"))
+	if err != nil {
+		response.WriteHeaderAndEntity(http.StatusBadRequest, oauth.NewInvalidClient(err))
+		return
+	}
+
+	state, err := h.tokenOperator.IssueOAuthState(provider, client)
+	if err != nil {
+		response.WriteHeaderAndEntity(http.StatusInternalServerError, oauth.NewServerError(err))
+		return
+	}
+
+	authCodeURL, err := h.oauthAuthenticator.AuthCodeURL(provider, state)
+	if err != nil {
+		if err == oauth.ErrorIdentityProviderNotFound {
+			response.WriteHeaderAndEntity(http.StatusNotFound, oauth.NewInvalidRequest(err))
+			return
+		}
+		response.WriteHeaderAndEntity(http.StatusInternalServerError, oauth.NewServerError(err))
+		return
+	}
+
+	http.SetCookie(response, &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    state,
+		Path:     "/oauth/callback/",
+		MaxAge:   oauthStateMaxAge,
+		HttpOnly: true,
+		Secure:   req.Request.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(response, req.Request, authCodeURL, http.StatusFound)
+}
+
+// oauthCallback handles the callback of external OAuth identity providers, the identity is mapped
+// to the user and the tokens are issued with the default lifetime. The state must be the one issued
+// to the user-agent by login, otherwise the user-agent could be logged in as another user.
+func (h *handler) oauthCallback(req *restful.Request, response *restful.Response) {
+	provider := req.PathParameter("callback")
+	client, err := h.options.OAuthOptions.OAuthClient(req.QueryParameter("client_id"))
+	if err != nil {
+		response.WriteHeaderAndEntity(http.StatusBadRequest, oauth.NewInvalidClient(err))
+		return
+	}
+
+	state := req.QueryParameter("state")
+	cookie, err := req.Request.Cookie(oauthStateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		response.WriteHeaderAndEntity(http.StatusBadRequest, oauth.NewInvalidRequest(errors.New("the state does not match the login of the user-agent")))
+		return
+	}
+	// the state can only be used once
+	http.SetCookie(response, &http.Cookie{Name: oauthStateCookie, Path: "/oauth/callback/", MaxAge: -1})
+
+	if err := h.tokenOperator.VerifyOAuthState(state, provider, client); err != nil {
+		klog.V(4).Info(err)
+		response.WriteHeaderAndEntity(http.StatusBadRequest, oauth.NewInvalidRequest(errors.New("invalid state")))
+		return
+	}
+
+	authenticated, err := h.oauthAuthenticator.Authenticate(req.Request.Context(), provider, req.Request)
+	if err != nil {
+		if err == oauth.ErrorIdentityProviderNotFound {
+			response.WriteHeaderAndEntity(http.StatusNotFound, oauth.NewInvalidRequest(err))
+			return
+		}
+		// the identity is mapped to a user which is not allowed to log in
+		if authenticated != nil {
+			h.recordLogin(authenticated.GetName(), iamv1alpha2.OAuth, provider, req, err)
+		}
+		if statusCode, ok := authenticationFailed(err); ok {
+			response.WriteHeaderAndEntity(statusCode, oauth.NewInvalidGrant(err))
+			return
+		}
+		klog.Error(err)
+		response.WriteHeaderAndEntity(http.StatusUnauthorized, oauth.NewError(oauth.AccessDenied, err))
+		return
+	}
+
+	h.recordLogin(authenticated.GetName(), iamv1alpha2.OAuth, provider, req, nil)
+	h.issueTokenTo(authenticated, client, response)
+}
+
+// authenticationFailed returns the status code if the error is caused by the credentials or the state of the user
+func authenticationFailed(err error) (int, bool) {
+	switch err {
+	case auth.ErrIncorrectPassword, auth.ErrUserDisabled, auth.ErrUserPendingConfirmation,
+		auth.ErrIdentityNotMapped, auth.ErrIdentityConflict:
+		return http.StatusBadRequest, true
+	case auth.ErrRateLimitExceeded:
+		return http.StatusTooManyRequests, true
+	}
+	return 0, false
+}
+
+// refreshTokenGrant handles refreshing an access token,
+// for more details: https://tools.ietf.org/html/rfc6749#section-6
+func (h *handler) refreshTokenGrant(refreshToken string, client oauth.Client, req *restful.Request, response *restful.Response) {
+	authenticated, err := h.tokenOperator.VerifyRefreshToken(refreshToken, client)
+	if err != nil {
+		klog.V(4).Info(err)
+		response.WriteHeaderAndEntity(http.StatusBadRequest, oauth.NewInvalidGrant(errors.New("invalid refresh token")))
+		return
+	}
+
+	// the user may have been removed or disabled since the refresh token was issued
+	u, err := h.im.DescribeUser(authenticated.GetName())
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			response.WriteHeaderAndEntity(http.StatusBadRequest, oauth.NewInvalidGrant(err))
+			return
+		}
+		response.WriteHeaderAndEntity(http.StatusInternalServerError, oauth.NewServerError(err))
+		return
+	}
+	if u.Status.State != iamv1alpha2.UserActive {
+		response.WriteHeaderAndEntity(http.StatusBadRequest, oauth.NewInvalidGrant(fmt.Errorf("user %s is not active", u.Name)))
+		return
+	}
+
+	h.issueTokenTo(&user.DefaultInfo{Name: authenticated.GetName()}, client, response)
+}
+
+// codeGrant exchanges the authorization code for tokens,
+// for more details: https://tools.ietf.org/html/rfc6749#section-4.1.3
+func (h *handler) codeGrant(code, redirectURI string, client oauth.Client, req *restful.Request, response *restful.Response) {
+	redirectURL, err := client.ResolveRedirectURL(redirectURI)
+	if err != nil {
+		response.WriteHeaderAndEntity(http.StatusBadRequest, oauth.NewInvalidGrant(err))
+		return
+	}
+
+	authenticated, err := h.tokenOperator.VerifyAuthorizationCode(code, client, redirectURL.String())
+	if err != nil {
+		klog.V(4).Info(err)
+		response.WriteHeaderAndEntity(http.StatusBadRequest, oauth.NewInvalidGrant(errors.New("invalid authorization code")))
+		return
+	}
+
+	h.issueTokenTo(authenticated, client, response)
+}
+
+func (h *handler) issueTokenTo(authenticated user.Info, client oauth.Client, response *restful.Response) {
+	result, err := h.tokenOperator.IssueTo(authenticated, client)
+	if err != nil {
+		response.WriteHeaderAndEntity(http.StatusInternalServerError, oauth.NewServerError(err))
+		return
+	}
+
+	response.Header().Set("Cache-Control", "no-store")
+	response.Header().Set("Pragma", "no-cache")
+	response.WriteEntity(result)
+}
+
+// recordLogin records the authentication attempt, failures of recording do not block the login
+func (h *handler) recordLogin(username string, loginType iamv1alpha2.LoginType, provider string, req *restful.Request, authErr error) {
+	if err := h.loginRecorder.RecordLogin(username, loginType, provider, ip.RemoteIp(req.Request), req.Request.UserAgent(), authErr); err != nil {
+		klog.Errorf("failed to record login of user %s: %v", username, err)
+	}
+}
+
+// logout revokes all the tokens issued to the current user and redirects the user-agent to post_logout_redirect_uri if provided,
+// the redirect uri must be registered by the client identified by client_id.
+func (h *handler) logout(req *restful.Request, response *restful.Response) {
+	authenticated, ok := request.UserFrom(req.Request.Context())
+	if ok && authenticated.GetName() != user.Anonymous {
+		if err := h.tokenOperator.RevokeAllUserTokens(authenticated.GetName()); err != nil {
+			response.WriteHeaderAndEntity(http.StatusInternalServerError, oauth.NewServerError(err))
+			return
+		}
+	}
+
+	postLogoutRedirectURI, _ := req.BodyParameter("post_logout_redirect_uri")
+	if postLogoutRedirectURI == "" {
+		response.WriteHeader(http.StatusOK)
+		return
+	}
+
+	clientID, _ := req.BodyParameter("client_id")
+	client, err := h.options.OAuthOptions.OAuthClient(clientID)
+	if err != nil {
+		response.WriteHeaderAndEntity(http.StatusBadRequest, oauth.NewInvalidClient(err))
+		return
+	}
+
+	redirectURL, err := client.ResolveRedirectURL(postLogoutRedirectURI)
+	if err != nil {
+		response.WriteHeaderAndEntity(http.StatusBadRequest, oauth.NewInvalidRequest(fmt.Errorf("invalid logout redirect URI: %s", err)))
+		return
+	}
+
+	state, _ := req.BodyParameter("state")
+	if state != "" {
+		values := redirectURL.Query()
+		values.Set("state", state)
+		redirectURL.RawQuery = values.Encode()
+	}
+
+	http.Redirect(response, req.Request, redirectURL.String(), http.StatusSeeOther)
+}
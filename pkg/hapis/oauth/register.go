@@ -0,0 +1,92 @@
+package oauth
+
+import (
+	"net/http"
+
+	restfulspec "github.com/emicklei/go-restful-openapi"
+	"github.com/emicklei/go-restful/v3"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/oauth"
+	"github.com/sunweiwe/horizon/pkg/constants"
+	"github.com/sunweiwe/horizon/pkg/models/auth"
+	"github.com/sunweiwe/horizon/pkg/models/iam/im"
+)
+
+const (
+	contentTypeFormData = "application/x-www-form-urlencoded"
+)
+
+// AddToContainer registers the built-in OAuth2 authorization server endpoints,
+// for more details: https://tools.ietf.org/html/rfc6749
+func AddToContainer(c *restful.Container, im im.IdentityManagementInterface,
+	tokenOperator auth.TokenManagementInterface,
+	passwordAuthenticator auth.PasswordAuthenticator,
+	oauthAuthenticator auth.OAuthAuthenticator,
+	loginRecorder auth.LoginRecorder,
+	options *authentication.Options) error {
+
+	ws := &restful.WebService{}
+	ws.Path("/oauth").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON)
+
+	handler := newHandler(im, tokenOperator, passwordAuthenticator, oauthAuthenticator, loginRecorder, options)
+
+	// Authorization Code Grant, the user must be authenticated before requesting an authorization code
+	ws.Route(ws.GET("/authorize").
+		Doc("The authorization endpoint is used to interact with the resource owner and obtain an authorization grant.").
+		Param(ws.QueryParameter("response_type", "The value MUST be 'code'.").Required(true)).
+		Param(ws.QueryParameter("client_id", "The client identifier issued to the client during the registration process.").Required(true)).
+		Param(ws.QueryParameter("redirect_uri", "After completing its interaction with the resource owner, the authorization server directs the resource owner's user-agent back to the client.").Required(false)).
+		Param(ws.QueryParameter("state", "An opaque value used by the client to maintain state between the request and callback.").Required(false)).
+		To(handler.authorize).
+		Returns(http.StatusFound, http.StatusText(http.StatusFound), "").
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AuthenticationTag}))
+
+	ws.Route(ws.POST("/token").
+		Consumes(contentTypeFormData).
+		Doc("The token endpoint is used by the client to obtain an access token by presenting its authorization grant or refresh token.").
+		Param(ws.FormParameter("grant_type", "Value MUST be one of 'password', 'refresh_token' and 'authorization_code'.").Required(true)).
+		Param(ws.FormParameter("client_id", "The client identifier issued to the client during the registration process.").Required(true)).
+		Param(ws.FormParameter("client_secret", "The client secret.").Required(true)).
+		Param(ws.FormParameter("username", "The resource owner username, required by password grant.").Required(false)).
+		Param(ws.FormParameter("password", "The resource owner password, required by password grant.").Required(false)).
+		Param(ws.FormParameter("refresh_token", "The refresh token issued to the client, required by refresh_token grant.").Required(false)).
+		Param(ws.FormParameter("code", "The authorization code received from the authorization server, required by authorization_code grant.").Required(false)).
+		Param(ws.FormParameter("redirect_uri", "Redirect uri used in the authorization request.").Required(false)).
+		To(handler.token).
+		Returns(http.StatusOK, http.StatusText(http.StatusOK), &oauth.Token{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AuthenticationTag}))
+
+	ws.Route(ws.GET("/login/{callback}").
+		Doc("Redirects the user-agent to the identity provider to log in, the callback is only accepted for the logins started here.").
+		Param(ws.PathParameter("callback", "The name of the identity provider.").Required(true)).
+		Param(ws.QueryParameter("client_id", "The client the tokens are issued to, it must match the client_id of the callback.").Required(true)).
+		To(handler.login).
+		Returns(http.StatusFound, http.StatusText(http.StatusFound), "").
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AuthenticationTag}))
+
+	ws.Route(ws.GET("/callback/{callback}").
+		Doc("OAuth callback API, the path param callback is config by identity provider.").
+		Param(ws.PathParameter("callback", "The name of the identity provider.").Required(true)).
+		Param(ws.QueryParameter("client_id", "The client the tokens are issued to, it should be included in the redirect URL registered with the identity provider.").Required(true)).
+		Param(ws.QueryParameter("code", "The authorization code returned from the identity provider.").Required(true)).
+		Param(ws.QueryParameter("state", "The state issued by the login endpoint, it must match the state bound to the user-agent.").Required(true)).
+		To(handler.oauthCallback).
+		Returns(http.StatusOK, http.StatusText(http.StatusOK), &oauth.Token{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AuthenticationTag}))
+
+	// logging out changes the state of the server, it is not exposed as GET so that it can not be triggered cross-site
+	ws.Route(ws.POST("/logout").
+		Consumes(contentTypeFormData).
+		Doc("Logout the current user, the tokens issued to the user are revoked.").
+		Param(ws.FormParameter("post_logout_redirect_uri", "URL to which the user agent is redirected after logout, it must be registered by the client.").Required(false)).
+		Param(ws.FormParameter("client_id", "The client identifier, required by post_logout_redirect_uri.").Required(false)).
+		Param(ws.FormParameter("state", "An opaque value passed back to the post_logout_redirect_uri.").Required(false)).
+		To(handler.logout).
+		Returns(http.StatusOK, http.StatusText(http.StatusOK), nil).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.AuthenticationTag}))
+
+	c.Add(ws)
+	return nil
+}
@@ -1,26 +1,33 @@
 package v1alpha2
 
 import (
+	"fmt"
+	"io"
+	"net/http"
+
 	"github.com/emicklei/go-restful/v3"
 	"github.com/sunweiwe/horizon/pkg/api"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authorization/authorizer"
 	"github.com/sunweiwe/horizon/pkg/apiserver/query"
 	"github.com/sunweiwe/horizon/pkg/client/clientset"
 	"github.com/sunweiwe/horizon/pkg/informers"
+	"github.com/sunweiwe/horizon/pkg/models/iam/am"
 	"github.com/sunweiwe/horizon/pkg/models/tenant"
-	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
+
+	tenantv1alpha1 "github.com/sunweiwe/api/tenant/v1alpha1"
 )
 
 type tenantHandler struct {
 	tenant tenant.Interface
 }
 
-func NewTenantHandler(factory informers.InformerFactory, client kubernetes.Interface, horizon clientset.Interface) *tenantHandler {
+func NewTenantHandler(factory informers.InformerFactory, client kubernetes.Interface, horizon clientset.Interface, am am.AccessManagementInterface, authorizer authorizer.Authorizer) *tenantHandler {
 
 	return &tenantHandler{
-		tenant: tenant.New(factory, client, horizon),
+		tenant: tenant.New(factory, client, horizon, am, authorizer),
 	}
 }
 
@@ -28,21 +35,119 @@ func (h *tenantHandler) ListClusters(r *restful.Request, response *restful.Respo
 	user, ok := request.UserFrom(r.Request.Context())
 
 	if !ok {
-		response.WriteEntity([]interface{}{})
+		response.WriteEntity(api.ListResult{Items: []interface{}{}})
 		return
 	}
 
-	queryParam := query.ParseQueryParameter(r)
-	data, err := h.tenant.ListClusters(user, queryParam)
+	queryParam, err := query.ParseQueryParameter(r)
+	if err != nil {
+		api.HandleBadRequest(response, r, err)
+		return
+	}
+	result, err := h.tenant.ListClusters(user, queryParam)
 	if err != nil {
 		klog.Error(err)
-		if errors.IsNotFound(err) {
-			api.HandleNotFound(response, r, err)
-			return
-		}
 		api.HandleInternalError(response, r, err)
 		return
 	}
 
-	response.WriteEntity(data)
+	response.WriteEntity(result)
+}
+
+func (h *tenantHandler) ListWorkspaces(r *restful.Request, response *restful.Response) {
+	user, ok := request.UserFrom(r.Request.Context())
+
+	if !ok {
+		response.WriteEntity(api.ListResult{Items: []interface{}{}})
+		return
+	}
+
+	queryParam, err := query.ParseQueryParameter(r)
+	if err != nil {
+		api.HandleBadRequest(response, r, err)
+		return
+	}
+	result, err := h.tenant.ListWorkspaces(user, queryParam)
+	if err != nil {
+		api.HandleInternalError(response, r, err)
+		return
+	}
+
+	response.WriteEntity(result)
+}
+
+func (h *tenantHandler) CreateWorkspace(r *restful.Request, response *restful.Response) {
+	var workspace tenantv1alpha1.Workspace
+	if err := r.ReadEntity(&workspace); err != nil {
+		api.HandleBadRequest(response, r, err)
+		return
+	}
+
+	created, err := h.tenant.CreateWorkspace(&workspace)
+	if err != nil {
+		api.HandleError(response, r, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusCreated, created)
+}
+
+func (h *tenantHandler) DescribeWorkspace(r *restful.Request, response *restful.Response) {
+	workspace, err := h.tenant.DescribeWorkspace(r.PathParameter("workspace"))
+	if err != nil {
+		api.HandleError(response, r, err)
+		return
+	}
+
+	response.WriteEntity(workspace)
+}
+
+func (h *tenantHandler) UpdateWorkspace(r *restful.Request, response *restful.Response) {
+	name := r.PathParameter("workspace")
+
+	var workspace tenantv1alpha1.Workspace
+	if err := r.ReadEntity(&workspace); err != nil {
+		api.HandleBadRequest(response, r, err)
+		return
+	}
+
+	if workspace.Name != name {
+		api.HandleBadRequest(response, r, fmt.Errorf("the name of the object (%s) does not match the name on the URL (%s)", workspace.Name, name))
+		return
+	}
+
+	updated, err := h.tenant.UpdateWorkspace(&workspace)
+	if err != nil {
+		api.HandleError(response, r, err)
+		return
+	}
+
+	response.WriteEntity(updated)
+}
+
+func (h *tenantHandler) PatchWorkspace(r *restful.Request, response *restful.Response) {
+	name := r.PathParameter("workspace")
+
+	patch, err := io.ReadAll(r.Request.Body)
+	if err != nil {
+		api.HandleBadRequest(response, r, err)
+		return
+	}
+
+	patched, err := h.tenant.PatchWorkspace(name, patch)
+	if err != nil {
+		api.HandleError(response, r, err)
+		return
+	}
+
+	response.WriteEntity(patched)
+}
+
+func (h *tenantHandler) DeleteWorkspace(r *restful.Request, response *restful.Response) {
+	if err := h.tenant.DeleteWorkspace(r.PathParameter("workspace")); err != nil {
+		api.HandleError(response, r, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
 }
@@ -6,12 +6,17 @@ import (
 	restfulspec "github.com/emicklei/go-restful-openapi"
 	"github.com/emicklei/go-restful/v3"
 	"github.com/sunweiwe/horizon/pkg/api"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authorization/authorizer"
 	"github.com/sunweiwe/horizon/pkg/apiserver/runtime"
 	"github.com/sunweiwe/horizon/pkg/client/clientset"
 	"github.com/sunweiwe/horizon/pkg/constants"
 	"github.com/sunweiwe/horizon/pkg/informers"
+	"github.com/sunweiwe/horizon/pkg/models/iam/am"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes"
+
+	clusterv1alpha1 "github.com/sunweiwe/api/cluster/v1alpha1"
+	tenantv1alpha1 "github.com/sunweiwe/api/tenant/v1alpha1"
 )
 
 const (
@@ -20,16 +25,60 @@ const (
 
 var GroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1alpha2"}
 
-func AddToContainer(c *restful.Container, factory informers.InformerFactory, client kubernetes.Interface, horizon clientset.Interface) error {
+func AddToContainer(c *restful.Container, factory informers.InformerFactory, client kubernetes.Interface, horizon clientset.Interface, am am.AccessManagementInterface, authorizer authorizer.Authorizer) error {
 	service := runtime.NewWebService(GroupVersion)
-	handler := NewTenantHandler(factory, client, horizon)
+	handler := NewTenantHandler(factory, client, horizon, am, authorizer)
 
 	service.Route(service.GET("/clusters").
 		To(handler.ListClusters).
-		Doc("List clusters available to users").
-		Returns(http.StatusOK, api.StatusOK, api.ListResult{}).
+		Doc("List the clusters available to the current user, the clusters the workspaces of the user are placed on unless the user can list all the clusters.").
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{clusterv1alpha1.Cluster{}}}).
 		Metadata(restfulspec.KeyOpenAPITags, []string{constants.UserResourceTag}))
 
+	service.Route(service.GET("/workspaces").
+		To(handler.ListWorkspaces).
+		Doc("List the workspaces available to the current user.").
+		Returns(http.StatusOK, api.StatusOK, api.ListResult{Items: []interface{}{tenantv1alpha1.Workspace{}}}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.WorkspaceTag}))
+
+	service.Route(service.POST("/workspaces").
+		To(handler.CreateWorkspace).
+		Doc("Create a workspace, the manager of the workspace is bound as the workspace admin.").
+		Reads(tenantv1alpha1.Workspace{}).
+		Returns(http.StatusCreated, api.StatusOK, tenantv1alpha1.Workspace{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.WorkspaceTag}))
+
+	service.Route(service.GET("/workspaces/{workspace}").
+		To(handler.DescribeWorkspace).
+		Doc("Retrieve the specified workspace.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Returns(http.StatusOK, api.StatusOK, tenantv1alpha1.Workspace{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.WorkspaceTag}))
+
+	service.Route(service.PUT("/workspaces/{workspace}").
+		To(handler.UpdateWorkspace).
+		Doc("Update the specified workspace.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Reads(tenantv1alpha1.Workspace{}).
+		Returns(http.StatusOK, api.StatusOK, tenantv1alpha1.Workspace{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.WorkspaceTag}))
+
+	service.Route(service.PATCH("/workspaces/{workspace}").
+		To(handler.PatchWorkspace).
+		Consumes(restful.MIME_JSON, runtime.MimeMergePatchJson).
+		Doc("Patch the specified workspace with JSON merge patch.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Reads(tenantv1alpha1.Workspace{}).
+		Returns(http.StatusOK, api.StatusOK, tenantv1alpha1.Workspace{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.WorkspaceTag}))
+
+	service.Route(service.DELETE("/workspaces/{workspace}").
+		To(handler.DeleteWorkspace).
+		Doc("Delete the specified workspace.").
+		Param(service.PathParameter("workspace", "workspace name")).
+		Returns(http.StatusOK, api.StatusOK, nil).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.WorkspaceTag}))
+
 	c.Add(service)
 	return nil
 }
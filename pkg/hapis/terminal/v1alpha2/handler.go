@@ -0,0 +1,192 @@
+package v1alpha2
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/gorilla/websocket"
+	"github.com/sunweiwe/horizon/pkg/api"
+	"github.com/sunweiwe/horizon/pkg/models/kubectl"
+	"github.com/sunweiwe/horizon/pkg/models/terminal"
+	"k8s.io/klog/v2"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8srequest "k8s.io/apiserver/pkg/endpoints/request"
+)
+
+type terminalHandler struct {
+	kubectl    kubectl.Interface
+	terminaler terminal.Interface
+	upgrader   websocket.Upgrader
+}
+
+func newTerminalHandler(kubectl kubectl.Interface, terminaler terminal.Interface, consoleOrigins []string) *terminalHandler {
+	return &terminalHandler{
+		kubectl:    kubectl,
+		terminaler: terminaler,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin:     checkOrigin(consoleOrigins),
+		},
+	}
+}
+
+// checkOrigin returns the origin check of the websocket handshakes, the websockets are not protected by
+// the same-origin policy of the browsers, so that the pages of other origins could open the terminals with
+// the credentials of the users. The handshakes without origin are not sent by browsers and are allowed.
+func checkOrigin(consoleOrigins []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+
+		if len(consoleOrigins) == 0 {
+			return strings.EqualFold(u.Host, r.Host)
+		}
+		for _, consoleOrigin := range consoleOrigins {
+			if strings.EqualFold(strings.TrimSuffix(consoleOrigin, "/"), u.Scheme+"://"+u.Host) {
+				return true
+			}
+		}
+		klog.V(4).Infof("websocket origin %q is not allowed", origin)
+		return false
+	}
+}
+
+func (h *terminalHandler) GetKubectlPod(request *restful.Request, response *restful.Response) {
+	username := request.PathParameter("user")
+	if err := checkOperator(request, username); err != nil {
+		api.HandleForbidden(response, request, err)
+		return
+	}
+
+	podInfo, err := h.kubectl.GetKubectlPod(username)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	response.WriteEntity(podInfo)
+}
+
+func (h *terminalHandler) HandleKubectlTerminal(request *restful.Request, response *restful.Response) {
+	username := request.PathParameter("user")
+	shell := request.QueryParameter("shell")
+	if err := checkOperator(request, username); err != nil {
+		api.HandleForbidden(response, request, err)
+		return
+	}
+
+	podInfo, err := h.kubectl.GetKubectlPod(username)
+	if err != nil {
+		api.HandleError(response, request, err)
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(response.ResponseWriter, request.Request, nil)
+	if err != nil {
+		klog.Warning(err)
+		return
+	}
+
+	h.terminaler.HandleSession(shell, podInfo.Namespace, podInfo.Pod, podInfo.Container, conn)
+}
+
+func (h *terminalHandler) HandleTerminalSession(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	podName := request.PathParameter("pod")
+	containerName := request.QueryParameter("container")
+	shell := request.QueryParameter("shell")
+
+	conn, err := h.upgrader.Upgrade(response.ResponseWriter, request.Request, nil)
+	if err != nil {
+		klog.Warning(err)
+		return
+	}
+
+	h.terminaler.HandleSession(shell, namespace, podName, containerName, conn)
+}
+
+func (h *terminalHandler) HandleLogSession(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	podName := request.PathParameter("pod")
+
+	options, err := parseLogOptions(request)
+	if err != nil {
+		api.HandleBadRequest(response, request, err)
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(response.ResponseWriter, request.Request, nil)
+	if err != nil {
+		klog.Warning(err)
+		return
+	}
+
+	h.terminaler.HandleLogSession(namespace, podName, options, conn)
+}
+
+// parseLogOptions returns the options of the log stream, the logs are followed unless follow=false is given.
+func parseLogOptions(request *restful.Request) (*corev1.PodLogOptions, error) {
+	options := &corev1.PodLogOptions{
+		Container: request.QueryParameter("container"),
+		Follow:    request.QueryParameter("follow") != "false",
+	}
+
+	var err error
+	if options.Timestamps, err = parseBool(request.QueryParameter("timestamps")); err != nil {
+		return nil, err
+	}
+	if options.Previous, err = parseBool(request.QueryParameter("previous")); err != nil {
+		return nil, err
+	}
+	if options.TailLines, err = parseInt64(request.QueryParameter("tailLines")); err != nil {
+		return nil, err
+	}
+	if options.SinceSeconds, err = parseInt64(request.QueryParameter("sinceSeconds")); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+func parseBool(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+func parseInt64(value string) (*int64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// checkOperator makes sure the kubectl pod is only used by its owner, the shell runs with the permissions
+// of the owner rather than the user who opens it.
+func checkOperator(request *restful.Request, username string) error {
+	operator, ok := k8srequest.UserFrom(request.Request.Context())
+	if !ok || operator.GetName() != username {
+		return apierrors.NewForbidden(iamv1alpha2.Resource(iamv1alpha2.ResourcePluralUser), username,
+			fmt.Errorf("kubectl can only be used by the user"))
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package v1alpha2
+
+import (
+	"net/http"
+
+	restfulspec "github.com/emicklei/go-restful-openapi"
+	"github.com/emicklei/go-restful/v3"
+	"github.com/sunweiwe/horizon/pkg/api"
+	"github.com/sunweiwe/horizon/pkg/apiserver/runtime"
+	"github.com/sunweiwe/horizon/pkg/constants"
+	"github.com/sunweiwe/horizon/pkg/models/kubectl"
+	"github.com/sunweiwe/horizon/pkg/models/terminal"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	GroupName = "terminal.horizon.io"
+)
+
+var GroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1alpha2"}
+
+func AddToContainer(c *restful.Container, kubectlOperator kubectl.Interface, terminaler terminal.Interface, consoleOrigins []string) error {
+	service := runtime.NewWebService(GroupVersion)
+	handler := newTerminalHandler(kubectlOperator, terminaler, consoleOrigins)
+
+	service.Route(service.GET("/users/{user}/kubectl").
+		To(handler.GetKubectlPod).
+		Doc("Locate the kubectl pod of the current user, the pod is created if it does not exist yet.").
+		Param(service.PathParameter("user", "username")).
+		Returns(http.StatusOK, api.StatusOK, kubectl.PodInfo{}).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.TerminalTag}))
+
+	service.Route(service.GET("/users/{user}/kubectl/terminal").
+		To(handler.HandleKubectlTerminal).
+		Doc("Open a websocket terminal attached to the kubectl pod of the current user.").
+		Param(service.PathParameter("user", "username")).
+		Param(service.QueryParameter("shell", "shell to start, one of bash and sh").Required(false)).
+		Returns(http.StatusSwitchingProtocols, api.StatusOK, nil).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.TerminalTag}))
+
+	service.Route(service.GET("/namespaces/{namespace}/pods/{pod}/exec").
+		To(handler.HandleTerminalSession).
+		Doc("Open a websocket terminal attached to a shell executed in the container of the pod.").
+		Param(service.PathParameter("namespace", "namespace of the pod")).
+		Param(service.PathParameter("pod", "name of the pod")).
+		Param(service.QueryParameter("container", "name of the container, defaults to the only container of the pod").Required(false)).
+		Param(service.QueryParameter("shell", "shell to start, one of bash and sh").Required(false)).
+		Returns(http.StatusSwitchingProtocols, api.StatusOK, nil).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.TerminalTag}))
+
+	service.Route(service.GET("/namespaces/{namespace}/pods/{pod}/log").
+		To(handler.HandleLogSession).
+		Doc("Stream the logs of the container of the pod over websocket.").
+		Param(service.PathParameter("namespace", "namespace of the pod")).
+		Param(service.PathParameter("pod", "name of the pod")).
+		Param(service.QueryParameter("container", "name of the container, defaults to the only container of the pod").Required(false)).
+		Param(service.QueryParameter("follow", "follow the log stream, defaults to true").Required(false)).
+		Param(service.QueryParameter("tailLines", "number of lines from the end of the logs to show").Required(false)).
+		Param(service.QueryParameter("sinceSeconds", "show the logs newer than the relative time in seconds").Required(false)).
+		Param(service.QueryParameter("timestamps", "prefix the lines with timestamps").Required(false)).
+		Param(service.QueryParameter("previous", "show the logs of the previous terminated container").Required(false)).
+		Returns(http.StatusSwitchingProtocols, api.StatusOK, nil).
+		Metadata(restfulspec.KeyOpenAPITags, []string{constants.TerminalTag}))
+
+	c.Add(service)
+	return nil
+}
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/identityprovider"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/oauth"
+	"github.com/sunweiwe/horizon/pkg/client/clientset"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	iamv1alpha2listers "github.com/sunweiwe/horizon/pkg/client/listers/iam/v1alpha2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	ErrIdentityNotMapped = errors.New("no user is linked to the identity")
+	ErrIdentityConflict  = errors.New("user already exists and is not linked to the identity")
+)
+
+// identityMapper links the identities from identity providers to users
+type identityMapper struct {
+	horizonClient clientset.Interface
+	userLister    iamv1alpha2listers.UserLister
+}
+
+// mapIdentity returns the user linked to the identity, the user will be created on first login
+// unless the mapping method is lookup.
+func (m *identityMapper) mapIdentity(ctx context.Context, options *oauth.IdentityProviderOptions, identity identityprovider.Identity) (*iamv1alpha2.User, error) {
+	linked, err := m.findLinkedUser(options.Name, identity.GetUserID())
+	if err != nil {
+		return nil, err
+	}
+
+	if linked != nil {
+		return linked, nil
+	}
+
+	switch options.MappingMethod {
+	case oauth.MappingMethodLookup:
+		return nil, ErrIdentityNotMapped
+	case oauth.MappingMethodAuto, oauth.MappingMethodManual, "":
+		return m.createLinkedUser(ctx, options, identity)
+	default:
+		return nil, fmt.Errorf("unsupported mapping method %s of identity provider %s", options.MappingMethod, options.Name)
+	}
+}
+
+func (m *identityMapper) findLinkedUser(idp string, uid string) (*iamv1alpha2.User, error) {
+	selector, err := labels.ValidatedSelectorFromSet(labels.Set{
+		iamv1alpha2.IdentityProviderLabel: idp,
+		iamv1alpha2.OriginUIDLabel:        uid,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := m.userLister.List(selector)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	if len(users) == 0 {
+		return nil, nil
+	}
+
+	return users[0], nil
+}
+
+// createLinkedUser creates the user linked to the identity, existing users are never linked automatically
+func (m *identityMapper) createLinkedUser(ctx context.Context, options *oauth.IdentityProviderOptions, identity identityprovider.Identity) (*iamv1alpha2.User, error) {
+	user := &iamv1alpha2.User{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: identity.GetUsername(),
+			Labels: map[string]string{
+				iamv1alpha2.IdentityProviderLabel: options.Name,
+				iamv1alpha2.OriginUIDLabel:        identity.GetUserID(),
+			},
+		},
+		Spec: iamv1alpha2.UserSpec{
+			Email: identity.GetEmail(),
+		},
+	}
+
+	// the user will be disabled by the user controller until an administrator confirms it
+	if options.MappingMethod == oauth.MappingMethodManual {
+		user.Annotations = map[string]string{
+			iamv1alpha2.PendingConfirmationAnnotation: "true",
+		}
+	}
+
+	created, err := m.horizonClient.IamV1alpha2().Users().Create(ctx, user, metav1.CreateOptions{})
+	if err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			klog.Warningf("user %s already exists and is not linked to identity provider %s", user.Name, options.Name)
+			return nil, ErrIdentityConflict
+		}
+		klog.Error(err)
+		return nil, err
+	}
+
+	return created, nil
+}
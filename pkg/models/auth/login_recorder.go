@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sunweiwe/horizon/pkg/client/clientset"
+	"k8s.io/klog/v2"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	iamv1alpha2listers "github.com/sunweiwe/horizon/pkg/client/listers/iam/v1alpha2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LoginRecorder records the authentication attempts of users
+type LoginRecorder interface {
+	RecordLogin(username string, loginType iamv1alpha2.LoginType, provider string, sourceIP string, userAgent string, authErr error) error
+}
+
+type loginRecorder struct {
+	horizonClient clientset.Interface
+	userLister    iamv1alpha2listers.UserLister
+}
+
+func NewLoginRecorder(horizonClient clientset.Interface, userLister iamv1alpha2listers.UserLister) LoginRecorder {
+	return &loginRecorder{
+		horizonClient: horizonClient,
+		userLister:    userLister,
+	}
+}
+
+// RecordLogin creates a LoginRecord for the attempt, attempts of nonexistent users are ignored
+func (l *loginRecorder) RecordLogin(username string, loginType iamv1alpha2.LoginType, provider string, sourceIP string, userAgent string, authErr error) error {
+	if _, err := l.userLister.Get(username); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Error(err)
+		return err
+	}
+
+	record := &iamv1alpha2.LoginRecord{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-", username),
+			Labels: map[string]string{
+				iamv1alpha2.UserReferenceLabel: username,
+			},
+		},
+		Spec: iamv1alpha2.LoginRecordSpec{
+			Type:      loginType,
+			Provider:  provider,
+			SourceIP:  sourceIP,
+			UserAgent: userAgent,
+			Success:   true,
+			Reason:    iamv1alpha2.AuthenticatedSuccessfully,
+		},
+	}
+
+	if authErr != nil {
+		record.Spec.Success = false
+		record.Spec.Reason = authErr.Error()
+	}
+
+	_, err := l.horizonClient.IamV1alpha2().LoginRecords().Create(context.Background(), record, metav1.CreateOptions{})
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	return nil
+}
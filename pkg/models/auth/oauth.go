@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/identityprovider"
+	"github.com/sunweiwe/horizon/pkg/client/clientset"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/klog/v2"
+
+	iamv1alpha2listers "github.com/sunweiwe/horizon/pkg/client/listers/iam/v1alpha2"
+)
+
+// OAuthAuthenticator authenticates the user with the callback of external OAuth identity providers.
+// The mapped user is returned along with the error if the state of the user does not allow logging in,
+// so that the failed attempt can be recorded.
+type OAuthAuthenticator interface {
+	// AuthCodeURL returns the URL the user-agent is redirected to for logging in with the identity provider
+	AuthCodeURL(provider string, state string) (string, error)
+	Authenticate(ctx context.Context, provider string, req *http.Request) (user.Info, error)
+}
+
+type oauthAuthenticator struct {
+	identityMapper *identityMapper
+	options        *authentication.Options
+}
+
+func NewOAuthAuthenticator(horizonClient clientset.Interface, userLister iamv1alpha2listers.UserLister,
+	options *authentication.Options) OAuthAuthenticator {
+	return &oauthAuthenticator{
+		identityMapper: &identityMapper{horizonClient: horizonClient, userLister: userLister},
+		options:        options,
+	}
+}
+
+func (o *oauthAuthenticator) AuthCodeURL(provider string, state string) (string, error) {
+	providerOptions, err := o.options.OAuthOptions.IdentityProviderOptions(provider)
+	if err != nil {
+		klog.Error(err)
+		return "", err
+	}
+
+	oauthProvider, err := identityprovider.GetOAuthProvider(providerOptions.Name)
+	if err != nil {
+		klog.Error(err)
+		return "", err
+	}
+
+	return oauthProvider.AuthCodeURL(state), nil
+}
+
+func (o *oauthAuthenticator) Authenticate(ctx context.Context, provider string, req *http.Request) (user.Info, error) {
+	providerOptions, err := o.options.OAuthOptions.IdentityProviderOptions(provider)
+	// identity provider not registered
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	oauthProvider, err := identityprovider.GetOAuthProvider(providerOptions.Name)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	identity, err := oauthProvider.IdentityExchangeCallback(req)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	mapped, err := o.identityMapper.mapIdentity(ctx, providerOptions, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	authenticated := &user.DefaultInfo{Name: mapped.Name}
+	if err := checkUser(mapped); err != nil {
+		return authenticated, err
+	}
+
+	return authenticated, nil
+}
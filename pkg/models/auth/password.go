@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/identityprovider"
+	"github.com/sunweiwe/horizon/pkg/client/clientset"
+	"github.com/sunweiwe/horizon/pkg/models/iam/im"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/klog/v2"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	iamv1alpha2listers "github.com/sunweiwe/horizon/pkg/client/listers/iam/v1alpha2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+var (
+	ErrIncorrectPassword       = errors.New("incorrect password")
+	ErrRateLimitExceeded       = errors.New("auth rate limit exceeded")
+	ErrUserDisabled            = errors.New("user is disabled")
+	ErrUserPendingConfirmation = errors.New("user is pending confirmation")
+)
+
+// PasswordAuthenticator is an interface implemented by authenticator which authenticates the user with username and password.
+type PasswordAuthenticator interface {
+	// Authenticate returns the authenticated user and the name of the identity provider, which is empty for local users.
+	Authenticate(ctx context.Context, username, password string) (user.Info, string, error)
+}
+
+type passwordAuthenticator struct {
+	im             im.IdentityManagementInterface
+	identityMapper *identityMapper
+	options        *authentication.Options
+}
+
+func NewPasswordAuthenticator(im im.IdentityManagementInterface, horizonClient clientset.Interface,
+	userLister iamv1alpha2listers.UserLister, options *authentication.Options) PasswordAuthenticator {
+	return &passwordAuthenticator{
+		im:             im,
+		identityMapper: &identityMapper{horizonClient: horizonClient, userLister: userLister},
+		options:        options,
+	}
+}
+
+func (p *passwordAuthenticator) Authenticate(ctx context.Context, username, password string) (user.Info, string, error) {
+	// empty username or password are not allowed
+	if username == "" || password == "" {
+		return nil, "", ErrIncorrectPassword
+	}
+
+	u, err := p.im.DescribeUser(username)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, "", err
+	}
+
+	if u != nil {
+		if err := checkUser(u); err != nil {
+			return nil, "", err
+		}
+
+		err := p.im.PasswordVerify(username, password)
+		if err == nil {
+			return &user.DefaultInfo{Name: username}, "", nil
+		}
+		if err != im.ErrIncorrectPassword {
+			klog.Error(err)
+			return nil, "", err
+		}
+	}
+
+	// the user might be managed by generic identity providers, e.g. LDAP
+	authenticated, provider, err := p.authenticateWithProviders(ctx, username, password)
+	if err != nil {
+		return nil, "", err
+	}
+	if authenticated != nil {
+		return authenticated, provider, nil
+	}
+
+	// the failed attempt is recorded as a login record by the caller, the user controller moves the user
+	// into AuthLimitExceeded state when the failed attempts reach AuthenticateRateLimiterMaxTries
+	return nil, "", ErrIncorrectPassword
+}
+
+// authenticateWithProviders tries the generic identity providers in the configured order,
+// returns nil if none of the providers accepts the credentials
+func (p *passwordAuthenticator) authenticateWithProviders(ctx context.Context, username, password string) (user.Info, string, error) {
+	for i := range p.options.OAuthOptions.IdentityProviders {
+		providerOptions := &p.options.OAuthOptions.IdentityProviders[i]
+		provider, err := identityprovider.GetGenericProvider(providerOptions.Name)
+		// not a generic identity provider
+		if err != nil {
+			continue
+		}
+
+		identity, err := provider.Authenticate(username, password)
+		if err != nil {
+			klog.V(4).Infof("failed to authenticate user %s with identity provider %s: %v", username, providerOptions.Name, err)
+			continue
+		}
+
+		mapped, err := p.identityMapper.mapIdentity(ctx, providerOptions, identity)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if err := checkUser(mapped); err != nil {
+			return nil, "", err
+		}
+
+		return &user.DefaultInfo{Name: mapped.Name}, providerOptions.Name, nil
+	}
+	return nil, "", nil
+}
+
+// checkUser returns error if the user is not allowed to login
+func checkUser(u *iamv1alpha2.User) error {
+	switch u.Status.State {
+	case iamv1alpha2.UserAuthLimitExceeded:
+		return ErrRateLimitExceeded
+	case iamv1alpha2.UserDisabled:
+		return ErrUserDisabled
+	case iamv1alpha2.UserActive:
+		return nil
+	}
+
+	// the user created with manual mapping method is disabled until an administrator confirms it
+	if u.Annotations[iamv1alpha2.PendingConfirmationAnnotation] == "true" {
+		return ErrUserPendingConfirmation
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/oauth"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/token"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// authorization code is exchanged for tokens right after the redirection
+	authorizationCodeMaxAge = 10 * time.Minute
+	// the user is expected to log in with the identity provider in a few minutes
+	oauthStateMaxAge = 10 * time.Minute
+	// oauthStateOwner is the name the states of the logins with the identity providers are recorded with,
+	// they are not issued to any user until the callback
+	oauthStateOwner = "system.oauth-states"
+
+	extraClientID    = "client_id"
+	extraRedirectURI = "redirect_uri"
+)
+
+// TokenManagementInterface is used to issue tokens to users and verify the tokens issued.
+type TokenManagementInterface interface {
+	// Verify the given token and returns user.Info
+	Verify(tokenStr string) (user.Info, token.TokenType, error)
+	// IssueTo issue a token pair for the specified user, the lifetime of the tokens depends on the client
+	IssueTo(user user.Info, client oauth.Client) (*oauth.Token, error)
+	// IssueAuthorizationCode issue a short-lived authorization code bound to the client and redirect uri
+	IssueAuthorizationCode(user user.Info, client oauth.Client, redirectURI string) (string, error)
+	// VerifyAuthorizationCode verifies the authorization code was issued to the client and redirect uri,
+	// the code is consumed so that it can not be exchanged again
+	VerifyAuthorizationCode(code string, client oauth.Client, redirectURI string) (user.Info, error)
+	// VerifyRefreshToken verifies the refresh token was issued to the client and has not been revoked
+	VerifyRefreshToken(refreshToken string, client oauth.Client) (user.Info, error)
+	// IssueOAuthState issues the state of logging in with the identity provider for the client, it is
+	// recorded so that the callback is only accepted once for the logins started by horizon
+	IssueOAuthState(provider string, client oauth.Client) (string, error)
+	// VerifyOAuthState verifies the state was issued for the identity provider and the client, the state
+	// is consumed so that it can not be used again
+	VerifyOAuthState(state string, provider string, client oauth.Client) error
+	// RevokeAllUserTokens revoke all user tokens
+	RevokeAllUserTokens(username string) error
+}
+
+type tokenOperator struct {
+	issuer  token.Issuer
+	store   token.Store
+	options *authentication.Options
+}
+
+func NewTokenOperator(store token.Store, options *authentication.Options) TokenManagementInterface {
+	return &tokenOperator{
+		issuer:  token.NewTokenIssuer(options.JwtSecret, options.MaximumClockSkew),
+		store:   store,
+		options: options,
+	}
+}
+
+// Verify verifies the signature of the token, the access tokens and refresh tokens must also be
+// recorded in the store, otherwise they have been revoked.
+func (t *tokenOperator) Verify(tokenStr string) (user.Info, token.TokenType, error) {
+	authenticated, tokenType, err := t.issuer.Verify(tokenStr)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if tokenType == token.AccessToken || tokenType == token.RefreshToken {
+		exists, err := t.store.Exists(authenticated.GetName(), tokenStr)
+		if err != nil {
+			klog.Error(err)
+			return nil, "", err
+		}
+		if !exists {
+			return nil, "", fmt.Errorf("token not found in store")
+		}
+	}
+
+	return authenticated, tokenType, nil
+}
+
+func (t *tokenOperator) IssueTo(u user.Info, client oauth.Client) (*oauth.Token, error) {
+	accessTokenMaxAge, accessTokenInactivityTimeout := t.options.OAuthOptions.AccessTokenLifetime(client)
+
+	refreshTokenMaxAge := time.Duration(0)
+	if accessTokenMaxAge > 0 && accessTokenInactivityTimeout > 0 {
+		refreshTokenMaxAge = accessTokenMaxAge + accessTokenInactivityTimeout
+	}
+
+	accessToken, err := t.issuer.IssueTo(u, token.AccessToken, accessTokenMaxAge)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	// the refresh token can only be used by the client it is issued to
+	refreshToken, err := t.issuer.IssueTo(&user.DefaultInfo{
+		Name:  u.GetName(),
+		Extra: map[string][]string{extraClientID: {client.Name}},
+	}, token.RefreshToken, refreshTokenMaxAge)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	// the tokens issued before are invalidated if multiple login is not allowed
+	if !t.options.MultipleLogin {
+		if err = t.store.RevokeAll(u.GetName()); err != nil {
+			klog.Error(err)
+			return nil, err
+		}
+	}
+
+	if err = t.store.Add(u.GetName(), accessToken, accessTokenMaxAge); err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	if err = t.store.Add(u.GetName(), refreshToken, refreshTokenMaxAge); err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	result := &oauth.Token{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(accessTokenMaxAge.Seconds()),
+	}
+
+	return result, nil
+}
+
+func (t *tokenOperator) IssueAuthorizationCode(u user.Info, client oauth.Client, redirectURI string) (string, error) {
+	info := &user.DefaultInfo{
+		Name: u.GetName(),
+		Extra: map[string][]string{
+			extraClientID:    {client.Name},
+			extraRedirectURI: {redirectURI},
+		},
+	}
+
+	code, err := t.issuer.IssueTo(info, token.AuthorizationCode, authorizationCodeMaxAge)
+	if err != nil {
+		klog.Error(err)
+		return "", err
+	}
+
+	// the code is recorded so that it can only be exchanged once
+	if err = t.store.Add(u.GetName(), code, authorizationCodeMaxAge); err != nil {
+		klog.Error(err)
+		return "", err
+	}
+
+	return code, nil
+}
+
+func (t *tokenOperator) VerifyAuthorizationCode(code string, client oauth.Client, redirectURI string) (user.Info, error) {
+	info, tokenType, err := t.issuer.Verify(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if tokenType != token.AuthorizationCode {
+		return nil, fmt.Errorf("unsupported token type %s", tokenType)
+	}
+
+	extra := info.GetExtra()
+	if !hasValue(extra[extraClientID], client.Name) || !hasValue(extra[extraRedirectURI], redirectURI) {
+		return nil, fmt.Errorf("authorization code was issued to another client")
+	}
+
+	// the authorization code must not be used more than once, see https://tools.ietf.org/html/rfc6749#section-4.1.2
+	consumed, err := t.store.Consume(info.GetName(), code)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	if !consumed {
+		return nil, fmt.Errorf("authorization code has been used or revoked")
+	}
+
+	return &user.DefaultInfo{Name: info.GetName()}, nil
+}
+
+func (t *tokenOperator) VerifyRefreshToken(refreshToken string, client oauth.Client) (user.Info, error) {
+	info, tokenType, err := t.Verify(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if tokenType != token.RefreshToken {
+		return nil, fmt.Errorf("unsupported token type %s", tokenType)
+	}
+
+	if !hasValue(info.GetExtra()[extraClientID], client.Name) {
+		return nil, fmt.Errorf("refresh token was issued to another client")
+	}
+
+	return &user.DefaultInfo{Name: info.GetName()}, nil
+}
+
+func (t *tokenOperator) IssueOAuthState(provider string, client oauth.Client) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	state := hex.EncodeToString(b)
+
+	if err := t.store.Add(oauthStateOwner, oauthStateKey(state, provider, client), oauthStateMaxAge); err != nil {
+		klog.Error(err)
+		return "", err
+	}
+	return state, nil
+}
+
+func (t *tokenOperator) VerifyOAuthState(state string, provider string, client oauth.Client) error {
+	if state == "" {
+		return fmt.Errorf("state is required")
+	}
+
+	consumed, err := t.store.Consume(oauthStateOwner, oauthStateKey(state, provider, client))
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if !consumed {
+		return fmt.Errorf("state has been used, expired or was issued for another login")
+	}
+	return nil
+}
+
+func (t *tokenOperator) RevokeAllUserTokens(username string) error {
+	if err := t.store.RevokeAll(username); err != nil {
+		klog.Error(err)
+		return err
+	}
+	return nil
+}
+
+// oauthStateKey binds the state to the identity provider and the client
+func oauthStateKey(state string, provider string, client oauth.Client) string {
+	return fmt.Sprintf("%s/%s/%s", provider, client.Name, state)
+}
+
+func hasValue(values []string, value string) bool {
+	return len(values) == 1 && values[0] == value
+}
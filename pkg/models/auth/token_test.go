@@ -0,0 +1,231 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/oauth"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authentication/token"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	corev1listers "k8s.io/client-go/listers/core/v1"
+)
+
+func newTestStores() map[string]func() token.Store {
+	return map[string]func() token.Store{
+		authentication.TokenStoreMemory: token.NewMemoryStore,
+		authentication.TokenStoreSecret: func() token.Store {
+			// the cache is never synced, the tokens are looked up from the API server
+			secretLister := corev1listers.NewSecretLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}))
+			return token.NewSecretStore(fake.NewSimpleClientset(), secretLister, "horizon-system")
+		},
+	}
+}
+
+func newTestTokenOperator(store token.Store, multipleLogin bool) TokenManagementInterface {
+	options := authentication.NewOptions()
+	options.JwtSecret = "secret"
+	options.MultipleLogin = multipleLogin
+	return NewTokenOperator(store, options)
+}
+
+func TestTokenOperator(t *testing.T) {
+	console := oauth.Client{Name: "console"}
+	kubectl := oauth.Client{Name: "kubectl"}
+	alice := &user.DefaultInfo{Name: "alice"}
+
+	tests := []struct {
+		name          string
+		multipleLogin bool
+		run           func(t *testing.T, operator TokenManagementInterface)
+	}{
+		{
+			name: "issued tokens are verified",
+			run: func(t *testing.T, operator TokenManagementInterface) {
+				issued := mustIssue(t, operator, alice, console)
+				u, tokenType, err := operator.Verify(issued.AccessToken)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if u.GetName() != alice.Name || tokenType != token.AccessToken {
+					t.Errorf("expected access token of %q, got %s of %q", alice.Name, tokenType, u.GetName())
+				}
+				if _, err = operator.VerifyRefreshToken(issued.RefreshToken, console); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			name: "access token is not a refresh token",
+			run: func(t *testing.T, operator TokenManagementInterface) {
+				issued := mustIssue(t, operator, alice, console)
+				if _, err := operator.VerifyRefreshToken(issued.AccessToken, console); err == nil {
+					t.Errorf("expected the access token to be rejected")
+				}
+			},
+		},
+		{
+			name: "refresh token of another client",
+			run: func(t *testing.T, operator TokenManagementInterface) {
+				issued := mustIssue(t, operator, alice, console)
+				if _, err := operator.VerifyRefreshToken(issued.RefreshToken, kubectl); err == nil {
+					t.Errorf("expected the refresh token of another client to be rejected")
+				}
+			},
+		},
+		{
+			name: "tokens signed with another secret",
+			run: func(t *testing.T, operator TokenManagementInterface) {
+				forged, err := token.NewTokenIssuer("another secret", 0).IssueTo(alice, token.AccessToken, time.Hour)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if _, _, err = operator.Verify(forged); err == nil {
+					t.Errorf("expected the forged token to be rejected")
+				}
+			},
+		},
+		{
+			name: "tokens of another issuer",
+			run: func(t *testing.T, operator TokenManagementInterface) {
+				forged := mustSign(t, &token.Claims{
+					Username:         alice.Name,
+					TokenType:        token.AccessToken,
+					RegisteredClaims: jwt.RegisteredClaims{Issuer: "another", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+				})
+				if _, _, err := operator.Verify(forged); err == nil {
+					t.Errorf("expected the token of another issuer to be rejected")
+				}
+			},
+		},
+		{
+			name: "access tokens without expiration",
+			run: func(t *testing.T, operator TokenManagementInterface) {
+				forged := mustSign(t, &token.Claims{
+					Username:         alice.Name,
+					TokenType:        token.AccessToken,
+					RegisteredClaims: jwt.RegisteredClaims{Issuer: token.DefaultIssuerName},
+				})
+				if _, _, err := operator.Verify(forged); err == nil {
+					t.Errorf("expected the access token without expiration to be rejected")
+				}
+			},
+		},
+		{
+			name: "revoked tokens",
+			run: func(t *testing.T, operator TokenManagementInterface) {
+				issued := mustIssue(t, operator, alice, console)
+				if err := operator.RevokeAllUserTokens(alice.Name); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if _, _, err := operator.Verify(issued.AccessToken); err == nil {
+					t.Errorf("expected the revoked access token to be rejected")
+				}
+				if _, err := operator.VerifyRefreshToken(issued.RefreshToken, console); err == nil {
+					t.Errorf("expected the revoked refresh token to be rejected")
+				}
+			},
+		},
+		{
+			name: "tokens issued before are revoked on login",
+			run: func(t *testing.T, operator TokenManagementInterface) {
+				first := mustIssue(t, operator, alice, console)
+				second := mustIssue(t, operator, alice, console)
+				if _, _, err := operator.Verify(first.AccessToken); err == nil {
+					t.Errorf("expected the access token issued before to be rejected")
+				}
+				if _, _, err := operator.Verify(second.AccessToken); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			name:          "tokens issued before are kept with multiple login",
+			multipleLogin: true,
+			run: func(t *testing.T, operator TokenManagementInterface) {
+				first := mustIssue(t, operator, alice, console)
+				mustIssue(t, operator, alice, console)
+				if _, _, err := operator.Verify(first.AccessToken); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			name: "authorization code is exchanged once",
+			run: func(t *testing.T, operator TokenManagementInterface) {
+				code, err := operator.IssueAuthorizationCode(alice, console, "https://console/callback")
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if _, err = operator.VerifyAuthorizationCode(code, kubectl, "https://console/callback"); err == nil {
+					t.Errorf("expected the code of another client to be rejected")
+				}
+				if _, err = operator.VerifyAuthorizationCode(code, console, "https://evil/callback"); err == nil {
+					t.Errorf("expected the code of another redirect uri to be rejected")
+				}
+				u, err := operator.VerifyAuthorizationCode(code, console, "https://console/callback")
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if u.GetName() != alice.Name {
+					t.Errorf("expected user %q, got %q", alice.Name, u.GetName())
+				}
+				if _, err = operator.VerifyAuthorizationCode(code, console, "https://console/callback"); err == nil {
+					t.Errorf("expected the code to be rejected once exchanged")
+				}
+			},
+		},
+		{
+			name: "oauth state is verified once",
+			run: func(t *testing.T, operator TokenManagementInterface) {
+				state, err := operator.IssueOAuthState("github", console)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if err = operator.VerifyOAuthState(state, "oidc", console); err == nil {
+					t.Errorf("expected the state of another identity provider to be rejected")
+				}
+				if err = operator.VerifyOAuthState(state, "github", kubectl); err == nil {
+					t.Errorf("expected the state of another client to be rejected")
+				}
+				if err = operator.VerifyOAuthState(state, "github", console); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if err = operator.VerifyOAuthState(state, "github", console); err == nil {
+					t.Errorf("expected the state to be rejected once verified")
+				}
+			},
+		},
+	}
+
+	for storeName, newStore := range newTestStores() {
+		for _, test := range tests {
+			t.Run(storeName+"/"+test.name, func(t *testing.T) {
+				test.run(t, newTestTokenOperator(newStore(), test.multipleLogin))
+			})
+		}
+	}
+}
+
+func mustIssue(t *testing.T, operator TokenManagementInterface, u user.Info, client oauth.Client) *oauth.Token {
+	t.Helper()
+	issued, err := operator.IssueTo(u, client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return issued
+}
+
+// mustSign signs the claims with the secret of the test operator
+func mustSign(t *testing.T, claims *token.Claims) string {
+	t.Helper()
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return signed
+}
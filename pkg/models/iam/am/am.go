@@ -1,12 +1,91 @@
 package am
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sunweiwe/horizon/pkg/api"
+	"github.com/sunweiwe/horizon/pkg/apiserver/query"
 	"github.com/sunweiwe/horizon/pkg/client/clientset"
 	"github.com/sunweiwe/horizon/pkg/informers"
+	"github.com/sunweiwe/horizon/pkg/models/resources/globalrole"
+	"github.com/sunweiwe/horizon/pkg/models/resources/role"
+	"github.com/sunweiwe/horizon/pkg/models/resources/workspacerole"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	tenantv1alpha1 "github.com/sunweiwe/api/tenant/v1alpha1"
+	iamv1alpha2listers "github.com/sunweiwe/horizon/pkg/client/listers/iam/v1alpha2"
+	resources "github.com/sunweiwe/horizon/pkg/models/resources/v1alpha3"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	rbacv1listers "k8s.io/client-go/listers/rbac/v1"
 )
 
 type AccessManagementInterface interface {
+	// ListGlobalRoleBindings lists the global role bindings whose subjects include the user or its groups,
+	// the groups of workspaces never apply to the global role bindings
+	ListGlobalRoleBindings(username string, groups []string) ([]*iamv1alpha2.GlobalRoleBinding, error)
+	// ListWorkspaceRoleBindings lists the role bindings of the workspace whose subjects include the user or its groups,
+	// the role bindings of all the workspaces are listed if the workspace is empty, the groups of workspaces only
+	// apply to the role bindings of their workspaces
+	ListWorkspaceRoleBindings(username string, groups []string, workspace string) ([]*iamv1alpha2.WorkspaceRoleBinding, error)
+	// ListClusterRoleBindings lists the cluster role bindings whose subjects include the user or its groups,
+	// the groups of workspaces never apply to the cluster role bindings
+	ListClusterRoleBindings(username string, groups []string) ([]*rbacv1.ClusterRoleBinding, error)
+	// ListRoleBindings lists the role bindings in the namespace whose subjects include the user or its groups,
+	// the role bindings of all the namespaces are listed if the namespace is empty, the groups of workspaces
+	// only apply to the namespaces of their workspaces
+	ListRoleBindings(username string, groups []string, namespace string) ([]*rbacv1.RoleBinding, error)
+	// GetRoleReferenceRules returns the rules of the role referenced by the binding in the workspace or namespace,
+	// the role could be a GlobalRole, WorkspaceRole, ClusterRole or Role, the WorkspaceRole must belong to the workspace
+	GetRoleReferenceRules(roleRef rbacv1.RoleRef, workspace string, namespace string) ([]rbacv1.PolicyRule, error)
+	// GetNamespaceControlledWorkspace returns the workspace the namespace belongs to, empty if none
+	GetNamespaceControlledWorkspace(namespace string) (string, error)
+	// FilterWorkspaceGroups returns the groups that apply in the workspace, the groups of the other workspaces
+	// are dropped, only the groups outside workspaces are returned if the workspace is empty
+	FilterWorkspaceGroups(groups []string, workspace string) []string
+
+	// GetGlobalRoleOfUser returns the global role bound to the user
+	GetGlobalRoleOfUser(username string) (*iamv1alpha2.GlobalRole, error)
+	// GetWorkspaceRoleOfUser returns the roles of the workspace bound to the user and its groups
+	GetWorkspaceRoleOfUser(username string, groups []string, workspace string) ([]*iamv1alpha2.WorkspaceRole, error)
+	// GetNamespaceRoleOfUser returns the roles of the namespace bound to the user and its groups
+	GetNamespaceRoleOfUser(username string, groups []string, namespace string) ([]*rbacv1.Role, error)
+
+	ListGlobalRoles(query *query.Query) (*api.ListResult, error)
+	ListWorkspaceRoles(workspace string, query *query.Query) (*api.ListResult, error)
+	ListRoles(namespace string, query *query.Query) (*api.ListResult, error)
+	GetGlobalRole(name string) (*iamv1alpha2.GlobalRole, error)
+	GetWorkspaceRole(workspace string, name string) (*iamv1alpha2.WorkspaceRole, error)
+	GetNamespaceRole(namespace string, name string) (*rbacv1.Role, error)
+	CreateOrUpdateGlobalRole(role *iamv1alpha2.GlobalRole) (*iamv1alpha2.GlobalRole, error)
+	CreateOrUpdateWorkspaceRole(workspace string, role *iamv1alpha2.WorkspaceRole) (*iamv1alpha2.WorkspaceRole, error)
+	CreateOrUpdateNamespaceRole(namespace string, role *rbacv1.Role) (*rbacv1.Role, error)
+	// DeleteGlobalRole deletes the global role along with the bindings created for it
+	DeleteGlobalRole(name string) error
+	// DeleteWorkspaceRole deletes the workspace role along with the bindings created for it
+	DeleteWorkspaceRole(workspace string, name string) error
+	// DeleteNamespaceRole deletes the role along with the bindings created for it
+	DeleteNamespaceRole(namespace string, name string) error
+
+	// CreateOrUpdateGlobalRoleBinding binds the global role to the user, the global role bound before is unbound
+	CreateOrUpdateGlobalRoleBinding(username string, role string) error
+	// CreateOrUpdateWorkspaceRoleBinding binds the workspace role to the user, the workspace role bound before is unbound
+	CreateOrUpdateWorkspaceRoleBinding(username string, workspace string, role string) error
+	// CreateOrUpdateRoleBinding binds the role of the namespace to the user, the role bound before is unbound
+	CreateOrUpdateRoleBinding(username string, namespace string, role string) error
+	// RemoveUserFromWorkspace removes the workspace role bindings of the user and its role bindings in the namespaces of the workspace
+	RemoveUserFromWorkspace(username string, workspace string) error
+	// RemoveUserFromNamespace removes the role bindings of the user in the namespace
+	RemoveUserFromNamespace(username string, namespace string) error
 }
 
 func NewOperator(kube kubernetes.Interface, horizon clientset.Interface, factory informers.InformerFactory) AccessManagementInterface {
@@ -18,12 +97,627 @@ func NewOperator(kube kubernetes.Interface, horizon clientset.Interface, factory
 }
 
 type amOperator struct {
-	kube    kubernetes.Interface
-	horizon clientset.Interface
+	kube                       kubernetes.Interface
+	horizon                    clientset.Interface
+	roleLister                 rbacv1listers.RoleLister
+	roleBindingLister          rbacv1listers.RoleBindingLister
+	clusterRoleLister          rbacv1listers.ClusterRoleLister
+	clusterRoleBindingLister   rbacv1listers.ClusterRoleBindingLister
+	globalRoleLister           iamv1alpha2listers.GlobalRoleLister
+	globalRoleBindingLister    iamv1alpha2listers.GlobalRoleBindingLister
+	workspaceRoleLister        iamv1alpha2listers.WorkspaceRoleLister
+	workspaceRoleBindingLister iamv1alpha2listers.WorkspaceRoleBindingLister
+	groupLister                iamv1alpha2listers.GroupLister
+	namespaceLister            corev1listers.NamespaceLister
+	globalRoleGetter           resources.Interface
+	workspaceRoleGetter        resources.Interface
+	roleGetter                 resources.Interface
 }
 
 func NewReadOnlyOperator(factory informers.InformerFactory) AccessManagementInterface {
-	operator := &amOperator{}
+	rbacInformers := factory.KubernetesSharedInformerFactory().Rbac().V1()
+	iamInformers := factory.HorizonSharedInformerFactory().Iam().V1alpha2()
+	operator := &amOperator{
+		roleLister:                 rbacInformers.Roles().Lister(),
+		roleBindingLister:          rbacInformers.RoleBindings().Lister(),
+		clusterRoleLister:          rbacInformers.ClusterRoles().Lister(),
+		clusterRoleBindingLister:   rbacInformers.ClusterRoleBindings().Lister(),
+		globalRoleLister:           iamInformers.GlobalRoles().Lister(),
+		globalRoleBindingLister:    iamInformers.GlobalRoleBindings().Lister(),
+		workspaceRoleLister:        iamInformers.WorkspaceRoles().Lister(),
+		workspaceRoleBindingLister: iamInformers.WorkspaceRoleBindings().Lister(),
+		groupLister:                iamInformers.Groups().Lister(),
+		namespaceLister:            factory.KubernetesSharedInformerFactory().Core().V1().Namespaces().Lister(),
+		globalRoleGetter:           globalrole.New(factory.HorizonSharedInformerFactory()),
+		workspaceRoleGetter:        workspacerole.New(factory.HorizonSharedInformerFactory()),
+		roleGetter:                 role.New(factory.KubernetesSharedInformerFactory()),
+	}
 
 	return operator
 }
+
+func (am *amOperator) ListGlobalRoleBindings(username string, groups []string) ([]*iamv1alpha2.GlobalRoleBinding, error) {
+	globalRoleBindings, err := am.globalRoleBindingLister.List(labels.Everything())
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	groups = am.FilterWorkspaceGroups(groups, "")
+	result := make([]*iamv1alpha2.GlobalRoleBinding, 0)
+	for _, globalRoleBinding := range globalRoleBindings {
+		if containsUser(globalRoleBinding.Subjects, username, groups, "") {
+			result = append(result, globalRoleBinding)
+		}
+	}
+	return result, nil
+}
+
+func (am *amOperator) ListWorkspaceRoleBindings(username string, groups []string, workspace string) ([]*iamv1alpha2.WorkspaceRoleBinding, error) {
+	selector := labels.Everything()
+	if workspace != "" {
+		selector = labels.SelectorFromSet(labels.Set{tenantv1alpha1.WorkspaceLabel: workspace})
+	}
+	workspaceRoleBindings, err := am.workspaceRoleBindingLister.List(selector)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	result := make([]*iamv1alpha2.WorkspaceRoleBinding, 0)
+	for _, workspaceRoleBinding := range workspaceRoleBindings {
+		workspaceGroups := am.FilterWorkspaceGroups(groups, workspaceRoleBinding.Labels[tenantv1alpha1.WorkspaceLabel])
+		if containsUser(workspaceRoleBinding.Subjects, username, workspaceGroups, "") {
+			result = append(result, workspaceRoleBinding)
+		}
+	}
+	return result, nil
+}
+
+func (am *amOperator) ListClusterRoleBindings(username string, groups []string) ([]*rbacv1.ClusterRoleBinding, error) {
+	clusterRoleBindings, err := am.clusterRoleBindingLister.List(labels.Everything())
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	groups = am.FilterWorkspaceGroups(groups, "")
+	result := make([]*rbacv1.ClusterRoleBinding, 0)
+	for _, clusterRoleBinding := range clusterRoleBindings {
+		if containsUser(clusterRoleBinding.Subjects, username, groups, "") {
+			result = append(result, clusterRoleBinding)
+		}
+	}
+	return result, nil
+}
+
+func (am *amOperator) ListRoleBindings(username string, groups []string, namespace string) ([]*rbacv1.RoleBinding, error) {
+	roleBindings, err := am.roleBindingLister.RoleBindings(namespace).List(labels.Everything())
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	workspace := ""
+	if namespace != "" {
+		workspace, err = am.GetNamespaceControlledWorkspace(namespace)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	groups = am.FilterWorkspaceGroups(groups, workspace)
+	result := make([]*rbacv1.RoleBinding, 0)
+	for _, roleBinding := range roleBindings {
+		if containsUser(roleBinding.Subjects, username, groups, roleBinding.Namespace) {
+			result = append(result, roleBinding)
+		}
+	}
+	return result, nil
+}
+
+func (am *amOperator) GetRoleReferenceRules(roleRef rbacv1.RoleRef, workspace string, namespace string) ([]rbacv1.PolicyRule, error) {
+	switch roleRef.Kind {
+	case iamv1alpha2.ResourceKindGlobalRole:
+		globalRole, err := am.globalRoleLister.Get(roleRef.Name)
+		if err != nil {
+			return nil, err
+		}
+		return globalRole.Rules, nil
+	case iamv1alpha2.ResourceKindWorkspaceRole:
+		// the bindings can not refer to the roles of other workspaces
+		workspaceRole, err := am.GetWorkspaceRole(workspace, roleRef.Name)
+		if err != nil {
+			return nil, err
+		}
+		return workspaceRole.Rules, nil
+	case "Role":
+		role, err := am.roleLister.Roles(namespace).Get(roleRef.Name)
+		if err != nil {
+			return nil, err
+		}
+		return role.Rules, nil
+	case "ClusterRole":
+		clusterRole, err := am.clusterRoleLister.Get(roleRef.Name)
+		if err != nil {
+			return nil, err
+		}
+		return clusterRole.Rules, nil
+	default:
+		return nil, fmt.Errorf("unsupported role reference kind: %q", roleRef.Kind)
+	}
+}
+
+func (am *amOperator) GetNamespaceControlledWorkspace(namespace string) (string, error) {
+	ns, err := am.namespaceLister.Get(namespace)
+	if err != nil {
+		klog.Error(err)
+		return "", err
+	}
+	return ns.Labels[tenantv1alpha1.WorkspaceLabel], nil
+}
+
+func (am *amOperator) FilterWorkspaceGroups(groups []string, workspace string) []string {
+	result := make([]string, 0, len(groups))
+	for _, name := range groups {
+		// the groups unknown to horizon, e.g. system:authenticated, do not belong to any workspace
+		group, err := am.groupLister.Get(name)
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.Error(err)
+			continue
+		}
+		if group == nil || group.Labels[tenantv1alpha1.WorkspaceLabel] == "" || group.Labels[tenantv1alpha1.WorkspaceLabel] == workspace {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+func (am *amOperator) GetGlobalRoleOfUser(username string) (*iamv1alpha2.GlobalRole, error) {
+	globalRoleBindings, err := am.ListGlobalRoleBindings(username, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(globalRoleBindings) == 0 {
+		err := apierrors.NewNotFound(iamv1alpha2.Resource(iamv1alpha2.ResourcePluralGlobalRole), username)
+		klog.V(4).Info(err)
+		return nil, err
+	}
+
+	if len(globalRoleBindings) > 1 {
+		klog.Warningf("conflict global role binding, username: %s", username)
+	}
+
+	return am.globalRoleLister.Get(globalRoleBindings[0].RoleRef.Name)
+}
+
+func (am *amOperator) GetWorkspaceRoleOfUser(username string, groups []string, workspace string) ([]*iamv1alpha2.WorkspaceRole, error) {
+	workspaceRoleBindings, err := am.ListWorkspaceRoleBindings(username, groups, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*iamv1alpha2.WorkspaceRole, 0)
+	for _, workspaceRoleBinding := range workspaceRoleBindings {
+		workspaceRole, err := am.workspaceRoleLister.Get(workspaceRoleBinding.RoleRef.Name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				klog.Warningf("invalid workspace role binding found: %s", workspaceRoleBinding.Name)
+				continue
+			}
+			klog.Error(err)
+			return nil, err
+		}
+		result = append(result, workspaceRole)
+	}
+	return result, nil
+}
+
+func (am *amOperator) GetNamespaceRoleOfUser(username string, groups []string, namespace string) ([]*rbacv1.Role, error) {
+	roleBindings, err := am.ListRoleBindings(username, groups, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*rbacv1.Role, 0)
+	for _, roleBinding := range roleBindings {
+		if roleBinding.RoleRef.Kind != "Role" {
+			continue
+		}
+		role, err := am.roleLister.Roles(namespace).Get(roleBinding.RoleRef.Name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				klog.Warningf("invalid role binding found: %s/%s", namespace, roleBinding.Name)
+				continue
+			}
+			klog.Error(err)
+			return nil, err
+		}
+		result = append(result, role)
+	}
+	return result, nil
+}
+
+func (am *amOperator) ListGlobalRoles(query *query.Query) (*api.ListResult, error) {
+	return am.globalRoleGetter.List("", query)
+}
+
+func (am *amOperator) ListWorkspaceRoles(workspace string, q *query.Query) (*api.ListResult, error) {
+	q.Filters[query.FieldLabel] = query.Value(fmt.Sprintf("%s=%s", tenantv1alpha1.WorkspaceLabel, workspace))
+	return am.workspaceRoleGetter.List("", q)
+}
+
+func (am *amOperator) ListRoles(namespace string, query *query.Query) (*api.ListResult, error) {
+	return am.roleGetter.List(namespace, query)
+}
+
+func (am *amOperator) GetGlobalRole(name string) (*iamv1alpha2.GlobalRole, error) {
+	return am.globalRoleLister.Get(name)
+}
+
+func (am *amOperator) GetWorkspaceRole(workspace string, name string) (*iamv1alpha2.WorkspaceRole, error) {
+	workspaceRole, err := am.workspaceRoleLister.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// the roles of other workspaces are invisible
+	if workspaceRole.Labels[tenantv1alpha1.WorkspaceLabel] != workspace {
+		return nil, apierrors.NewNotFound(iamv1alpha2.Resource(iamv1alpha2.ResourcePluralWorkspaceRole), name)
+	}
+	return workspaceRole, nil
+}
+
+func (am *amOperator) GetNamespaceRole(namespace string, name string) (*rbacv1.Role, error) {
+	return am.roleLister.Roles(namespace).Get(name)
+}
+
+func (am *amOperator) CreateOrUpdateGlobalRole(globalRole *iamv1alpha2.GlobalRole) (*iamv1alpha2.GlobalRole, error) {
+	if err := validateAggregationRoleTemplates(iamv1alpha2.Resource(iamv1alpha2.ResourcePluralGlobalRole), globalRole); err != nil {
+		return nil, err
+	}
+
+	globalRole = globalRole.DeepCopy()
+	old, err := am.globalRoleLister.Get(globalRole.Name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		return am.horizon.IamV1alpha2().GlobalRoles().Create(context.Background(), globalRole, metav1.CreateOptions{})
+	}
+
+	if globalRole.ResourceVersion == "" {
+		globalRole.ResourceVersion = old.ResourceVersion
+	}
+	return am.horizon.IamV1alpha2().GlobalRoles().Update(context.Background(), globalRole, metav1.UpdateOptions{})
+}
+
+func (am *amOperator) CreateOrUpdateWorkspaceRole(workspace string, workspaceRole *iamv1alpha2.WorkspaceRole) (*iamv1alpha2.WorkspaceRole, error) {
+	if err := validateAggregationRoleTemplates(iamv1alpha2.Resource(iamv1alpha2.ResourcePluralWorkspaceRole), workspaceRole); err != nil {
+		return nil, err
+	}
+
+	workspaceRole = workspaceRole.DeepCopy()
+	if workspaceRole.Labels == nil {
+		workspaceRole.Labels = make(map[string]string)
+	}
+	workspaceRole.Labels[tenantv1alpha1.WorkspaceLabel] = workspace
+
+	old, err := am.workspaceRoleLister.Get(workspaceRole.Name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		return am.horizon.IamV1alpha2().WorkspaceRoles().Create(context.Background(), workspaceRole, metav1.CreateOptions{})
+	}
+
+	if old.Labels[tenantv1alpha1.WorkspaceLabel] != workspace {
+		return nil, apierrors.NewConflict(iamv1alpha2.Resource(iamv1alpha2.ResourcePluralWorkspaceRole), workspaceRole.Name,
+			fmt.Errorf("the name is already used by a role of another workspace"))
+	}
+	if workspaceRole.ResourceVersion == "" {
+		workspaceRole.ResourceVersion = old.ResourceVersion
+	}
+	return am.horizon.IamV1alpha2().WorkspaceRoles().Update(context.Background(), workspaceRole, metav1.UpdateOptions{})
+}
+
+func (am *amOperator) CreateOrUpdateNamespaceRole(namespace string, role *rbacv1.Role) (*rbacv1.Role, error) {
+	if err := validateAggregationRoleTemplates(rbacv1.Resource("roles"), role); err != nil {
+		return nil, err
+	}
+
+	role = role.DeepCopy()
+	role.Namespace = namespace
+
+	old, err := am.roleLister.Roles(namespace).Get(role.Name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		return am.kube.RbacV1().Roles(namespace).Create(context.Background(), role, metav1.CreateOptions{})
+	}
+
+	if role.ResourceVersion == "" {
+		role.ResourceVersion = old.ResourceVersion
+	}
+	return am.kube.RbacV1().Roles(namespace).Update(context.Background(), role, metav1.UpdateOptions{})
+}
+
+func (am *amOperator) DeleteGlobalRole(name string) error {
+	err := am.horizon.IamV1alpha2().GlobalRoles().Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil {
+		return err
+	}
+
+	return am.horizon.IamV1alpha2().GlobalRoleBindings().DeleteCollection(context.Background(), metav1.DeleteOptions{},
+		metav1.ListOptions{LabelSelector: roleReferenceSelector(name, nil)})
+}
+
+func (am *amOperator) DeleteWorkspaceRole(workspace string, name string) error {
+	if _, err := am.GetWorkspaceRole(workspace, name); err != nil {
+		return err
+	}
+
+	err := am.horizon.IamV1alpha2().WorkspaceRoles().Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil {
+		return err
+	}
+
+	return am.horizon.IamV1alpha2().WorkspaceRoleBindings().DeleteCollection(context.Background(), metav1.DeleteOptions{},
+		metav1.ListOptions{LabelSelector: roleReferenceSelector(name, labels.Set{tenantv1alpha1.WorkspaceLabel: workspace})})
+}
+
+func (am *amOperator) DeleteNamespaceRole(namespace string, name string) error {
+	err := am.kube.RbacV1().Roles(namespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil {
+		return err
+	}
+
+	return am.kube.RbacV1().RoleBindings(namespace).DeleteCollection(context.Background(), metav1.DeleteOptions{},
+		metav1.ListOptions{LabelSelector: roleReferenceSelector(name, nil)})
+}
+
+func (am *amOperator) CreateOrUpdateGlobalRoleBinding(username string, role string) error {
+	if _, err := am.globalRoleLister.Get(role); err != nil {
+		return err
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{iamv1alpha2.UserReferenceLabel: username})
+	globalRoleBindings, err := am.globalRoleBindingLister.List(selector)
+	if err != nil {
+		return err
+	}
+
+	bound := false
+	for _, globalRoleBinding := range globalRoleBindings {
+		if globalRoleBinding.RoleRef.Name == role {
+			bound = true
+			continue
+		}
+		err := am.horizon.IamV1alpha2().GlobalRoleBindings().Delete(context.Background(), globalRoleBinding.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.Error(err)
+			return err
+		}
+	}
+	if bound {
+		return nil
+	}
+
+	globalRoleBinding := &iamv1alpha2.GlobalRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   fmt.Sprintf("%s-%s", username, role),
+			Labels: map[string]string{iamv1alpha2.UserReferenceLabel: username, iamv1alpha2.RoleReferenceLabel: role},
+		},
+		Subjects: userSubjects(username),
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: iamv1alpha2.SchemeGroupVersion.Group,
+			Kind:     iamv1alpha2.ResourceKindGlobalRole,
+			Name:     role,
+		},
+	}
+
+	_, err = am.horizon.IamV1alpha2().GlobalRoleBindings().Create(context.Background(), globalRoleBinding, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		klog.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (am *amOperator) CreateOrUpdateWorkspaceRoleBinding(username string, workspace string, role string) error {
+	if _, err := am.GetWorkspaceRole(workspace, role); err != nil {
+		return err
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{iamv1alpha2.UserReferenceLabel: username, tenantv1alpha1.WorkspaceLabel: workspace})
+	workspaceRoleBindings, err := am.workspaceRoleBindingLister.List(selector)
+	if err != nil {
+		return err
+	}
+
+	bound := false
+	for _, workspaceRoleBinding := range workspaceRoleBindings {
+		if workspaceRoleBinding.RoleRef.Name == role {
+			bound = true
+			continue
+		}
+		err := am.horizon.IamV1alpha2().WorkspaceRoleBindings().Delete(context.Background(), workspaceRoleBinding.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.Error(err)
+			return err
+		}
+	}
+	if bound {
+		return nil
+	}
+
+	workspaceRoleBinding := &iamv1alpha2.WorkspaceRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%s-%s", username, role),
+			Labels: map[string]string{
+				iamv1alpha2.UserReferenceLabel: username,
+				iamv1alpha2.RoleReferenceLabel: role,
+				tenantv1alpha1.WorkspaceLabel:  workspace,
+			},
+		},
+		Subjects: userSubjects(username),
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: iamv1alpha2.SchemeGroupVersion.Group,
+			Kind:     iamv1alpha2.ResourceKindWorkspaceRole,
+			Name:     role,
+		},
+	}
+
+	_, err = am.horizon.IamV1alpha2().WorkspaceRoleBindings().Create(context.Background(), workspaceRoleBinding, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		klog.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (am *amOperator) CreateOrUpdateRoleBinding(username string, namespace string, role string) error {
+	if _, err := am.roleLister.Roles(namespace).Get(role); err != nil {
+		return err
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{iamv1alpha2.UserReferenceLabel: username})
+	roleBindings, err := am.roleBindingLister.RoleBindings(namespace).List(selector)
+	if err != nil {
+		return err
+	}
+
+	bound := false
+	for _, roleBinding := range roleBindings {
+		if roleBinding.RoleRef.Name == role {
+			bound = true
+			continue
+		}
+		err := am.kube.RbacV1().RoleBindings(namespace).Delete(context.Background(), roleBinding.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.Error(err)
+			return err
+		}
+	}
+	if bound {
+		return nil
+	}
+
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", username, role),
+			Namespace: namespace,
+			Labels:    map[string]string{iamv1alpha2.UserReferenceLabel: username, iamv1alpha2.RoleReferenceLabel: role},
+		},
+		Subjects: userSubjects(username),
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     role,
+		},
+	}
+
+	_, err = am.kube.RbacV1().RoleBindings(namespace).Create(context.Background(), roleBinding, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		klog.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (am *amOperator) RemoveUserFromWorkspace(username string, workspace string) error {
+	selector := labels.SelectorFromSet(labels.Set{iamv1alpha2.UserReferenceLabel: username, tenantv1alpha1.WorkspaceLabel: workspace}).String()
+	err := am.horizon.IamV1alpha2().WorkspaceRoleBindings().DeleteCollection(context.Background(), metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	namespaces, err := am.namespaceLister.List(labels.SelectorFromSet(labels.Set{tenantv1alpha1.WorkspaceLabel: workspace}))
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	for _, namespace := range namespaces {
+		if err := am.RemoveUserFromNamespace(username, namespace.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (am *amOperator) RemoveUserFromNamespace(username string, namespace string) error {
+	selector := labels.SelectorFromSet(labels.Set{iamv1alpha2.UserReferenceLabel: username}).String()
+	err := am.kube.RbacV1().RoleBindings(namespace).DeleteCollection(context.Background(), metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	return nil
+}
+
+// roleReferenceSelector returns the label selector of the bindings created for the role
+func roleReferenceSelector(role string, set labels.Set) string {
+	selector := labels.Set{iamv1alpha2.RoleReferenceLabel: role}
+	for k, v := range set {
+		selector[k] = v
+	}
+	return labels.SelectorFromSet(selector).String()
+}
+
+func userSubjects(username string) []rbacv1.Subject {
+	return []rbacv1.Subject{{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: username}}
+}
+
+// containsUser returns whether the subjects include the user or any of its groups.
+func containsUser(subjects []rbacv1.Subject, username string, groups []string, namespace string) bool {
+	_, ok := AppliesTo(subjects, username, groups, namespace)
+	return ok
+}
+
+// AppliesTo returns whether any of the subjects is the user or any of its groups, and if true, the index
+// of the first subject that applies. The namespace is used to default the namespace of service accounts.
+func AppliesTo(subjects []rbacv1.Subject, username string, groups []string, namespace string) (int, bool) {
+	for i, subject := range subjects {
+		switch subject.Kind {
+		case rbacv1.UserKind:
+			if subject.Name == username {
+				return i, true
+			}
+		case rbacv1.GroupKind:
+			for _, group := range groups {
+				if subject.Name == group {
+					return i, true
+				}
+			}
+		case rbacv1.ServiceAccountKind:
+			saNamespace := subject.Namespace
+			if saNamespace == "" {
+				saNamespace = namespace
+			}
+			if saNamespace != "" && serviceaccount.MakeUsername(saNamespace, subject.Name) == username {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// validateAggregationRoleTemplates rejects the roles whose aggregation role templates annotation is not
+// a JSON array of role template names, the rules of the templates are aggregated by the controller.
+func validateAggregationRoleTemplates(resource schema.GroupResource, role metav1.Object) error {
+	value, ok := role.GetAnnotations()[iamv1alpha2.AggregationRoleTemplatesAnnotation]
+	if !ok {
+		return nil
+	}
+
+	var templates []string
+	if err := json.Unmarshal([]byte(value), &templates); err != nil {
+		return apierrors.NewBadRequest(fmt.Sprintf("invalid annotation %s of %s %s: %v",
+			iamv1alpha2.AggregationRoleTemplatesAnnotation, resource.String(), role.GetName(), err))
+	}
+	return nil
+}
@@ -0,0 +1,347 @@
+package group
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sunweiwe/horizon/pkg/api"
+	"github.com/sunweiwe/horizon/pkg/apiserver/query"
+	"github.com/sunweiwe/horizon/pkg/client/clientset"
+	"github.com/sunweiwe/horizon/pkg/models/iam/am"
+	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/klog/v2"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	tenantv1alpha1 "github.com/sunweiwe/api/tenant/v1alpha1"
+	resources "github.com/sunweiwe/horizon/pkg/models/resources/v1alpha3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// GroupOperator manages the groups of workspaces and the bindings between users and groups
+type GroupOperator interface {
+	ListGroups(workspace string, query *query.Query) (*api.ListResult, error)
+	// CreateGroup creates the group in the workspace, the names of groups are cluster-wide so that the name
+	// is generated from the workspace and the requested name, e.g. <workspace>-<name>-<random suffix>
+	CreateGroup(workspace string, group *iamv1alpha2.Group) (*iamv1alpha2.Group, error)
+	DescribeGroup(workspace, group string) (*iamv1alpha2.Group, error)
+	UpdateGroup(workspace string, group *iamv1alpha2.Group) (*iamv1alpha2.Group, error)
+	// DeleteGroup deletes the group, the child groups and the group bindings are garbage collected
+	DeleteGroup(workspace, group string) error
+	// ListGroupMembers lists the users bound to the group
+	ListGroupMembers(workspace, group string, query *query.Query) (*api.ListResult, error)
+	ListGroupBindings(workspace string, query *query.Query) (*api.ListResult, error)
+	CreateGroupBinding(workspace string, groupBinding *iamv1alpha2.GroupBinding) (*iamv1alpha2.GroupBinding, error)
+	DeleteGroupBinding(workspace, groupBinding string) error
+}
+
+type groupOperator struct {
+	horizonClient      clientset.Interface
+	am                 am.AccessManagementInterface
+	groupGetter        resources.Interface
+	groupBindingGetter resources.Interface
+	userGetter         resources.Interface
+}
+
+func New(horizonClient clientset.Interface, am am.AccessManagementInterface, groupGetter resources.Interface, groupBindingGetter resources.Interface, userGetter resources.Interface) GroupOperator {
+	return &groupOperator{
+		horizonClient:      horizonClient,
+		am:                 am,
+		groupGetter:        groupGetter,
+		groupBindingGetter: groupBindingGetter,
+		userGetter:         userGetter,
+	}
+}
+
+func (o *groupOperator) ListGroups(workspace string, query *query.Query) (*api.ListResult, error) {
+	appendLabelSelector(query, tenantv1alpha1.WorkspaceLabel, workspace)
+
+	result, err := o.groupGetter.List("", query)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (o *groupOperator) CreateGroup(workspace string, group *iamv1alpha2.Group) (*iamv1alpha2.Group, error) {
+	group = group.DeepCopy()
+	if group.Labels == nil {
+		group.Labels = make(map[string]string)
+	}
+	group.Labels[tenantv1alpha1.WorkspaceLabel] = workspace
+
+	// the groups are subjects of the role bindings, the callers can not choose the names of the groups,
+	// otherwise they could take over the bindings of the groups outside the workspace
+	prefix := strings.TrimSuffix(group.Name, "-")
+	if prefix == "" {
+		prefix = strings.TrimSuffix(group.GenerateName, "-")
+	}
+	if prefix == "" {
+		prefix = workspace
+	} else {
+		prefix = fmt.Sprintf("%s-%s", workspace, prefix)
+	}
+	group.Name = names.SimpleNameGenerator.GenerateName(prefix + "-")
+	group.GenerateName = ""
+
+	if err := o.validateSubject(workspace, group.Name); err != nil {
+		return nil, err
+	}
+	if err := o.validateParent(workspace, group); err != nil {
+		return nil, err
+	}
+
+	created, err := o.horizonClient.IamV1alpha2().Groups().Create(context.Background(), group, metav1.CreateOptions{})
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	return created, nil
+}
+
+func (o *groupOperator) DescribeGroup(workspace, name string) (*iamv1alpha2.Group, error) {
+	obj, err := o.groupGetter.Get("", name)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	group := obj.(*iamv1alpha2.Group)
+	// groups of other workspaces are invisible
+	if group.Labels[tenantv1alpha1.WorkspaceLabel] != workspace {
+		return nil, apierrors.NewNotFound(iamv1alpha2.Resource(iamv1alpha2.ResourcePluralGroup), name)
+	}
+	return group, nil
+}
+
+func (o *groupOperator) UpdateGroup(workspace string, group *iamv1alpha2.Group) (*iamv1alpha2.Group, error) {
+	old, err := o.DescribeGroup(workspace, group.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := old.DeepCopy()
+	updated.Labels = group.Labels
+	if updated.Labels == nil {
+		updated.Labels = make(map[string]string)
+	}
+	// the group is not allowed to be moved to another workspace
+	updated.Labels[tenantv1alpha1.WorkspaceLabel] = workspace
+	updated.Annotations = group.Annotations
+	updated.Spec = group.Spec
+	if group.ResourceVersion != "" {
+		updated.ResourceVersion = group.ResourceVersion
+	}
+
+	if err := o.validateParent(workspace, updated); err != nil {
+		return nil, err
+	}
+
+	updated, err = o.horizonClient.IamV1alpha2().Groups().Update(context.Background(), updated, metav1.UpdateOptions{})
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (o *groupOperator) DeleteGroup(workspace, name string) error {
+	if _, err := o.DescribeGroup(workspace, name); err != nil {
+		return err
+	}
+
+	err := o.horizonClient.IamV1alpha2().Groups().Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (o *groupOperator) ListGroupMembers(workspace, group string, q *query.Query) (*api.ListResult, error) {
+	if _, err := o.DescribeGroup(workspace, group); err != nil {
+		return nil, err
+	}
+
+	groupBindings, err := o.groupBindingGetter.List("", listGroupBindingsOf(group))
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	members := make([]string, 0)
+	for _, item := range groupBindings.Items {
+		members = append(members, item.(*iamv1alpha2.GroupBinding).Users...)
+	}
+	q.Filters[query.FieldNames] = query.Value(strings.Join(members, ","))
+
+	result, err := o.userGetter.List("", q)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	for i, item := range result.Items {
+		user := item.(*iamv1alpha2.User).DeepCopy()
+		user.Spec.EncryptedPassword = ""
+		result.Items[i] = user
+	}
+	return result, nil
+}
+
+func (o *groupOperator) ListGroupBindings(workspace string, query *query.Query) (*api.ListResult, error) {
+	appendLabelSelector(query, tenantv1alpha1.WorkspaceLabel, workspace)
+
+	result, err := o.groupBindingGetter.List("", query)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (o *groupOperator) CreateGroupBinding(workspace string, groupBinding *iamv1alpha2.GroupBinding) (*iamv1alpha2.GroupBinding, error) {
+	group, err := o.DescribeGroup(workspace, groupBinding.GroupRef.Name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("group %s not found in workspace %s", groupBinding.GroupRef.Name, workspace))
+		}
+		return nil, err
+	}
+
+	if len(groupBinding.Users) == 0 {
+		return nil, apierrors.NewBadRequest("at least one user must be bound to the group")
+	}
+	for _, username := range groupBinding.Users {
+		if _, err := o.userGetter.Get("", username); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, apierrors.NewBadRequest(fmt.Sprintf("user %s not found", username))
+			}
+			klog.Error(err)
+			return nil, err
+		}
+	}
+
+	groupBinding = groupBinding.DeepCopy()
+	if groupBinding.Name == "" && groupBinding.GenerateName == "" {
+		groupBinding.GenerateName = fmt.Sprintf("%s-", group.Name)
+	}
+	if groupBinding.Labels == nil {
+		groupBinding.Labels = make(map[string]string)
+	}
+	groupBinding.Labels[tenantv1alpha1.WorkspaceLabel] = workspace
+	groupBinding.Labels[iamv1alpha2.GroupReferenceLabel] = group.Name
+	groupBinding.GroupRef.APIGroup = iamv1alpha2.SchemeGroupVersion.Group
+	groupBinding.GroupRef.Kind = iamv1alpha2.ResourceKindGroup
+
+	created, err := o.horizonClient.IamV1alpha2().GroupBindings().Create(context.Background(), groupBinding, metav1.CreateOptions{})
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	return created, nil
+}
+
+func (o *groupOperator) DeleteGroupBinding(workspace, name string) error {
+	obj, err := o.groupBindingGetter.Get("", name)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	if obj.(*iamv1alpha2.GroupBinding).Labels[tenantv1alpha1.WorkspaceLabel] != workspace {
+		return apierrors.NewNotFound(iamv1alpha2.Resource(iamv1alpha2.ResourcePluralGroupBinding), name)
+	}
+
+	err = o.horizonClient.IamV1alpha2().GroupBindings().Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	return nil
+}
+
+// validateSubject rejects the group name if it is the subject of any binding outside the workspace
+func (o *groupOperator) validateSubject(workspace, name string) error {
+	conflict := apierrors.NewConflict(iamv1alpha2.Resource(iamv1alpha2.ResourcePluralGroup), name,
+		fmt.Errorf("group %s is bound outside workspace %s", name, workspace))
+
+	globalRoleBindings, err := o.am.ListGlobalRoleBindings("", []string{name})
+	if err != nil {
+		return err
+	}
+	if len(globalRoleBindings) > 0 {
+		return conflict
+	}
+
+	clusterRoleBindings, err := o.am.ListClusterRoleBindings("", []string{name})
+	if err != nil {
+		return err
+	}
+	if len(clusterRoleBindings) > 0 {
+		return conflict
+	}
+
+	workspaceRoleBindings, err := o.am.ListWorkspaceRoleBindings("", []string{name}, "")
+	if err != nil {
+		return err
+	}
+	for _, workspaceRoleBinding := range workspaceRoleBindings {
+		if workspaceRoleBinding.Labels[tenantv1alpha1.WorkspaceLabel] != workspace {
+			return conflict
+		}
+	}
+
+	roleBindings, err := o.am.ListRoleBindings("", []string{name}, "")
+	if err != nil {
+		return err
+	}
+	for _, roleBinding := range roleBindings {
+		namespaceWorkspace, err := o.am.GetNamespaceControlledWorkspace(roleBinding.Namespace)
+		if err != nil {
+			return err
+		}
+		if namespaceWorkspace != workspace {
+			return conflict
+		}
+	}
+	return nil
+}
+
+// validateParent makes sure the parent group exists in the same workspace and no cycle is introduced
+func (o *groupOperator) validateParent(workspace string, group *iamv1alpha2.Group) error {
+	parent := group.Labels[iamv1alpha2.GroupParent]
+	for parent != "" {
+		if parent == group.Name {
+			return apierrors.NewBadRequest(fmt.Sprintf("group %s can not be a descendant of itself", group.Name))
+		}
+
+		obj, err := o.DescribeGroup(workspace, parent)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return apierrors.NewBadRequest(fmt.Sprintf("parent group %s not found in workspace %s", parent, workspace))
+			}
+			return err
+		}
+		parent = obj.Labels[iamv1alpha2.GroupParent]
+	}
+	return nil
+}
+
+func listGroupBindingsOf(group string) *query.Query {
+	q := query.New()
+	q.LabelSelector = labels.SelectorFromSet(labels.Set{iamv1alpha2.GroupReferenceLabel: group}).String()
+	return q
+}
+
+func appendLabelSelector(q *query.Query, key, value string) {
+	selector := labels.SelectorFromSet(labels.Set{key: value}).String()
+	if q.LabelSelector == "" {
+		q.LabelSelector = selector
+	} else {
+		q.LabelSelector = fmt.Sprintf("%s,%s", q.LabelSelector, selector)
+	}
+}
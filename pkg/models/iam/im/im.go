@@ -1,26 +1,216 @@
 package im
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
 	"github.com/sunweiwe/horizon/pkg/api"
 	"github.com/sunweiwe/horizon/pkg/apiserver/query"
-	"k8s.io/klog"
+	"github.com/sunweiwe/horizon/pkg/client/clientset"
+	"golang.org/x/crypto/bcrypt"
+	"k8s.io/klog/v2"
 
+	jsonpatch "github.com/evanphx/json-patch"
 	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
 	resources "github.com/sunweiwe/horizon/pkg/models/resources/v1alpha3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var (
+	ErrIncorrectPassword = errors.New("incorrect password")
 )
 
 type IdentityManagementInterface interface {
+	CreateUser(user *iamv1alpha2.User) (*iamv1alpha2.User, error)
 	ListUsers(query *query.Query) (*api.ListResult, error)
+	DescribeUser(username string) (*iamv1alpha2.User, error)
+	// UpdateUser replaces the spec of the user, the password and the status are left unchanged
+	UpdateUser(user *iamv1alpha2.User) (*iamv1alpha2.User, error)
+	// PatchUser applies a JSON merge patch to the user, the password can not be patched
+	PatchUser(username string, patch []byte) (*iamv1alpha2.User, error)
+	DeleteUser(username string) error
+	// UpdateUserState enables or disables the user
+	UpdateUserState(username string, state iamv1alpha2.UserState) (*iamv1alpha2.User, error)
+	// PasswordVerify checks the password against the bcrypt hash stored in the user spec
+	PasswordVerify(username string, password string) error
+	// ModifyPassword replaces the password of the user, it will be encrypted by the user controller
+	ModifyPassword(username string, password string) error
+	// ListLoginRecords lists the login history of the user
+	ListLoginRecords(username string, query *query.Query) (*api.ListResult, error)
+}
+
+// TokenRevoker revokes the tokens issued to the users, the sessions of the disabled users are ended through it
+type TokenRevoker interface {
+	RevokeAllUserTokens(username string) error
 }
 
 type imOperator struct {
-	userGetter resources.Interface
+	horizonClient     clientset.Interface
+	userGetter        resources.Interface
+	loginRecordGetter resources.Interface
+	tokenRevoker      TokenRevoker
 }
 
-func NewOperator(userGetter resources.Interface) IdentityManagementInterface {
+func NewOperator(horizonClient clientset.Interface, userGetter resources.Interface, loginRecordGetter resources.Interface, tokenRevoker TokenRevoker) IdentityManagementInterface {
 	return &imOperator{
-		userGetter: userGetter,
+		horizonClient:     horizonClient,
+		userGetter:        userGetter,
+		loginRecordGetter: loginRecordGetter,
+		tokenRevoker:      tokenRevoker,
+	}
+}
+
+func (im *imOperator) CreateUser(user *iamv1alpha2.User) (*iamv1alpha2.User, error) {
+	if err := im.validateEmail(user.Name, user.Spec.Email); err != nil {
+		return nil, err
+	}
+
+	user = user.DeepCopy()
+	// the state is initialized by the user controller
+	user.Status = iamv1alpha2.UserStatus{}
+
+	created, err := im.horizonClient.IamV1alpha2().Users().Create(context.Background(), user, metav1.CreateOptions{})
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	return ensurePasswordNotOutput(created), nil
+}
+
+func (im *imOperator) UpdateUser(user *iamv1alpha2.User) (*iamv1alpha2.User, error) {
+	old, err := im.horizonClient.IamV1alpha2().Users().Get(context.Background(), user.Name, metav1.GetOptions{})
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	if err := im.validateEmail(user.Name, user.Spec.Email); err != nil {
+		return nil, err
+	}
+
+	updated := old.DeepCopy()
+	updated.Labels = user.Labels
+	updated.Annotations = user.Annotations
+	updated.Spec = user.Spec
+	// the password is only allowed to be modified with ModifyPassword
+	updated.Spec.EncryptedPassword = old.Spec.EncryptedPassword
+	if user.ResourceVersion != "" {
+		updated.ResourceVersion = user.ResourceVersion
+	}
+
+	updated, err = im.horizonClient.IamV1alpha2().Users().Update(context.Background(), updated, metav1.UpdateOptions{})
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	return ensurePasswordNotOutput(updated), nil
+}
+
+func (im *imOperator) PatchUser(username string, patch []byte) (*iamv1alpha2.User, error) {
+	old, err := im.horizonClient.IamV1alpha2().Users().Get(context.Background(), username, metav1.GetOptions{})
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	// apply the patch locally to validate the result before sending it
+	original, err := json.Marshal(old)
+	if err != nil {
+		return nil, err
+	}
+	patched, err := jsonpatch.MergePatch(original, patch)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	user := &iamv1alpha2.User{}
+	if err := json.Unmarshal(patched, user); err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+
+	if user.Name != old.Name {
+		return nil, apierrors.NewBadRequest("the name of the user can not be changed")
+	}
+	if user.Spec.EncryptedPassword != old.Spec.EncryptedPassword {
+		return nil, apierrors.NewBadRequest("the password can not be modified by patch")
+	}
+	if user.Spec.Email != old.Spec.Email {
+		if err := im.validateEmail(username, user.Spec.Email); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := im.horizonClient.IamV1alpha2().Users().Patch(context.Background(), username, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	return ensurePasswordNotOutput(updated), nil
+}
+
+func (im *imOperator) DeleteUser(username string) error {
+	err := im.horizonClient.IamV1alpha2().Users().Delete(context.Background(), username, metav1.DeleteOptions{})
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (im *imOperator) UpdateUserState(username string, state iamv1alpha2.UserState) (*iamv1alpha2.User, error) {
+	if state != iamv1alpha2.UserActive && state != iamv1alpha2.UserDisabled {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid user state %q, must be one of %s and %s", state, iamv1alpha2.UserActive, iamv1alpha2.UserDisabled))
 	}
+
+	user, err := im.horizonClient.IamV1alpha2().Users().Get(context.Background(), username, metav1.GetOptions{})
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	// enabling the user confirms the user created by identity provider
+	if state == iamv1alpha2.UserActive && user.Annotations[iamv1alpha2.PendingConfirmationAnnotation] != "" {
+		user = user.DeepCopy()
+		delete(user.Annotations, iamv1alpha2.PendingConfirmationAnnotation)
+		user, err = im.horizonClient.IamV1alpha2().Users().Update(context.Background(), user, metav1.UpdateOptions{})
+		if err != nil {
+			klog.Error(err)
+			return nil, err
+		}
+	}
+
+	if user.Status.State != state {
+		now := metav1.Now()
+		user = user.DeepCopy()
+		user.Status.State = state
+		user.Status.Reason = ""
+		user.Status.LastTransitionTime = &now
+
+		user, err = im.horizonClient.IamV1alpha2().Users().UpdateStatus(context.Background(), user, metav1.UpdateOptions{})
+		if err != nil {
+			klog.Error(err)
+			return nil, err
+		}
+	}
+
+	// the sessions of the disabled user are ended, the tokens are revoked again in case a previous attempt failed
+	if state == iamv1alpha2.UserDisabled {
+		if err := im.tokenRevoker.RevokeAllUserTokens(username); err != nil {
+			klog.Error(err)
+			return nil, err
+		}
+	}
+
+	return ensurePasswordNotOutput(user), nil
 }
 
 func (im *imOperator) ListUsers(query *query.Query) (ret *api.ListResult, err error) {
@@ -41,6 +231,106 @@ func (im *imOperator) ListUsers(query *query.Query) (ret *api.ListResult, err er
 	return ret, nil
 }
 
+func (im *imOperator) DescribeUser(username string) (*iamv1alpha2.User, error) {
+	obj, err := im.userGetter.Get("", username)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	user := obj.(*iamv1alpha2.User)
+	return ensurePasswordNotOutput(user), nil
+}
+
+func (im *imOperator) PasswordVerify(username string, password string) error {
+	// empty password is not allowed
+	if password == "" {
+		return ErrIncorrectPassword
+	}
+
+	obj, err := im.userGetter.Get("", username)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ErrIncorrectPassword
+		}
+		klog.Error(err)
+		return err
+	}
+
+	user := obj.(*iamv1alpha2.User)
+	if user.Spec.EncryptedPassword == "" {
+		return ErrIncorrectPassword
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Spec.EncryptedPassword), []byte(password)); err != nil {
+		return ErrIncorrectPassword
+	}
+
+	return nil
+}
+
+func (im *imOperator) ModifyPassword(username string, password string) error {
+	user, err := im.horizonClient.IamV1alpha2().Users().Get(context.Background(), username, metav1.GetOptions{})
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	user = user.DeepCopy()
+	user.Spec.EncryptedPassword = password
+
+	_, err = im.horizonClient.IamV1alpha2().Users().Update(context.Background(), user, metav1.UpdateOptions{})
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+func (im *imOperator) ListLoginRecords(username string, query *query.Query) (*api.ListResult, error) {
+	userSelector := labels.SelectorFromSet(labels.Set{iamv1alpha2.UserReferenceLabel: username}).String()
+	if query.LabelSelector == "" {
+		query.LabelSelector = userSelector
+	} else {
+		query.LabelSelector = fmt.Sprintf("%s,%s", query.LabelSelector, userSelector)
+	}
+
+	result, err := im.loginRecordGetter.List("", query)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// validateEmail checks the format of the email and that it's not used by users other than username
+func (im *imOperator) validateEmail(username, email string) error {
+	if email == "" {
+		return apierrors.NewBadRequest("email must not be empty")
+	}
+
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return apierrors.NewBadRequest(fmt.Sprintf("invalid email address %q", email))
+	}
+
+	result, err := im.userGetter.List("", query.New())
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	for _, item := range result.Items {
+		user := item.(*iamv1alpha2.User)
+		if user.Name != username && strings.EqualFold(user.Spec.Email, email) {
+			return apierrors.NewConflict(iamv1alpha2.Resource(iamv1alpha2.ResourcePluralUser), username, fmt.Errorf("email %s is already used by another user", email))
+		}
+	}
+
+	return nil
+}
+
 func ensurePasswordNotOutput(user *iamv1alpha2.User) *iamv1alpha2.User {
 	out := user.DeepCopy()
 	out.Spec.EncryptedPassword = ""
@@ -0,0 +1,102 @@
+package kubeconfig
+
+import (
+	"fmt"
+
+	"github.com/sunweiwe/horizon/pkg/constants"
+	"k8s.io/client-go/tools/clientcmd"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	// FileName is the key of the kubeconfig in the data of the Secret
+	FileName = "config"
+	// SecretTypeKubeConfig is the type of the Secrets which hold the kubeconfig of the users
+	SecretTypeKubeConfig corev1.SecretType = "horizon.io/kubeconfig"
+	// TokenExpiresAtAnnotation records when the token in the kubeconfig expires, in RFC 3339
+	TokenExpiresAtAnnotation = "iam.horizon.io/token-expires-at"
+	// UserReferenceLabel labels the Secrets of the kubeconfig with the user, it differs from the label of
+	// the Secrets of the token store so that the changes of the tokens do not trigger the kubeconfig controller
+	UserReferenceLabel = "kubeconfig.horizon.io/user-ref"
+	// DefaultServer is the in-cluster address of hz-apiserver, which proxies the requests to the
+	// Kubernetes apiserver after authorizing them. It is only reachable inside the cluster network,
+	// the address served over https should be configured when the kubeconfig is used elsewhere.
+	DefaultServer = "http://hz-apiserver.horizon-system.svc"
+
+	secretNameFormat   = "kubeconfig-%s"
+	defaultClusterName = "local"
+	defaultNamespace   = "default"
+)
+
+// Interface retrieves the kubeconfig generated for the users by the kubeconfig controller.
+type Interface interface {
+	GetKubeConfig(username string) (string, error)
+}
+
+type operator struct {
+	secretLister corev1listers.SecretLister
+}
+
+func NewReadOnlyOperator(secretLister corev1listers.SecretLister) Interface {
+	return &operator{secretLister: secretLister}
+}
+
+// GetKubeConfig returns the kubeconfig of the user, a NotFound error is returned
+// if the kubeconfig has not been generated yet.
+func (o *operator) GetKubeConfig(username string) (string, error) {
+	secret, err := o.secretLister.Secrets(constants.HorizonNamespace).Get(SecretName(username))
+	if err != nil {
+		return "", err
+	}
+
+	data, ok := secret.Data[FileName]
+	if !ok {
+		return "", fmt.Errorf("kubeconfig of user %s is invalid", username)
+	}
+	return string(data), nil
+}
+
+// SecretName returns the name of the Secret which holds the kubeconfig of the user.
+func SecretName(username string) string {
+	return fmt.Sprintf(secretNameFormat, username)
+}
+
+// New returns a kubeconfig which authenticates the user to the server with the token.
+func New(server string, username string, token string) ([]byte, error) {
+	contextName := fmt.Sprintf("%s@%s", username, defaultClusterName)
+
+	config := clientcmdapi.NewConfig()
+	config.Clusters[defaultClusterName] = &clientcmdapi.Cluster{Server: server}
+	config.AuthInfos[username] = &clientcmdapi.AuthInfo{Token: token}
+	config.Contexts[contextName] = &clientcmdapi.Context{
+		Cluster:   defaultClusterName,
+		AuthInfo:  username,
+		Namespace: defaultNamespace,
+	}
+	config.CurrentContext = contextName
+
+	return clientcmd.Write(*config)
+}
+
+// Parse returns the server and token of the current context of the kubeconfig.
+func Parse(data []byte) (server string, token string, err error) {
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return "", "", err
+	}
+
+	context, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return "", "", fmt.Errorf("current context %s not found", config.CurrentContext)
+	}
+	if cluster, ok := config.Clusters[context.Cluster]; ok {
+		server = cluster.Server
+	}
+	if authInfo, ok := config.AuthInfos[context.AuthInfo]; ok {
+		token = authInfo.Token
+	}
+	return server, token, nil
+}
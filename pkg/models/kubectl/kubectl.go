@@ -0,0 +1,163 @@
+package kubectl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sunweiwe/horizon/pkg/constants"
+	"github.com/sunweiwe/horizon/pkg/models/kubeconfig"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	iamv1alpha2listers "github.com/sunweiwe/horizon/pkg/client/listers/iam/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+)
+
+const (
+	// ContainerName is the name of the container in the kubectl pods
+	ContainerName = "kubectl"
+
+	podNameFormat       = "kubectl-%s"
+	kubeconfigVolume    = "kubeconfig"
+	kubeconfigMountPath = "/root/.kube"
+	appLabel            = "app"
+)
+
+// the kubectl pods only run the shells of the users, the resources are limited since every user has one
+var kubectlResources = corev1.ResourceRequirements{
+	Requests: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("10m"),
+		corev1.ResourceMemory: resource.MustParse("32Mi"),
+	},
+	Limits: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("200m"),
+		corev1.ResourceMemory: resource.MustParse("256Mi"),
+	},
+}
+
+// PodInfo locates the container of the kubectl pod
+type PodInfo struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Phase     string `json:"phase,omitempty"`
+}
+
+// Interface manages the kubectl pods of the users, the kubeconfig of the user is mounted into the pod,
+// so that the kubectl commands are authorized by hz-apiserver with the permissions of the user.
+type Interface interface {
+	// GetKubectlPod returns the kubectl pod of the user, the pod is created if it does not exist yet
+	GetKubectlPod(username string) (*PodInfo, error)
+}
+
+type operator struct {
+	k8sClient  kubernetes.Interface
+	podLister  corev1listers.PodLister
+	userLister iamv1alpha2listers.UserLister
+	image      string
+}
+
+func NewOperator(k8sClient kubernetes.Interface, podLister corev1listers.PodLister, userLister iamv1alpha2listers.UserLister, image string) Interface {
+	return &operator{
+		k8sClient:  k8sClient,
+		podLister:  podLister,
+		userLister: userLister,
+		image:      image,
+	}
+}
+
+func (o *operator) GetKubectlPod(username string) (*PodInfo, error) {
+	pod, err := o.podLister.Pods(constants.HorizonNamespace).Get(podName(username))
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.Error(err)
+			return nil, err
+		}
+		if pod, err = o.createKubectlPod(username); err != nil {
+			return nil, err
+		}
+	}
+
+	return &PodInfo{
+		Namespace: pod.Namespace,
+		Pod:       pod.Name,
+		Container: ContainerName,
+		Phase:     string(pod.Status.Phase),
+	}, nil
+}
+
+func (o *operator) createKubectlPod(username string) (*corev1.Pod, error) {
+	if o.image == "" {
+		return nil, apierrors.NewServiceUnavailable("kubectl image is not configured")
+	}
+
+	user, err := o.userLister.Get(username)
+	if err != nil {
+		return nil, err
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      podName(username),
+			Namespace: constants.HorizonNamespace,
+			Labels: map[string]string{
+				appLabel:                       ContainerName,
+				iamv1alpha2.UserReferenceLabel: username,
+			},
+			// the pod is garbage collected along with the user
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(user, iamv1alpha2.SchemeGroupVersion.WithKind(iamv1alpha2.ResourceKindUser)),
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  ContainerName,
+					Image: o.image,
+					// the container is kept running for the shells executed in it, whatever the entrypoint of the image
+					Command:   []string{"sleep", "infinity"},
+					Resources: kubectlResources,
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							// mounted without sub path, so that the renewed kubeconfig is synced into the pod
+							Name:      kubeconfigVolume,
+							MountPath: kubeconfigMountPath,
+							ReadOnly:  true,
+						},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name: kubeconfigVolume,
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: kubeconfig.SecretName(username),
+							Items:      []corev1.KeyToPath{{Key: kubeconfig.FileName, Path: kubeconfig.FileName}},
+						},
+					},
+				},
+			},
+			AutomountServiceAccountToken: new(bool),
+		},
+	}
+
+	created, err := o.k8sClient.CoreV1().Pods(constants.HorizonNamespace).Create(context.Background(), pod, metav1.CreateOptions{})
+	if err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return o.k8sClient.CoreV1().Pods(constants.HorizonNamespace).Get(context.Background(), pod.Name, metav1.GetOptions{})
+		}
+		klog.Error(err)
+		return nil, err
+	}
+	return created, nil
+}
+
+func podName(username string) string {
+	return fmt.Sprintf(podNameFormat, username)
+}
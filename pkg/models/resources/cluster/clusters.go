@@ -0,0 +1,60 @@
+package cluster
+
+import (
+	"github.com/sunweiwe/horizon/pkg/api"
+	"github.com/sunweiwe/horizon/pkg/apiserver/query"
+	"github.com/sunweiwe/horizon/pkg/client/informers/externalversions"
+	"github.com/sunweiwe/horizon/pkg/models/resources/v1alpha3"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterv1alpha1 "github.com/sunweiwe/api/cluster/v1alpha1"
+)
+
+type clustersGetter struct {
+	horizonInformers externalversions.SharedInformerFactory
+}
+
+func New(horizon externalversions.SharedInformerFactory) v1alpha3.Interface {
+	return &clustersGetter{horizonInformers: horizon}
+}
+
+func (c *clustersGetter) Get(_, name string) (runtime.Object, error) {
+	return c.horizonInformers.Cluster().V1alpha1().Clusters().Lister().Get(name)
+}
+
+func (c *clustersGetter) List(_ string, query *query.Query) (*api.ListResult, error) {
+	clusters, err := c.horizonInformers.Cluster().V1alpha1().Clusters().Lister().List(query.Selector())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []runtime.Object
+	for _, cluster := range clusters {
+		result = append(result, cluster)
+	}
+
+	return v1alpha3.DefaultList(result, query, c.compare, c.filter), nil
+}
+
+func (c *clustersGetter) compare(left runtime.Object, right runtime.Object, field query.Field) bool {
+	leftCluster, ok := left.(*clusterv1alpha1.Cluster)
+	if !ok {
+		return false
+	}
+
+	rightCluster, ok := right.(*clusterv1alpha1.Cluster)
+	if !ok {
+		return false
+	}
+
+	return v1alpha3.DefaultObjectMetaCompare(leftCluster.ObjectMeta, rightCluster.ObjectMeta, field)
+}
+
+func (c *clustersGetter) filter(object runtime.Object, filter query.Filter) bool {
+	cluster, ok := object.(*clusterv1alpha1.Cluster)
+	if !ok {
+		return false
+	}
+
+	return v1alpha3.DefaultObjectMetaFilter(cluster.ObjectMeta, filter)
+}
@@ -0,0 +1,60 @@
+package globalrole
+
+import (
+	"github.com/sunweiwe/horizon/pkg/api"
+	"github.com/sunweiwe/horizon/pkg/apiserver/query"
+	"github.com/sunweiwe/horizon/pkg/client/informers/externalversions"
+	"github.com/sunweiwe/horizon/pkg/models/resources/v1alpha3"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+)
+
+type globalRolesGetter struct {
+	horizonInformers externalversions.SharedInformerFactory
+}
+
+func New(horizon externalversions.SharedInformerFactory) v1alpha3.Interface {
+	return &globalRolesGetter{horizonInformers: horizon}
+}
+
+func (g *globalRolesGetter) Get(_, name string) (runtime.Object, error) {
+	return g.horizonInformers.Iam().V1alpha2().GlobalRoles().Lister().Get(name)
+}
+
+func (g *globalRolesGetter) List(_ string, query *query.Query) (*api.ListResult, error) {
+	roles, err := g.horizonInformers.Iam().V1alpha2().GlobalRoles().Lister().List(query.Selector())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []runtime.Object
+	for _, role := range roles {
+		result = append(result, role)
+	}
+
+	return v1alpha3.DefaultList(result, query, g.compare, g.filter), nil
+}
+
+func (g *globalRolesGetter) compare(left runtime.Object, right runtime.Object, field query.Field) bool {
+	leftRole, ok := left.(*iamv1alpha2.GlobalRole)
+	if !ok {
+		return false
+	}
+
+	rightRole, ok := right.(*iamv1alpha2.GlobalRole)
+	if !ok {
+		return false
+	}
+
+	return v1alpha3.DefaultObjectMetaCompare(leftRole.ObjectMeta, rightRole.ObjectMeta, field)
+}
+
+func (g *globalRolesGetter) filter(object runtime.Object, filter query.Filter) bool {
+	role, ok := object.(*iamv1alpha2.GlobalRole)
+	if !ok {
+		return false
+	}
+
+	return v1alpha3.DefaultObjectMetaFilter(role.ObjectMeta, filter)
+}
@@ -0,0 +1,75 @@
+package group
+
+import (
+	"strings"
+
+	"github.com/sunweiwe/horizon/pkg/api"
+	"github.com/sunweiwe/horizon/pkg/apiserver/query"
+	"github.com/sunweiwe/horizon/pkg/client/informers/externalversions"
+	"github.com/sunweiwe/horizon/pkg/models/resources/v1alpha3"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+)
+
+const (
+	fieldDisplayName = "displayName"
+	fieldParent      = "parent"
+)
+
+type groupsGetter struct {
+	horizonInformers externalversions.SharedInformerFactory
+}
+
+func New(horizon externalversions.SharedInformerFactory) v1alpha3.Interface {
+	return &groupsGetter{horizonInformers: horizon}
+}
+
+func (g *groupsGetter) Get(_, name string) (runtime.Object, error) {
+	return g.horizonInformers.Iam().V1alpha2().Groups().Lister().Get(name)
+}
+
+func (g *groupsGetter) List(_ string, query *query.Query) (*api.ListResult, error) {
+	groups, err := g.horizonInformers.Iam().V1alpha2().Groups().Lister().List(query.Selector())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []runtime.Object
+	for _, group := range groups {
+		result = append(result, group)
+	}
+
+	return v1alpha3.DefaultList(result, query, g.compare, g.filter), nil
+}
+
+func (g *groupsGetter) compare(left runtime.Object, right runtime.Object, field query.Field) bool {
+	leftGroup, ok := left.(*iamv1alpha2.Group)
+	if !ok {
+		return false
+	}
+
+	rightGroup, ok := right.(*iamv1alpha2.Group)
+	if !ok {
+		return false
+	}
+
+	return v1alpha3.DefaultObjectMetaCompare(leftGroup.ObjectMeta, rightGroup.ObjectMeta, field)
+}
+
+func (g *groupsGetter) filter(object runtime.Object, filter query.Filter) bool {
+	group, ok := object.(*iamv1alpha2.Group)
+	if !ok {
+		return false
+	}
+
+	switch filter.Field {
+	case fieldDisplayName:
+		return strings.Contains(group.Spec.DisplayName, string(filter.Value))
+	// ?parent=, the top level groups have no parent
+	case fieldParent:
+		return group.Labels[iamv1alpha2.GroupParent] == string(filter.Value)
+	default:
+		return v1alpha3.DefaultObjectMetaFilter(group.ObjectMeta, filter)
+	}
+}
@@ -0,0 +1,73 @@
+package groupbinding
+
+import (
+	"github.com/sunweiwe/horizon/pkg/api"
+	"github.com/sunweiwe/horizon/pkg/apiserver/query"
+	"github.com/sunweiwe/horizon/pkg/client/informers/externalversions"
+	"github.com/sunweiwe/horizon/pkg/models/resources/v1alpha3"
+	"github.com/sunweiwe/horizon/pkg/utils/slice"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+)
+
+const (
+	fieldGroup = "group"
+	fieldUser  = "user"
+)
+
+type groupBindingsGetter struct {
+	horizonInformers externalversions.SharedInformerFactory
+}
+
+func New(horizon externalversions.SharedInformerFactory) v1alpha3.Interface {
+	return &groupBindingsGetter{horizonInformers: horizon}
+}
+
+func (g *groupBindingsGetter) Get(_, name string) (runtime.Object, error) {
+	return g.horizonInformers.Iam().V1alpha2().GroupBindings().Lister().Get(name)
+}
+
+func (g *groupBindingsGetter) List(_ string, query *query.Query) (*api.ListResult, error) {
+	groupBindings, err := g.horizonInformers.Iam().V1alpha2().GroupBindings().Lister().List(query.Selector())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []runtime.Object
+	for _, groupBinding := range groupBindings {
+		result = append(result, groupBinding)
+	}
+
+	return v1alpha3.DefaultList(result, query, g.compare, g.filter), nil
+}
+
+func (g *groupBindingsGetter) compare(left runtime.Object, right runtime.Object, field query.Field) bool {
+	leftGroupBinding, ok := left.(*iamv1alpha2.GroupBinding)
+	if !ok {
+		return false
+	}
+
+	rightGroupBinding, ok := right.(*iamv1alpha2.GroupBinding)
+	if !ok {
+		return false
+	}
+
+	return v1alpha3.DefaultObjectMetaCompare(leftGroupBinding.ObjectMeta, rightGroupBinding.ObjectMeta, field)
+}
+
+func (g *groupBindingsGetter) filter(object runtime.Object, filter query.Filter) bool {
+	groupBinding, ok := object.(*iamv1alpha2.GroupBinding)
+	if !ok {
+		return false
+	}
+
+	switch filter.Field {
+	case fieldGroup:
+		return groupBinding.GroupRef.Name == string(filter.Value)
+	case fieldUser:
+		return slice.HasString(groupBinding.Users, string(filter.Value))
+	default:
+		return v1alpha3.DefaultObjectMetaFilter(groupBinding.ObjectMeta, filter)
+	}
+}
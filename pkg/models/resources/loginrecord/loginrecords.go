@@ -0,0 +1,75 @@
+package loginrecord
+
+import (
+	"strconv"
+
+	"github.com/sunweiwe/horizon/pkg/api"
+	"github.com/sunweiwe/horizon/pkg/apiserver/query"
+	"github.com/sunweiwe/horizon/pkg/client/informers/externalversions"
+	"github.com/sunweiwe/horizon/pkg/models/resources/v1alpha3"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+)
+
+const (
+	fieldType    = "type"
+	fieldSuccess = "success"
+)
+
+type loginRecordsGetter struct {
+	horizonInformers externalversions.SharedInformerFactory
+}
+
+func New(horizon externalversions.SharedInformerFactory) v1alpha3.Interface {
+	return &loginRecordsGetter{horizonInformers: horizon}
+}
+
+func (l *loginRecordsGetter) Get(_, name string) (runtime.Object, error) {
+	return l.horizonInformers.Iam().V1alpha2().LoginRecords().Lister().Get(name)
+}
+
+func (l *loginRecordsGetter) List(_ string, query *query.Query) (*api.ListResult, error) {
+	records, err := l.horizonInformers.Iam().V1alpha2().LoginRecords().Lister().List(query.Selector())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []runtime.Object
+	for _, record := range records {
+		result = append(result, record)
+	}
+
+	return v1alpha3.DefaultList(result, query, l.compare, l.filter), nil
+}
+
+func (l *loginRecordsGetter) compare(left runtime.Object, right runtime.Object, field query.Field) bool {
+	leftRecord, ok := left.(*iamv1alpha2.LoginRecord)
+	if !ok {
+		return false
+	}
+
+	rightRecord, ok := right.(*iamv1alpha2.LoginRecord)
+	if !ok {
+		return false
+	}
+
+	return v1alpha3.DefaultObjectMetaCompare(leftRecord.ObjectMeta, rightRecord.ObjectMeta, field)
+}
+
+func (l *loginRecordsGetter) filter(object runtime.Object, filter query.Filter) bool {
+	record, ok := object.(*iamv1alpha2.LoginRecord)
+	if !ok {
+		return false
+	}
+
+	switch filter.Field {
+	case fieldType:
+		return string(record.Spec.Type) == string(filter.Value)
+	case fieldSuccess:
+		success, _ := strconv.ParseBool(string(filter.Value))
+		return success == record.Spec.Success
+	default:
+		return v1alpha3.DefaultObjectMetaFilter(record.ObjectMeta, filter)
+	}
+}
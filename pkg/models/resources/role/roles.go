@@ -0,0 +1,60 @@
+package role
+
+import (
+	"github.com/sunweiwe/horizon/pkg/api"
+	"github.com/sunweiwe/horizon/pkg/apiserver/query"
+	"github.com/sunweiwe/horizon/pkg/models/resources/v1alpha3"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+type rolesGetter struct {
+	kubeInformers informers.SharedInformerFactory
+}
+
+func New(kube informers.SharedInformerFactory) v1alpha3.Interface {
+	return &rolesGetter{kubeInformers: kube}
+}
+
+func (g *rolesGetter) Get(namespace, name string) (runtime.Object, error) {
+	return g.kubeInformers.Rbac().V1().Roles().Lister().Roles(namespace).Get(name)
+}
+
+func (g *rolesGetter) List(namespace string, query *query.Query) (*api.ListResult, error) {
+	roles, err := g.kubeInformers.Rbac().V1().Roles().Lister().Roles(namespace).List(query.Selector())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []runtime.Object
+	for _, role := range roles {
+		result = append(result, role)
+	}
+
+	return v1alpha3.DefaultList(result, query, g.compare, g.filter), nil
+}
+
+func (g *rolesGetter) compare(left runtime.Object, right runtime.Object, field query.Field) bool {
+	leftRole, ok := left.(*rbacv1.Role)
+	if !ok {
+		return false
+	}
+
+	rightRole, ok := right.(*rbacv1.Role)
+	if !ok {
+		return false
+	}
+
+	return v1alpha3.DefaultObjectMetaCompare(leftRole.ObjectMeta, rightRole.ObjectMeta, field)
+}
+
+func (g *rolesGetter) filter(object runtime.Object, filter query.Filter) bool {
+	role, ok := object.(*rbacv1.Role)
+	if !ok {
+		return false
+	}
+
+	return v1alpha3.DefaultObjectMetaFilter(role.ObjectMeta, filter)
+}
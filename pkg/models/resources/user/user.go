@@ -1,12 +1,25 @@
 package user
 
 import (
+	"strings"
+
 	"github.com/sunweiwe/horizon/pkg/api"
 	"github.com/sunweiwe/horizon/pkg/apiserver/query"
 	"github.com/sunweiwe/horizon/pkg/client/informers/externalversions"
 	"github.com/sunweiwe/horizon/pkg/models/resources/v1alpha3"
+	"github.com/sunweiwe/horizon/pkg/utils/slice"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/informers"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+)
+
+const (
+	fieldEmail         = "email"
+	fieldDisplayName   = "displayName"
+	fieldState         = "state"
+	fieldGroup         = "group"
+	fieldLastLoginTime = "lastLoginTime"
 )
 
 type usersGetter struct {
@@ -22,8 +35,68 @@ func (u *usersGetter) Get(_, name string) (runtime.Object, error) {
 	return u.horizonInformers.Iam().V1alpha2().Users().Lister().Get(name)
 }
 
-// TODO
 func (u *usersGetter) List(_ string, query *query.Query) (*api.ListResult, error) {
+	users, err := u.horizonInformers.Iam().V1alpha2().Users().Lister().List(query.Selector())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []runtime.Object
+	for _, user := range users {
+		result = append(result, user)
+	}
+
+	return v1alpha3.DefaultList(result, query, u.compare, u.filter), nil
+}
+
+func (u *usersGetter) compare(left runtime.Object, right runtime.Object, field query.Field) bool {
+	leftUser, ok := left.(*iamv1alpha2.User)
+	if !ok {
+		return false
+	}
+
+	rightUser, ok := right.(*iamv1alpha2.User)
+	if !ok {
+		return false
+	}
+
+	switch field {
+	// ?sortBy=lastLoginTime, the users never logged in come last
+	case fieldLastLoginTime:
+		if leftUser.Status.LastLoginTime == nil && rightUser.Status.LastLoginTime == nil {
+			return strings.Compare(leftUser.Name, rightUser.Name) > 0
+		}
+		if leftUser.Status.LastLoginTime == nil {
+			return false
+		}
+		if rightUser.Status.LastLoginTime == nil {
+			return true
+		}
+		if leftUser.Status.LastLoginTime.Equal(rightUser.Status.LastLoginTime) {
+			return strings.Compare(leftUser.Name, rightUser.Name) > 0
+		}
+		return leftUser.Status.LastLoginTime.After(rightUser.Status.LastLoginTime.Time)
+	default:
+		return v1alpha3.DefaultObjectMetaCompare(leftUser.ObjectMeta, rightUser.ObjectMeta, field)
+	}
+}
+
+func (u *usersGetter) filter(object runtime.Object, filter query.Filter) bool {
+	user, ok := object.(*iamv1alpha2.User)
+	if !ok {
+		return false
+	}
 
-	return &api.ListResult{}, nil
+	switch filter.Field {
+	case fieldEmail:
+		return strings.Contains(user.Spec.Email, string(filter.Value))
+	case fieldDisplayName:
+		return strings.Contains(user.Spec.DisplayName, string(filter.Value))
+	case fieldState, query.FieldStatus:
+		return string(user.Status.State) == string(filter.Value)
+	case fieldGroup:
+		return slice.HasString(user.Spec.Groups, string(filter.Value))
+	default:
+		return v1alpha3.DefaultObjectMetaFilter(user.ObjectMeta, filter)
+	}
 }
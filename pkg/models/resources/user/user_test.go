@@ -0,0 +1,138 @@
+package user
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sunweiwe/horizon/pkg/apiserver/query"
+	"github.com/sunweiwe/horizon/pkg/client/informers/externalversions"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	fakeclientset "github.com/sunweiwe/horizon/pkg/client/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newUser(name string, created time.Time, lastLogin *time.Time, spec iamv1alpha2.UserSpec, state iamv1alpha2.UserState) *iamv1alpha2.User {
+	user := &iamv1alpha2.User{
+		ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+		Spec:       spec,
+		Status:     iamv1alpha2.UserStatus{State: state},
+	}
+	if lastLogin != nil {
+		t := metav1.NewTime(*lastLogin)
+		user.Status.LastLoginTime = &t
+	}
+	return user
+}
+
+func TestList(t *testing.T) {
+	now := time.Now()
+	hourAgo := now.Add(-time.Hour)
+	objects := []runtime.Object{
+		newUser("alice", now.Add(-3*time.Hour), &hourAgo,
+			iamv1alpha2.UserSpec{Email: "alice@example.com", DisplayName: "Alice", Groups: []string{"devs"}}, iamv1alpha2.UserActive),
+		newUser("bob", now.Add(-2*time.Hour), &now,
+			iamv1alpha2.UserSpec{Email: "bob@example.org", DisplayName: "Bob", Groups: []string{"devs", "ops"}}, iamv1alpha2.UserActive),
+		newUser("carol", now.Add(-time.Hour), nil,
+			iamv1alpha2.UserSpec{Email: "carol@example.com", DisplayName: "Carol"}, iamv1alpha2.UserDisabled),
+		newUser("dave", now.Add(-time.Hour), nil,
+			iamv1alpha2.UserSpec{Email: "dave@example.org", DisplayName: "Dave"}, iamv1alpha2.UserActive),
+	}
+
+	tests := []struct {
+		name      string
+		query     *query.Query
+		wantTotal int
+		wantNames []string
+	}{
+		{
+			name:      "newest first by default",
+			query:     query.New(),
+			wantTotal: 4,
+			wantNames: []string{"dave", "carol", "bob", "alice"},
+		},
+		{
+			name:      "last login, the users never logged in come last",
+			query:     &query.Query{SortBy: fieldLastLoginTime},
+			wantTotal: 4,
+			wantNames: []string{"bob", "alice", "dave", "carol"},
+		},
+		{
+			name:      "last login ascending",
+			query:     &query.Query{SortBy: fieldLastLoginTime, Ascending: true},
+			wantTotal: 4,
+			wantNames: []string{"carol", "dave", "alice", "bob"},
+		},
+		{
+			name:      "email",
+			query:     &query.Query{SortBy: query.FieldName, Ascending: true, Filters: map[query.Field]query.Value{fieldEmail: "example.com"}},
+			wantTotal: 2,
+			wantNames: []string{"alice", "carol"},
+		},
+		{
+			name:      "display name",
+			query:     &query.Query{Filters: map[query.Field]query.Value{fieldDisplayName: "Bo"}},
+			wantTotal: 1,
+			wantNames: []string{"bob"},
+		},
+		{
+			name:      "state",
+			query:     &query.Query{Filters: map[query.Field]query.Value{fieldState: query.Value(iamv1alpha2.UserDisabled)}},
+			wantTotal: 1,
+			wantNames: []string{"carol"},
+		},
+		{
+			name:      "group",
+			query:     &query.Query{SortBy: query.FieldName, Ascending: true, Filters: map[query.Field]query.Value{fieldGroup: "devs"}},
+			wantTotal: 2,
+			wantNames: []string{"alice", "bob"},
+		},
+		{
+			name:      "name",
+			query:     &query.Query{Filters: map[query.Field]query.Value{query.FieldName: "ar"}},
+			wantTotal: 1,
+			wantNames: []string{"carol"},
+		},
+		{
+			name:      "paginated",
+			query:     &query.Query{SortBy: query.FieldName, Ascending: true, Pagination: &query.Pagination{Limit: 2, Offset: 2}},
+			wantTotal: 4,
+			wantNames: []string{"carol", "dave"},
+		},
+	}
+
+	kubeInformerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	horizonInformerFactory := externalversions.NewSharedInformerFactory(fakeclientset.NewSimpleClientset(objects...), 0)
+	getter := New(kubeInformerFactory, horizonInformerFactory)
+
+	horizonInformerFactory.Iam().V1alpha2().Users().Informer()
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	horizonInformerFactory.Start(stopCh)
+	horizonInformerFactory.WaitForCacheSync(stopCh)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := getter.List("", test.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.TotalItems != test.wantTotal {
+				t.Errorf("expected %d users in total, got %d", test.wantTotal, result.TotalItems)
+			}
+
+			names := make([]string, 0, len(result.Items))
+			for _, item := range result.Items {
+				names = append(names, item.(*iamv1alpha2.User).Name)
+			}
+			if !reflect.DeepEqual(names, test.wantNames) {
+				t.Errorf("expected users %v, got %v", test.wantNames, names)
+			}
+		})
+	}
+}
@@ -1,9 +1,15 @@
 package v1alpha3
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/sunweiwe/horizon/pkg/api"
 	"github.com/sunweiwe/horizon/pkg/apiserver/query"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type Interface interface {
@@ -11,3 +17,150 @@ type Interface interface {
 
 	List(namespace string, query *query.Query) (*api.ListResult, error)
 }
+
+// CompareFunc return true is left great than right
+type CompareFunc func(runtime.Object, runtime.Object, query.Field) bool
+
+type FilterFunc func(runtime.Object, query.Filter) bool
+
+type TransformFunc func(runtime.Object) runtime.Object
+
+// DefaultList filters, sorts and paginates the objects with the query, the objects are
+// filtered by the label selector of the query first.
+func DefaultList(objects []runtime.Object, q *query.Query, compareFunc CompareFunc, filterFunc FilterFunc, transformFuncs ...TransformFunc) *api.ListResult {
+	// selected matched ones
+	var filtered []runtime.Object
+	selector := q.Selector()
+	for _, object := range objects {
+		if q.LabelSelector != "" && !labelMatch(object, selector) {
+			continue
+		}
+
+		selected := true
+		for field, value := range q.Filters {
+			if !filterFunc(object, query.Filter{Field: field, Value: value}) {
+				selected = false
+				break
+			}
+		}
+
+		if selected {
+			for _, transform := range transformFuncs {
+				object = transform(object)
+			}
+			filtered = append(filtered, object)
+		}
+	}
+
+	// sort by sortBy field
+	sort.Slice(filtered, func(i, j int) bool {
+		if !q.Ascending {
+			return compareFunc(filtered[i], filtered[j], q.SortBy)
+		}
+		return compareFunc(filtered[j], filtered[i], q.SortBy)
+	})
+
+	total := len(filtered)
+
+	if q.Pagination == nil {
+		q.Pagination = query.NoPagination
+	}
+
+	start, end := q.Pagination.GetValidPagination(total)
+
+	return &api.ListResult{
+		TotalItems: total,
+		Items:      objectsToInterfaces(filtered[start:end]),
+	}
+}
+
+// DefaultObjectMetaCompare return true is left great than right
+func DefaultObjectMetaCompare(left, right metav1.ObjectMeta, sortBy query.Field) bool {
+	switch sortBy {
+	// ?sortBy=name
+	case query.FieldName:
+		return strings.Compare(left.Name, right.Name) > 0
+	// ?sortBy=creationTimestamp
+	default:
+		// compare by name if creation timestamp is equal
+		if left.CreationTimestamp.Equal(&right.CreationTimestamp) {
+			return strings.Compare(left.Name, right.Name) > 0
+		}
+		return left.CreationTimestamp.After(right.CreationTimestamp.Time)
+	}
+}
+
+// DefaultObjectMetaFilter filters the object with the common fields of ObjectMeta
+func DefaultObjectMetaFilter(item metav1.ObjectMeta, filter query.Filter) bool {
+	switch filter.Field {
+	case query.FieldNames:
+		for _, name := range strings.Split(string(filter.Value), ",") {
+			if item.Name == name {
+				return true
+			}
+		}
+		return false
+	// /namespaces?page=1&limit=10&name=default
+	case query.FieldName:
+		return strings.Contains(item.Name, string(filter.Value))
+	// /namespaces?page=1&limit=10&uid=a8a8d6cf-f6a5-4fea-9c1b-e57610115706
+	case query.FieldUID:
+		return strings.Compare(string(item.UID), string(filter.Value)) == 0
+	// /deployments?page=1&limit=10&namespace=horizon-system
+	case query.FieldNamespace:
+		return strings.Compare(item.Namespace, string(filter.Value)) == 0
+	// /namespaces?page=1&limit=10&ownerReference=a8a8d6cf-f6a5-4fea-9c1b-e57610115706
+	case query.FieldOwnerReference:
+		for _, ownerReference := range item.OwnerReferences {
+			if strings.Compare(string(ownerReference.UID), string(filter.Value)) == 0 {
+				return true
+			}
+		}
+		return false
+	// /namespaces?page=1&limit=10&ownerKind=Workspace
+	case query.FieldOwnerKind:
+		for _, ownerReference := range item.OwnerReferences {
+			if strings.Compare(ownerReference.Kind, string(filter.Value)) == 0 {
+				return true
+			}
+		}
+		return false
+	// /namespaces?page=1&limit=10&annotation=horizon.io/creator
+	case query.FieldAnnotation:
+		return labelMatchValue(item.Annotations, string(filter.Value))
+	// /namespaces?page=1&limit=10&label=horizon.io/workspace=system-workspace
+	case query.FieldLabel:
+		return labelMatchValue(item.Labels, string(filter.Value))
+	// not supported filter
+	default:
+		return true
+	}
+}
+
+// labelMatchValue matches "key" or "key=value", multiple terms are separated by comma
+func labelMatchValue(labels map[string]string, filter string) bool {
+	for _, term := range strings.Split(filter, ",") {
+		key, value, hasValue := strings.Cut(term, "=")
+		actual, ok := labels[key]
+		if !ok || (hasValue && actual != value) {
+			return false
+		}
+	}
+	return true
+}
+
+func labelMatch(obj runtime.Object, selector labels.Selector) bool {
+	item, ok := obj.(metav1.Object)
+	if !ok {
+		return false
+	}
+	return selector.Matches(labels.Set(item.GetLabels()))
+}
+
+func objectsToInterfaces(objs []runtime.Object) []interface{} {
+	res := make([]interface{}, 0)
+	for _, obj := range objs {
+		res = append(res, obj)
+	}
+	return res
+}
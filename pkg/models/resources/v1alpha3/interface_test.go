@@ -0,0 +1,198 @@
+package v1alpha3
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sunweiwe/horizon/pkg/apiserver/query"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDefaultObjectMetaFilter(t *testing.T) {
+	item := metav1.ObjectMeta{
+		Name:        "frontend",
+		Namespace:   "ns1",
+		UID:         "uid-1",
+		Labels:      map[string]string{"app": "web", "tier": "frontend"},
+		Annotations: map[string]string{"horizon.io/creator": "alice"},
+		OwnerReferences: []metav1.OwnerReference{
+			{Kind: "Workspace", Name: "ws1", UID: "owner-uid"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		filter query.Filter
+		want   bool
+	}{
+		{name: "name contains", filter: query.Filter{Field: query.FieldName, Value: "front"}, want: true},
+		{name: "name does not contain", filter: query.Filter{Field: query.FieldName, Value: "backend"}, want: false},
+		{name: "names include", filter: query.Filter{Field: query.FieldNames, Value: "backend,frontend"}, want: true},
+		{name: "names match exactly", filter: query.Filter{Field: query.FieldNames, Value: "front"}, want: false},
+		{name: "uid", filter: query.Filter{Field: query.FieldUID, Value: "uid-1"}, want: true},
+		{name: "another uid", filter: query.Filter{Field: query.FieldUID, Value: "uid-2"}, want: false},
+		{name: "namespace", filter: query.Filter{Field: query.FieldNamespace, Value: "ns1"}, want: true},
+		{name: "another namespace", filter: query.Filter{Field: query.FieldNamespace, Value: "ns"}, want: false},
+		{name: "owner reference", filter: query.Filter{Field: query.FieldOwnerReference, Value: "owner-uid"}, want: true},
+		{name: "another owner reference", filter: query.Filter{Field: query.FieldOwnerReference, Value: "uid-1"}, want: false},
+		{name: "owner kind", filter: query.Filter{Field: query.FieldOwnerKind, Value: "Workspace"}, want: true},
+		{name: "another owner kind", filter: query.Filter{Field: query.FieldOwnerKind, Value: "Cluster"}, want: false},
+		{name: "label key", filter: query.Filter{Field: query.FieldLabel, Value: "app"}, want: true},
+		{name: "label key and value", filter: query.Filter{Field: query.FieldLabel, Value: "app=web"}, want: true},
+		{name: "label terms", filter: query.Filter{Field: query.FieldLabel, Value: "app=web,tier=frontend"}, want: true},
+		{name: "label terms partially match", filter: query.Filter{Field: query.FieldLabel, Value: "app=web,tier=backend"}, want: false},
+		{name: "missing label", filter: query.Filter{Field: query.FieldLabel, Value: "version"}, want: false},
+		{name: "annotation", filter: query.Filter{Field: query.FieldAnnotation, Value: "horizon.io/creator=alice"}, want: true},
+		{name: "another annotation value", filter: query.Filter{Field: query.FieldAnnotation, Value: "horizon.io/creator=bob"}, want: false},
+		{name: "unsupported field", filter: query.Filter{Field: "unknown", Value: "anything"}, want: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := DefaultObjectMetaFilter(item, test.filter); got != test.want {
+				t.Errorf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestDefaultObjectMetaCompare(t *testing.T) {
+	now := time.Now()
+	older := metav1.ObjectMeta{Name: "b", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))}
+	newer := metav1.ObjectMeta{Name: "a", CreationTimestamp: metav1.NewTime(now)}
+	sameTime := metav1.ObjectMeta{Name: "c", CreationTimestamp: metav1.NewTime(now)}
+
+	tests := []struct {
+		name   string
+		left   metav1.ObjectMeta
+		right  metav1.ObjectMeta
+		sortBy query.Field
+		want   bool
+	}{
+		{name: "newer is greater", left: newer, right: older, sortBy: query.FieldCreationTimeStamp, want: true},
+		{name: "older is not greater", left: older, right: newer, sortBy: query.FieldCreationTimeStamp, want: false},
+		{name: "same time compared by name", left: sameTime, right: newer, sortBy: query.FieldCreationTimeStamp, want: true},
+		{name: "same time compared by name reversed", left: newer, right: sameTime, sortBy: query.FieldCreationTimeStamp, want: false},
+		{name: "creation time by default", left: newer, right: older, sortBy: "", want: true},
+		{name: "by name", left: older, right: newer, sortBy: query.FieldName, want: true},
+		{name: "by name reversed", left: newer, right: older, sortBy: query.FieldName, want: false},
+		{name: "equal is not greater", left: newer, right: newer, sortBy: query.FieldName, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := DefaultObjectMetaCompare(test.left, test.right, test.sortBy); got != test.want {
+				t.Errorf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestDefaultList(t *testing.T) {
+	now := time.Now()
+	newConfigMap := func(name string, age time.Duration, labels map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Labels:            labels,
+			CreationTimestamp: metav1.NewTime(now.Add(-age)),
+		}}
+	}
+	objects := []runtime.Object{
+		newConfigMap("b", time.Hour, map[string]string{"app": "web"}),
+		newConfigMap("a", 2*time.Hour, map[string]string{"app": "web"}),
+		newConfigMap("d", 0, map[string]string{"app": "db"}),
+		newConfigMap("c", 0, nil),
+	}
+	compare := func(left, right runtime.Object, field query.Field) bool {
+		return DefaultObjectMetaCompare(left.(*corev1.ConfigMap).ObjectMeta, right.(*corev1.ConfigMap).ObjectMeta, field)
+	}
+	filter := func(object runtime.Object, filter query.Filter) bool {
+		return DefaultObjectMetaFilter(object.(*corev1.ConfigMap).ObjectMeta, filter)
+	}
+
+	tests := []struct {
+		name      string
+		query     *query.Query
+		wantTotal int
+		wantNames []string
+	}{
+		{
+			name:      "newest first by default",
+			query:     query.New(),
+			wantTotal: 4,
+			wantNames: []string{"d", "c", "b", "a"},
+		},
+		{
+			name:      "ascending",
+			query:     &query.Query{SortBy: query.FieldCreationTimeStamp, Ascending: true},
+			wantTotal: 4,
+			wantNames: []string{"a", "b", "c", "d"},
+		},
+		{
+			name:      "by name",
+			query:     &query.Query{SortBy: query.FieldName, Ascending: true},
+			wantTotal: 4,
+			wantNames: []string{"a", "b", "c", "d"},
+		},
+		{
+			name:      "label selector",
+			query:     &query.Query{LabelSelector: "app=web", SortBy: query.FieldName},
+			wantTotal: 2,
+			wantNames: []string{"b", "a"},
+		},
+		{
+			name:      "invalid label selector",
+			query:     &query.Query{LabelSelector: "app in (web"},
+			wantTotal: 0,
+			wantNames: []string{},
+		},
+		{
+			name: "filters",
+			query: &query.Query{
+				SortBy:  query.FieldName,
+				Filters: map[query.Field]query.Value{query.FieldLabel: "app", query.FieldNames: "a,d"},
+			},
+			wantTotal: 2,
+			wantNames: []string{"d", "a"},
+		},
+		{
+			name:      "paginated",
+			query:     &query.Query{SortBy: query.FieldName, Ascending: true, Pagination: &query.Pagination{Limit: 2, Offset: 1}},
+			wantTotal: 4,
+			wantNames: []string{"b", "c"},
+		},
+		{
+			name:      "last page",
+			query:     &query.Query{SortBy: query.FieldName, Ascending: true, Pagination: &query.Pagination{Limit: 2, Offset: 3}},
+			wantTotal: 4,
+			wantNames: []string{"d"},
+		},
+		{
+			name:      "out of range",
+			query:     &query.Query{Pagination: &query.Pagination{Limit: 2, Offset: 5}},
+			wantTotal: 4,
+			wantNames: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := DefaultList(objects, test.query, compare, filter)
+			if result.TotalItems != test.wantTotal {
+				t.Errorf("expected %d items in total, got %d", test.wantTotal, result.TotalItems)
+			}
+
+			names := make([]string, 0, len(result.Items))
+			for _, item := range result.Items {
+				names = append(names, item.(*corev1.ConfigMap).Name)
+			}
+			if !reflect.DeepEqual(names, test.wantNames) {
+				t.Errorf("expected items %v, got %v", test.wantNames, names)
+			}
+		})
+	}
+}
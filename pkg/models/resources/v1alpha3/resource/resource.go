@@ -5,8 +5,20 @@ import (
 
 	"github.com/sunweiwe/horizon/pkg/api"
 	"github.com/sunweiwe/horizon/pkg/apiserver/query"
+	"github.com/sunweiwe/horizon/pkg/informers"
+	"github.com/sunweiwe/horizon/pkg/models/resources/cluster"
+	"github.com/sunweiwe/horizon/pkg/models/resources/globalrole"
+	"github.com/sunweiwe/horizon/pkg/models/resources/role"
 	"github.com/sunweiwe/horizon/pkg/models/resources/v1alpha3"
+	"github.com/sunweiwe/horizon/pkg/models/resources/workspace"
+	"github.com/sunweiwe/horizon/pkg/models/resources/workspacerole"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	clusterv1alpha1 "github.com/sunweiwe/api/cluster/v1alpha1"
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	tenantv1alpha1 "github.com/sunweiwe/api/tenant/v1alpha1"
+	rbacv1 "k8s.io/api/rbac/v1"
 )
 
 var ErrResourceNotSupported = errors.New("resource is not supported")
@@ -16,6 +28,34 @@ type ResourceGetter struct {
 	namespacedResourceGetters map[schema.GroupVersionResource]v1alpha3.Interface
 }
 
+// NewResourceGetter returns the getters of the resources cached by the informers.
+func NewResourceGetter(factory informers.InformerFactory) *ResourceGetter {
+	clusterResourceGetters := map[schema.GroupVersionResource]v1alpha3.Interface{
+		clusterv1alpha1.SchemeGroupVersion.WithResource(clusterv1alpha1.ResourcesPluralCluster): cluster.New(factory.HorizonSharedInformerFactory()),
+		tenantv1alpha1.SchemeGroupVersion.WithResource(tenantv1alpha1.ResourcePluralWorkspace):  workspace.New(factory.HorizonSharedInformerFactory()),
+		iamv1alpha2.SchemeGroupVersion.WithResource(iamv1alpha2.ResourcePluralGlobalRole):       globalrole.New(factory.HorizonSharedInformerFactory()),
+		iamv1alpha2.SchemeGroupVersion.WithResource(iamv1alpha2.ResourcePluralWorkspaceRole):    workspacerole.New(factory.HorizonSharedInformerFactory()),
+	}
+
+	namespacedResourceGetters := map[schema.GroupVersionResource]v1alpha3.Interface{
+		rbacv1.SchemeGroupVersion.WithResource("roles"): role.New(factory.KubernetesSharedInformerFactory()),
+	}
+
+	return &ResourceGetter{
+		clusterResourceGetters:    clusterResourceGetters,
+		namespacedResourceGetters: namespacedResourceGetters,
+	}
+}
+
+func (r *ResourceGetter) Get(resource, namespace, name string) (runtime.Object, error) {
+	cluster := namespace == ""
+	getter := r.TryResource(cluster, resource)
+	if getter == nil {
+		return nil, ErrResourceNotSupported
+	}
+	return getter.Get(namespace, name)
+}
+
 func (r *ResourceGetter) List(resource, namespace string, query *query.Query) (*api.ListResult, error) {
 	cluster := namespace == ""
 	getter := r.TryResource(cluster, resource)
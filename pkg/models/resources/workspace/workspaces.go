@@ -0,0 +1,70 @@
+package workspace
+
+import (
+	"github.com/sunweiwe/horizon/pkg/api"
+	"github.com/sunweiwe/horizon/pkg/apiserver/query"
+	"github.com/sunweiwe/horizon/pkg/client/informers/externalversions"
+	"github.com/sunweiwe/horizon/pkg/models/resources/v1alpha3"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	tenantv1alpha1 "github.com/sunweiwe/api/tenant/v1alpha1"
+)
+
+const (
+	// FieldManager filters the workspaces by the manager
+	FieldManager = "manager"
+)
+
+type workspacesGetter struct {
+	horizonInformers externalversions.SharedInformerFactory
+}
+
+func New(horizon externalversions.SharedInformerFactory) v1alpha3.Interface {
+	return &workspacesGetter{horizonInformers: horizon}
+}
+
+func (w *workspacesGetter) Get(_, name string) (runtime.Object, error) {
+	return w.horizonInformers.Tenant().V1alpha1().Workspaces().Lister().Get(name)
+}
+
+func (w *workspacesGetter) List(_ string, query *query.Query) (*api.ListResult, error) {
+	workspaces, err := w.horizonInformers.Tenant().V1alpha1().Workspaces().Lister().List(query.Selector())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []runtime.Object
+	for _, workspace := range workspaces {
+		result = append(result, workspace)
+	}
+
+	return v1alpha3.DefaultList(result, query, w.compare, w.filter), nil
+}
+
+func (w *workspacesGetter) compare(left runtime.Object, right runtime.Object, field query.Field) bool {
+	leftWorkspace, ok := left.(*tenantv1alpha1.Workspace)
+	if !ok {
+		return false
+	}
+
+	rightWorkspace, ok := right.(*tenantv1alpha1.Workspace)
+	if !ok {
+		return false
+	}
+
+	return v1alpha3.DefaultObjectMetaCompare(leftWorkspace.ObjectMeta, rightWorkspace.ObjectMeta, field)
+}
+
+func (w *workspacesGetter) filter(object runtime.Object, filter query.Filter) bool {
+	workspace, ok := object.(*tenantv1alpha1.Workspace)
+	if !ok {
+		return false
+	}
+
+	switch filter.Field {
+	case FieldManager:
+		return workspace.Spec.Manager == string(filter.Value)
+	default:
+		return v1alpha3.DefaultObjectMetaFilter(workspace.ObjectMeta, filter)
+	}
+}
@@ -1,37 +1,66 @@
 package tenant
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 
+	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/sunweiwe/horizon/pkg/api"
 	"github.com/sunweiwe/horizon/pkg/apiserver/authorization/authorizer"
 	"github.com/sunweiwe/horizon/pkg/apiserver/query"
 	"github.com/sunweiwe/horizon/pkg/apiserver/request"
 	"github.com/sunweiwe/horizon/pkg/client/clientset"
+	tenantlisters "github.com/sunweiwe/horizon/pkg/client/listers/tenant/v1alpha1"
 	"github.com/sunweiwe/horizon/pkg/informers"
+	"github.com/sunweiwe/horizon/pkg/models/iam/am"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
 
 	clusterv1alpha1 "github.com/sunweiwe/api/cluster/v1alpha1"
+	tenantv1alpha1 "github.com/sunweiwe/api/tenant/v1alpha1"
 	resourcesv1alpha3 "github.com/sunweiwe/horizon/pkg/models/resources/v1alpha3/resource"
 )
 
 type Interface interface {
+	// ListClusters lists all the clusters if the user is allowed to list clusters globally,
+	// otherwise only the clusters any of the workspaces of the user is placed on
 	ListClusters(info user.Info, params *query.Query) (*api.ListResult, error)
+	// ListWorkspaces lists all the workspaces if the user is allowed to list workspaces globally by the roles
+	// not shared by all the authenticated users, otherwise only the workspaces the user or its groups are bound to
+	ListWorkspaces(info user.Info, params *query.Query) (*api.ListResult, error)
+	CreateWorkspace(workspace *tenantv1alpha1.Workspace) (*tenantv1alpha1.Workspace, error)
+	DescribeWorkspace(name string) (*tenantv1alpha1.Workspace, error)
+	UpdateWorkspace(workspace *tenantv1alpha1.Workspace) (*tenantv1alpha1.Workspace, error)
+	// PatchWorkspace applies the JSON merge patch to the workspace
+	PatchWorkspace(name string, patch []byte) (*tenantv1alpha1.Workspace, error)
+	DeleteWorkspace(name string) error
 }
 
 type tenantOperator struct {
-	kube           kubernetes.Interface
-	horizon        clientset.Interface
-	authorizer     authorizer.Authorizer
-	resourceGetter *resourcesv1alpha3.ResourceGetter
+	kube                    kubernetes.Interface
+	horizon                 clientset.Interface
+	am                      am.AccessManagementInterface
+	authorizer              authorizer.Authorizer
+	resourceGetter          *resourcesv1alpha3.ResourceGetter
+	workspaceTemplateLister tenantlisters.WorkspaceTemplateLister
 }
 
-func New(informers informers.InformerFactory, client kubernetes.Interface, horizon clientset.Interface) Interface {
+func New(informers informers.InformerFactory, client kubernetes.Interface, horizon clientset.Interface, am am.AccessManagementInterface, authorizer authorizer.Authorizer) Interface {
 
 	return &tenantOperator{
-		kube:    client,
-		horizon: horizon,
+		kube:                    client,
+		horizon:                 horizon,
+		am:                      am,
+		authorizer:              authorizer,
+		resourceGetter:          resourcesv1alpha3.NewResourceGetter(informers),
+		workspaceTemplateLister: informers.HorizonSharedInformerFactory().Tenant().V1alpha1().WorkspaceTemplates().Lister(),
 	}
 }
 
@@ -40,13 +69,13 @@ func (t *tenantOperator) ListClusters(info user.Info, params *query.Query) (*api
 	listClusters := authorizer.AtrributesRecord{
 		User:            info,
 		Verb:            "list",
-		APIGroup:        "cluster.horizon.io",
-		Resource:        "clusters",
+		APIGroup:        clusterv1alpha1.SchemeGroupVersion.Group,
+		Resource:        clusterv1alpha1.ResourcesPluralCluster,
 		ResourceScope:   request.GlobalScope,
 		ResourceRequest: true,
 	}
 
-	allowedListClusters, _, err := t.authorizer.Authorize(listClusters)
+	allowedListClusters, _, err := t.authorizer.Authorize(&listClusters)
 	if err != nil {
 		return nil, fmt.Errorf("failed to authorize: %s", err)
 	}
@@ -55,5 +84,188 @@ func (t *tenantOperator) ListClusters(info user.Info, params *query.Query) (*api
 		return t.resourceGetter.List(clusterv1alpha1.ResourcesPluralCluster, "", params)
 	}
 
-	return &api.ListResult{}, nil
+	workspaces, err := t.listUserWorkspaces(info)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := sets.New[string]()
+	for _, workspace := range sets.List(workspaces) {
+		workspaceTemplate, err := t.workspaceTemplateLister.Get(workspace)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			klog.Error(err)
+			return nil, err
+		}
+		for _, status := range workspaceTemplate.Status.Clusters {
+			if status.Phase == tenantv1alpha1.PlacementPlaced {
+				clusters.Insert(status.Name)
+			}
+		}
+	}
+
+	return t.listVisible(clusterv1alpha1.ResourcesPluralCluster, params, clusters)
+}
+
+func (t *tenantOperator) ListWorkspaces(info user.Info, params *query.Query) (*api.ListResult, error) {
+
+	// all the authenticated users are allowed to list workspaces, which are filtered for them below,
+	// only the roles bound to the user or its own groups grant listing all of them
+	listWorkspaces := authorizer.AtrributesRecord{
+		User:            withoutAuthenticatedGroup(info),
+		Verb:            "list",
+		APIGroup:        tenantv1alpha1.SchemeGroupVersion.Group,
+		Resource:        tenantv1alpha1.ResourcePluralWorkspace,
+		ResourceScope:   request.GlobalScope,
+		ResourceRequest: true,
+	}
+
+	allowedListWorkspaces, _, err := t.authorizer.Authorize(&listWorkspaces)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authorize: %s", err)
+	}
+
+	if allowedListWorkspaces == authorizer.DecisionAllow {
+		return t.resourceGetter.List(tenantv1alpha1.ResourcePluralWorkspace, "", params)
+	}
+
+	workspaces, err := t.listUserWorkspaces(info)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.listVisible(tenantv1alpha1.ResourcePluralWorkspace, params, workspaces)
+}
+
+// withoutAuthenticatedGroup returns the user without the group shared by all the authenticated users.
+func withoutAuthenticatedGroup(info user.Info) user.Info {
+	groups := make([]string, 0, len(info.GetGroups()))
+	for _, group := range info.GetGroups() {
+		if group != user.AllAuthenticated {
+			groups = append(groups, group)
+		}
+	}
+	return &user.DefaultInfo{Name: info.GetName(), UID: info.GetUID(), Groups: groups, Extra: info.GetExtra()}
+}
+
+// listUserWorkspaces returns the names of the workspaces the user or its groups are bound to.
+func (t *tenantOperator) listUserWorkspaces(info user.Info) (sets.Set[string], error) {
+	workspaceRoleBindings, err := t.am.ListWorkspaceRoleBindings(info.GetName(), info.GetGroups(), "")
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	workspaces := sets.New[string]()
+	for _, roleBinding := range workspaceRoleBindings {
+		if workspace := roleBinding.Labels[tenantv1alpha1.WorkspaceLabel]; workspace != "" {
+			workspaces.Insert(workspace)
+		}
+	}
+	return workspaces, nil
+}
+
+// listVisible lists the cluster scoped resources of the visible names, the resources are
+// filtered and sorted by the query before paginating the visible ones.
+func (t *tenantOperator) listVisible(resource string, params *query.Query, visible sets.Set[string]) (*api.ListResult, error) {
+	if visible.Len() == 0 {
+		return &api.ListResult{Items: []interface{}{}}, nil
+	}
+
+	all, err := t.resourceGetter.List(resource, "", &query.Query{
+		Pagination:    query.NoPagination,
+		SortBy:        params.SortBy,
+		Ascending:     params.Ascending,
+		Filters:       params.Filters,
+		LabelSelector: params.LabelSelector,
+	})
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	items := make([]interface{}, 0, visible.Len())
+	for _, item := range all.Items {
+		object, err := meta.Accessor(item)
+		if err == nil && visible.Has(object.GetName()) {
+			items = append(items, item)
+		}
+	}
+
+	pagination := params.Pagination
+	if pagination == nil {
+		pagination = query.NoPagination
+	}
+	start, end := pagination.GetValidPagination(len(items))
+
+	return &api.ListResult{TotalItems: len(items), Items: items[start:end]}, nil
+}
+
+func (t *tenantOperator) CreateWorkspace(workspace *tenantv1alpha1.Workspace) (*tenantv1alpha1.Workspace, error) {
+	created, err := t.horizon.TenantV1alpha1().Workspaces().Create(context.Background(), workspace, metav1.CreateOptions{})
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	return created, nil
+}
+
+func (t *tenantOperator) DescribeWorkspace(name string) (*tenantv1alpha1.Workspace, error) {
+	obj, err := t.resourceGetter.Get(tenantv1alpha1.ResourcePluralWorkspace, "", name)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*tenantv1alpha1.Workspace), nil
+}
+
+func (t *tenantOperator) UpdateWorkspace(workspace *tenantv1alpha1.Workspace) (*tenantv1alpha1.Workspace, error) {
+	updated, err := t.horizon.TenantV1alpha1().Workspaces().Update(context.Background(), workspace, metav1.UpdateOptions{})
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (t *tenantOperator) PatchWorkspace(name string, patch []byte) (*tenantv1alpha1.Workspace, error) {
+	old, err := t.horizon.TenantV1alpha1().Workspaces().Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	// apply the patch locally to validate the result before sending it
+	original, err := json.Marshal(old)
+	if err != nil {
+		return nil, err
+	}
+	patched, err := jsonpatch.MergePatch(original, patch)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	workspace := &tenantv1alpha1.Workspace{}
+	if err := json.Unmarshal(patched, workspace); err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	if workspace.Name != old.Name {
+		return nil, apierrors.NewBadRequest("the name of the workspace can not be changed")
+	}
+
+	updated, err := t.horizon.TenantV1alpha1().Workspaces().Patch(context.Background(), name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (t *tenantOperator) DeleteWorkspace(name string) error {
+	err := t.horizon.TenantV1alpha1().Workspaces().Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	return nil
 }
@@ -0,0 +1,330 @@
+package tenant
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sunweiwe/horizon/pkg/apiserver/authorization/authorizer"
+	"github.com/sunweiwe/horizon/pkg/apiserver/authorization/rbac"
+	"github.com/sunweiwe/horizon/pkg/apiserver/query"
+	"github.com/sunweiwe/horizon/pkg/apiserver/request"
+	"github.com/sunweiwe/horizon/pkg/informers"
+	"github.com/sunweiwe/horizon/pkg/models/iam/am"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/client-go/kubernetes/fake"
+
+	clusterv1alpha1 "github.com/sunweiwe/api/cluster/v1alpha1"
+	iamv1alpha2 "github.com/sunweiwe/api/iam/v1alpha2"
+	tenantv1alpha1 "github.com/sunweiwe/api/tenant/v1alpha1"
+	fakeclientset "github.com/sunweiwe/horizon/pkg/client/clientset/fake"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newCluster(name string, created time.Time) *clusterv1alpha1.Cluster {
+	return &clusterv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+	}
+}
+
+func newWorkspace(name string, created time.Time) *tenantv1alpha1.Workspace {
+	return &tenantv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+	}
+}
+
+func newWorkspaceRoleBinding(workspace string, username string) *iamv1alpha2.WorkspaceRoleBinding {
+	return &iamv1alpha2.WorkspaceRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   username + "-" + workspace + "-viewer",
+			Labels: map[string]string{tenantv1alpha1.WorkspaceLabel: workspace},
+		},
+		RoleRef: rbacv1.RoleRef{APIGroup: iamv1alpha2.SchemeGroupVersion.Group, Kind: iamv1alpha2.ResourceKindWorkspaceRole, Name: workspace + "-viewer"},
+		Subjects: []rbacv1.Subject{
+			{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: username},
+		},
+	}
+}
+
+func newWorkspaceTemplate(name string, placements map[string]tenantv1alpha1.PlacementPhase) *tenantv1alpha1.WorkspaceTemplate {
+	workspaceTemplate := &tenantv1alpha1.WorkspaceTemplate{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	for cluster, phase := range placements {
+		workspaceTemplate.Status.Clusters = append(workspaceTemplate.Status.Clusters,
+			tenantv1alpha1.ClusterPlacementStatus{Name: cluster, Phase: phase})
+	}
+	return workspaceTemplate
+}
+
+// newTestOperator returns the tenant operator backed by the informers of the fake clientsets,
+// authorized by the RBAC authorizer which is returned along with it.
+func newTestOperator(t *testing.T, objects ...runtime.Object) (Interface, authorizer.Authorizer) {
+	kubeClient := fake.NewSimpleClientset()
+	horizonClient := fakeclientset.NewSimpleClientset(objects...)
+	factory := informers.NewInformerFactories(kubeClient, horizonClient)
+
+	amOperator := am.NewOperator(kubeClient, horizonClient, factory)
+	rbacAuthorizer := rbac.NewRBACAuthorizer(amOperator, "host")
+	operator := New(factory, kubeClient, horizonClient, amOperator, rbacAuthorizer)
+
+	// the informers of the resource getters are started by the apiserver beforehand
+	factory.HorizonSharedInformerFactory().Cluster().V1alpha1().Clusters().Informer()
+	factory.HorizonSharedInformerFactory().Tenant().V1alpha1().Workspaces().Informer()
+
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	factory.Start(stopCh)
+	factory.KubernetesSharedInformerFactory().WaitForCacheSync(stopCh)
+	factory.HorizonSharedInformerFactory().WaitForCacheSync(stopCh)
+
+	return operator, rbacAuthorizer
+}
+
+func TestListClusters(t *testing.T) {
+	now := time.Now()
+	objects := []runtime.Object{
+		newCluster("host", now.Add(-3*time.Hour)),
+		newCluster("member1", now.Add(-2*time.Hour)),
+		newCluster("member2", now.Add(-time.Hour)),
+		&iamv1alpha2.GlobalRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "clusters-viewer"},
+			Rules: []rbacv1.PolicyRule{{
+				APIGroups: []string{clusterv1alpha1.SchemeGroupVersion.Group},
+				Resources: []string{clusterv1alpha1.ResourcesPluralCluster},
+				Verbs:     []string{"list"},
+			}},
+		},
+		&iamv1alpha2.GlobalRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "admin-clusters-viewer"},
+			RoleRef:    rbacv1.RoleRef{APIGroup: iamv1alpha2.SchemeGroupVersion.Group, Kind: iamv1alpha2.ResourceKindGlobalRole, Name: "clusters-viewer"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "admin"}},
+		},
+		newWorkspaceRoleBinding("ws1", "alice"),
+		newWorkspaceRoleBinding("ws1", "bob"),
+		newWorkspaceRoleBinding("ws2", "bob"),
+		// ws3 has no template, it is not placed on any cluster
+		newWorkspaceRoleBinding("ws3", "dave"),
+		newWorkspaceTemplate("ws1", map[string]tenantv1alpha1.PlacementPhase{
+			"host":    tenantv1alpha1.PlacementPlaced,
+			"member1": tenantv1alpha1.PlacementFailed,
+		}),
+		newWorkspaceTemplate("ws2", map[string]tenantv1alpha1.PlacementPhase{
+			"member2": tenantv1alpha1.PlacementPlaced,
+		}),
+	}
+
+	tests := []struct {
+		name      string
+		username  string
+		query     *query.Query
+		wantTotal int
+		wantNames []string
+	}{
+		{
+			name:      "global grant lists all clusters",
+			username:  "admin",
+			query:     query.New(),
+			wantTotal: 3,
+			wantNames: []string{"member2", "member1", "host"},
+		},
+		{
+			name:      "clusters placed successfully only",
+			username:  "alice",
+			query:     query.New(),
+			wantTotal: 1,
+			wantNames: []string{"host"},
+		},
+		{
+			name:      "clusters of all the workspaces",
+			username:  "bob",
+			query:     query.New(),
+			wantTotal: 2,
+			wantNames: []string{"member2", "host"},
+		},
+		{
+			name:      "workspace without template",
+			username:  "dave",
+			query:     query.New(),
+			wantTotal: 0,
+			wantNames: []string{},
+		},
+		{
+			name:      "no workspaces",
+			username:  "carol",
+			query:     query.New(),
+			wantTotal: 0,
+			wantNames: []string{},
+		},
+		{
+			name:     "global grant sorted by name and paginated",
+			username: "admin",
+			query: &query.Query{
+				Pagination: &query.Pagination{Limit: 2, Offset: 0},
+				SortBy:     query.FieldName,
+				Ascending:  true,
+			},
+			wantTotal: 3,
+			wantNames: []string{"host", "member1"},
+		},
+		{
+			name:     "workspace clusters sorted by name and paginated",
+			username: "bob",
+			query: &query.Query{
+				Pagination: &query.Pagination{Limit: 1, Offset: 1},
+				SortBy:     query.FieldName,
+				Ascending:  false,
+			},
+			wantTotal: 2,
+			wantNames: []string{"host"},
+		},
+		{
+			name:     "workspace clusters out of page",
+			username: "bob",
+			query: &query.Query{
+				Pagination: &query.Pagination{Limit: 10, Offset: 10},
+			},
+			wantTotal: 2,
+			wantNames: []string{},
+		},
+	}
+
+	operator, _ := newTestOperator(t, objects...)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := operator.ListClusters(&user.DefaultInfo{Name: test.username}, test.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.TotalItems != test.wantTotal {
+				t.Errorf("expected %d clusters in total, got %d", test.wantTotal, result.TotalItems)
+			}
+
+			names := make([]string, 0, len(result.Items))
+			for _, item := range result.Items {
+				names = append(names, item.(*clusterv1alpha1.Cluster).Name)
+			}
+			if !reflect.DeepEqual(names, test.wantNames) {
+				t.Errorf("expected clusters %v, got %v", test.wantNames, names)
+			}
+		})
+	}
+}
+
+func TestListWorkspaces(t *testing.T) {
+	now := time.Now()
+	objects := []runtime.Object{
+		newWorkspace("ws1", now.Add(-3*time.Hour)),
+		newWorkspace("ws2", now.Add(-2*time.Hour)),
+		newWorkspace("ws3", now.Add(-time.Hour)),
+		// the role shared by all the authenticated users, as shipped by the chart
+		&iamv1alpha2.GlobalRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "authenticated"},
+			Rules: []rbacv1.PolicyRule{{
+				APIGroups: []string{tenantv1alpha1.SchemeGroupVersion.Group},
+				Resources: []string{tenantv1alpha1.ResourcePluralWorkspace},
+				Verbs:     []string{"list"},
+			}},
+		},
+		&iamv1alpha2.GlobalRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "authenticated"},
+			RoleRef:    rbacv1.RoleRef{APIGroup: iamv1alpha2.SchemeGroupVersion.Group, Kind: iamv1alpha2.ResourceKindGlobalRole, Name: "authenticated"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: user.AllAuthenticated}},
+		},
+		&iamv1alpha2.GlobalRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "workspaces-viewer"},
+			Rules: []rbacv1.PolicyRule{{
+				APIGroups: []string{tenantv1alpha1.SchemeGroupVersion.Group},
+				Resources: []string{tenantv1alpha1.ResourcePluralWorkspace},
+				Verbs:     []string{"get", "list"},
+			}},
+		},
+		&iamv1alpha2.GlobalRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "admin-workspaces-viewer"},
+			RoleRef:    rbacv1.RoleRef{APIGroup: iamv1alpha2.SchemeGroupVersion.Group, Kind: iamv1alpha2.ResourceKindGlobalRole, Name: "workspaces-viewer"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "admin"}},
+		},
+		newWorkspaceRoleBinding("ws1", "alice"),
+		newWorkspaceRoleBinding("ws1", "bob"),
+		newWorkspaceRoleBinding("ws3", "bob"),
+	}
+
+	tests := []struct {
+		name      string
+		username  string
+		query     *query.Query
+		wantTotal int
+		wantNames []string
+	}{
+		{
+			name:      "global grant lists all workspaces",
+			username:  "admin",
+			query:     query.New(),
+			wantTotal: 3,
+			wantNames: []string{"ws3", "ws2", "ws1"},
+		},
+		{
+			name:      "workspaces of the user",
+			username:  "alice",
+			query:     query.New(),
+			wantTotal: 1,
+			wantNames: []string{"ws1"},
+		},
+		{
+			name:     "workspaces of the user sorted by name and paginated",
+			username: "bob",
+			query: &query.Query{
+				Pagination: &query.Pagination{Limit: 1, Offset: 0},
+				SortBy:     query.FieldName,
+				Ascending:  true,
+			},
+			wantTotal: 2,
+			wantNames: []string{"ws1"},
+		},
+		{
+			name:      "no workspaces",
+			username:  "carol",
+			query:     query.New(),
+			wantTotal: 0,
+			wantNames: []string{},
+		},
+	}
+
+	operator, rbacAuthorizer := newTestOperator(t, objects...)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			info := &user.DefaultInfo{Name: test.username, Groups: []string{user.AllAuthenticated}}
+
+			// the request is authorized as a global list before it reaches the operator
+			decision, reason, err := rbacAuthorizer.Authorize(&authorizer.AtrributesRecord{
+				User:            info,
+				Verb:            "list",
+				APIGroup:        tenantv1alpha1.SchemeGroupVersion.Group,
+				Resource:        tenantv1alpha1.ResourcePluralWorkspace,
+				ResourceScope:   request.GlobalScope,
+				ResourceRequest: true,
+			})
+			if err != nil || decision != authorizer.DecisionAllow {
+				t.Fatalf("expected listing workspaces to be allowed, got %v: %s, %v", decision, reason, err)
+			}
+
+			result, err := operator.ListWorkspaces(info, test.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.TotalItems != test.wantTotal {
+				t.Errorf("expected %d workspaces in total, got %d", test.wantTotal, result.TotalItems)
+			}
+
+			names := make([]string, 0, len(result.Items))
+			for _, item := range result.Items {
+				names = append(names, item.(*tenantv1alpha1.Workspace).Name)
+			}
+			if !reflect.DeepEqual(names, test.wantNames) {
+				t.Errorf("expected workspaces %v, got %v", test.wantNames, names)
+			}
+		})
+	}
+}
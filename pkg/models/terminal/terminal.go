@@ -0,0 +1,237 @@
+package terminal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/klog/v2"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// EndOfTransmission is sent to the shell when the websocket is closed
+	EndOfTransmission = "\u0004"
+
+	writeWait = 10 * time.Second
+)
+
+// Message is the messages exchanged with the terminal over the websocket:
+//
+//	Op      Direction  Field(s) used  Description
+//	---------------------------------------------------------------------
+//	stdin   fe->be     Data           keystrokes or paste buffer
+//	resize  fe->be     Rows, Cols     new terminal size
+//	stdout  be->fe     Data           output from the process
+//	toast   be->fe     Data           out-of-band message to the user
+type Message struct {
+	Op   string `json:"op"`
+	Data string `json:"data,omitempty"`
+	Rows uint16 `json:"rows,omitempty"`
+	Cols uint16 `json:"cols,omitempty"`
+}
+
+// Interface attaches the shell or the logs of the container of a pod to the websocket.
+type Interface interface {
+	// HandleSession executes a shell in the container and attaches it to the websocket
+	HandleSession(shell, namespace, podName, containerName string, conn *websocket.Conn)
+	// HandleLogSession streams the logs of the container to the websocket until the stream ends or the websocket is closed
+	HandleLogSession(namespace, podName string, options *corev1.PodLogOptions, conn *websocket.Conn)
+}
+
+type terminaler struct {
+	client kubernetes.Interface
+	config *rest.Config
+}
+
+func NewTerminaler(client kubernetes.Interface, config *rest.Config) Interface {
+	return &terminaler{client: client, config: config}
+}
+
+// session implements remotecommand.TerminalSizeQueue, io.Reader and io.Writer over the websocket.
+type session struct {
+	conn     *websocket.Conn
+	sizeChan chan remotecommand.TerminalSize
+	done     chan struct{}
+}
+
+// Next returns the new terminal size after the terminal has been resized, nil after the session is closed.
+func (s *session) Next() *remotecommand.TerminalSize {
+	select {
+	case size := <-s.sizeChan:
+		return &size
+	case <-s.done:
+		return nil
+	}
+}
+
+// Read reads the stdin of the shell from the websocket, resize messages are handed over to Next.
+func (s *session) Read(p []byte) (int, error) {
+	var msg Message
+	if err := s.conn.ReadJSON(&msg); err != nil {
+		return copy(p, EndOfTransmission), err
+	}
+
+	switch msg.Op {
+	case "stdin":
+		return copy(p, msg.Data), nil
+	case "resize":
+		select {
+		case s.sizeChan <- remotecommand.TerminalSize{Width: msg.Cols, Height: msg.Rows}:
+		case <-s.done:
+		}
+		return 0, nil
+	default:
+		return copy(p, EndOfTransmission), fmt.Errorf("unknown message type '%s'", msg.Op)
+	}
+}
+
+// Write writes the output of the shell to the websocket.
+func (s *session) Write(p []byte) (int, error) {
+	msg, err := json.Marshal(Message{Op: "stdout", Data: string(p)})
+	if err != nil {
+		return 0, err
+	}
+	if err := s.conn.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
+		return 0, err
+	}
+	if err := s.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Toast sends an out-of-band message to the user.
+func (s *session) Toast(p string) error {
+	msg, err := json.Marshal(Message{Op: "toast", Data: p})
+	if err != nil {
+		return err
+	}
+	if err := s.conn.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
+		return err
+	}
+	return s.conn.WriteMessage(websocket.TextMessage, msg)
+}
+
+// Close closes the session with the given status and reason.
+func (s *session) Close(status uint32, reason string) {
+	close(s.done)
+	if err := s.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(int(status), reason), time.Now().Add(writeWait)); err != nil {
+		klog.V(4).Infof("failed to close websocket: %v", err)
+	}
+	s.conn.Close()
+}
+
+func (t *terminaler) startProcess(namespace, podName, containerName string, cmd []string, ptyHandler *session) error {
+	req := t.client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(podName).
+		Namespace(namespace).
+		SubResource("exec")
+
+	req.VersionedParams(&corev1.PodExecOptions{
+		Container: containerName,
+		Command:   cmd,
+		Stdin:     true,
+		Stdout:    true,
+		Stderr:    true,
+		TTY:       true,
+	}, scheme.ParameterCodec)
+
+	exec, err := remotecommand.NewSPDYExecutor(t.config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+
+	return exec.StreamWithContext(context.Background(), remotecommand.StreamOptions{
+		Stdin:             ptyHandler,
+		Stdout:            ptyHandler,
+		Stderr:            ptyHandler,
+		TerminalSizeQueue: ptyHandler,
+		Tty:               true,
+	})
+}
+
+// isValidShell checks if the shell is an allowed one
+func isValidShell(validShells []string, shell string) bool {
+	for _, validShell := range validShells {
+		if validShell == shell {
+			return true
+		}
+	}
+	return false
+}
+
+// HandleSession starts the given shell, or the first working one of bash and sh if the shell is not
+// given or not allowed, in the container and closes the websocket after the shell exits.
+func (t *terminaler) HandleSession(shell, namespace, podName, containerName string, conn *websocket.Conn) {
+	s := &session{conn: conn, sizeChan: make(chan remotecommand.TerminalSize), done: make(chan struct{})}
+
+	var err error
+	validShells := []string{"bash", "sh"}
+	if isValidShell(validShells, shell) {
+		err = t.startProcess(namespace, podName, containerName, []string{shell}, s)
+	} else {
+		// try the shells in order until one of them succeeds
+		for _, testShell := range validShells {
+			if err = t.startProcess(namespace, podName, containerName, []string{testShell}, s); err == nil {
+				break
+			}
+		}
+	}
+
+	if err != nil && err != io.EOF {
+		klog.Warningf("failed to start shell in %s/%s: %v", namespace, podName, err)
+		if toastErr := s.Toast(err.Error()); toastErr != nil {
+			klog.V(4).Info(toastErr)
+		}
+		s.Close(websocket.CloseInternalServerErr, err.Error())
+		return
+	}
+
+	s.Close(websocket.CloseNormalClosure, "Process exited")
+}
+
+func (t *terminaler) HandleLogSession(namespace, podName string, options *corev1.PodLogOptions, conn *websocket.Conn) {
+	s := &session{conn: conn, sizeChan: make(chan remotecommand.TerminalSize), done: make(chan struct{})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the stream is cancelled once the websocket is closed by the client, the messages from the client are discarded
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	stream, err := t.client.CoreV1().Pods(namespace).GetLogs(podName, options).Stream(ctx)
+	if err != nil {
+		klog.Warningf("failed to stream logs of %s/%s: %v", namespace, podName, err)
+		if toastErr := s.Toast(err.Error()); toastErr != nil {
+			klog.V(4).Info(toastErr)
+		}
+		s.Close(websocket.CloseInternalServerErr, err.Error())
+		return
+	}
+	defer stream.Close()
+
+	if _, err = io.Copy(s, stream); err != nil && ctx.Err() == nil {
+		klog.V(4).Infof("log stream of %s/%s interrupted: %v", namespace, podName, err)
+		s.Close(websocket.CloseInternalServerErr, err.Error())
+		return
+	}
+
+	s.Close(websocket.CloseNormalClosure, "Log stream ended")
+}
@@ -14,11 +14,26 @@ type KubernetesOptions struct {
 	// in cluster way to create clientset
 	KubeConfig string `json:"kubeconfig" yaml:"kubeconfig"`
 
-	// kubernetes apiserver public address, used to generate kubeconfig
-	// for downloading, default to host defined in kubeconfig
+	// public address of hz-apiserver, which proxies the requests to the kubernetes apiserver,
+	// used to generate kubeconfig for downloading, default to the in-cluster hz-apiserver service
 	// +optional
 	Master string `json:"master,omitempty" yaml:"master,omitempty"`
 
+	// forward the proxied requests to the kubernetes apiserver as the requesting user through
+	// impersonation, so that kubernetes RBAC, audit and admission see the user, otherwise the
+	// requests are forwarded with the privileged credentials of horizon
+	// +optional
+	Impersonation bool `json:"impersonation,omitempty" yaml:"impersonation,omitempty"`
+
+	// image used to create kubectl pods for users
+	// +optional
+	KubectlImage string `json:"kubectlImage,omitempty" yaml:"kubectlImage,omitempty"`
+
+	// origins of hz-console, e.g. https://console.example.com, the websocket terminals are only opened
+	// from them by the browsers, the terminals are only opened from the origin of hz-apiserver if empty
+	// +optional
+	ConsoleOrigins []string `json:"consoleOrigins,omitempty" yaml:"consoleOrigins,omitempty"`
+
 	// kubernetes clientset qps
 	// +optional
 	QPS float32 `json:"qps,omitempty" yaml:"qps,omitempty"`
@@ -34,7 +49,18 @@ func (k *KubernetesOptions) AddFlags(fs *pflag.FlagSet, options *KubernetesOptio
 		"in cluster way.")
 
 	fs.StringVar(&k.Master, "master", options.Master, ""+
-		"Used to generate kubeconfig for downloading, if not specified, will use host in kubeconfig.")
+		"Public address of hz-apiserver used to generate kubeconfig for downloading, if not specified, will use the in-cluster hz-apiserver service.")
+
+	fs.BoolVar(&k.Impersonation, "kube-proxy-impersonation", options.Impersonation, ""+
+		"Forward the proxied requests to the kubernetes apiserver by impersonating the requesting user, "+
+		"instead of the privileged credentials of horizon.")
+
+	fs.StringVar(&k.KubectlImage, "kubectl-image", options.KubectlImage, ""+
+		"Image used to create the kubectl pods of users.")
+
+	fs.StringSliceVar(&k.ConsoleOrigins, "console-origins", options.ConsoleOrigins, ""+
+		"Origins of hz-console the websocket terminals are opened from by the browsers, e.g. https://console.example.com, "+
+		"if not specified, only the origin of hz-apiserver is allowed.")
 }
 
 func NewKubernetesClientOptions() (option *KubernetesOptions) {
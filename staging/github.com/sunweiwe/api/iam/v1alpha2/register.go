@@ -22,6 +22,21 @@ func Resource(resource string) schema.GroupResource {
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&User{},
+		&UserList{},
+		&LoginRecord{},
+		&LoginRecordList{},
+		&Group{},
+		&GroupList{},
+		&GroupBinding{},
+		&GroupBindingList{},
+		&GlobalRole{},
+		&GlobalRoleList{},
+		&GlobalRoleBinding{},
+		&GlobalRoleBindingList{},
+		&WorkspaceRole{},
+		&WorkspaceRoleList{},
+		&WorkspaceRoleBinding{},
+		&WorkspaceRoleBindingList{},
 	)
 
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
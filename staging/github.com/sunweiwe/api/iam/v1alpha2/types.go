@@ -1,9 +1,49 @@
 package v1alpha2
 
 import (
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	ResourceKindUser                     = "User"
+	ResourceSingularUser                 = "user"
+	ResourcePluralUser                   = "users"
+	ResourceKindLoginRecord              = "LoginRecord"
+	ResourceSingularLoginRecord          = "loginrecord"
+	ResourcePluralLoginRecord            = "loginrecords"
+	ResourceKindGroup                    = "Group"
+	ResourceSingularGroup                = "group"
+	ResourcePluralGroup                  = "groups"
+	ResourceKindGroupBinding             = "GroupBinding"
+	ResourceSingularGroupBinding         = "groupbinding"
+	ResourcePluralGroupBinding           = "groupbindings"
+	ResourceKindGlobalRole               = "GlobalRole"
+	ResourceSingularGlobalRole           = "globalrole"
+	ResourcePluralGlobalRole             = "globalroles"
+	ResourceKindGlobalRoleBinding        = "GlobalRoleBinding"
+	ResourceSingularGlobalRoleBinding    = "globalrolebinding"
+	ResourcePluralGlobalRoleBinding      = "globalrolebindings"
+	ResourceKindWorkspaceRole            = "WorkspaceRole"
+	ResourceSingularWorkspaceRole        = "workspacerole"
+	ResourcePluralWorkspaceRole          = "workspaceroles"
+	ResourceKindWorkspaceRoleBinding     = "WorkspaceRoleBinding"
+	ResourceSingularWorkspaceRoleBinding = "workspacerolebinding"
+	ResourcePluralWorkspaceRoleBinding   = "workspacerolebindings"
+	UserReferenceLabel                   = "iam.horizon.io/user-ref"
+	IdentityProviderLabel                = "iam.horizon.io/identity-provider"
+	OriginUIDLabel                       = "iam.horizon.io/origin-uid"
+	PendingConfirmationAnnotation        = "iam.horizon.io/pending-confirmation"
+	GroupReferenceLabel                  = "iam.horizon.io/group-ref"
+	GroupParent                          = "iam.horizon.io/group-parent"
+	RoleReferenceLabel                   = "iam.horizon.io/role-ref"
+	// RoleTemplateLabel marks a role as a template which can be aggregated by the roles of the same scope
+	RoleTemplateLabel = "iam.horizon.io/role-template"
+	// AggregationRoleTemplatesAnnotation holds a JSON array of the names of the role templates
+	// whose rules are aggregated into the annotated role
+	AggregationRoleTemplatesAnnotation = "iam.horizon.io/aggregation-role-templates"
+)
+
 // +genclient
 // +genclient:nonNamespaced
 // +kubebuilder:object:root=true
@@ -13,6 +53,7 @@ import (
 // +kubebuilder:printcolumn:name="Email",type="string",JSONPath=".spec.email"
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.state"
 // +kubebuilder:resource:categories="iam",scope="Cluster"
+// +kubebuilder:subresource:status
 // +kubebuilder:object:root=true
 type User struct {
 	metav1.TypeMeta `json:",inline"`
@@ -21,7 +62,7 @@ type User struct {
 
 	Spec UserSpec `json:"spec"`
 	// +optional
-	Status UserState `json:"status,omitempty"`
+	Status UserStatus `json:"status,omitempty"`
 }
 
 type UserSpec struct {
@@ -44,6 +85,16 @@ type UserSpec struct {
 
 type UserState string
 
+// These are the valid phases of a user.
+const (
+	// UserActive means the user is available.
+	UserActive UserState = "Active"
+	// UserDisabled means the user is disabled.
+	UserDisabled UserState = "Disabled"
+	// UserAuthLimitExceeded means restrict user login.
+	UserAuthLimitExceeded UserState = "AuthLimitExceeded"
+)
+
 type UserStatus struct {
 	// +optional
 	State UserState `json:"state,omitempty"`
@@ -67,3 +118,276 @@ type UserList struct {
 	metav1.ListMeta `json:"metadata,omitempty"`
 	Items           []User `json:"items"`
 }
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+
+// LoginRecord records an authentication attempt of a user
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Provider",type="string",JSONPath=".spec.provider"
+// +kubebuilder:printcolumn:name="From",type="string",JSONPath=".spec.sourceIP"
+// +kubebuilder:printcolumn:name="Success",type="string",JSONPath=".spec.success"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".spec.reason"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:categories="iam",scope="Cluster"
+type LoginRecord struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec LoginRecordSpec `json:"spec"`
+}
+
+type LoginRecordSpec struct {
+	// Which authentication method used, Token/OAuth
+	Type LoginType `json:"type"`
+
+	// Provider of authentication, LDAP/GitHub etc.
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// Source IP of client
+	SourceIP string `json:"sourceIP"`
+
+	// User agent of login attempt
+	// +optional
+	UserAgent string `json:"userAgent,omitempty"`
+
+	// Successful login attempt or not
+	Success bool `json:"success"`
+
+	// States failed login attempt reason
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+type LoginType string
+
+const (
+	// Token means the user logged in with username and password through the token endpoint
+	Token LoginType = "Token"
+	// OAuth means the user logged in through an external identity provider
+	OAuth LoginType = "OAuth"
+)
+
+const (
+	AuthenticatedSuccessfully = "authenticated successfully"
+)
+
+// LoginRecordList contains a list of LoginRecord
+// +kubebuilder:object:root=true
+type LoginRecordList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LoginRecord `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+
+// Group organizes users of a workspace, the workspace is specified with the label horizon.io/workspace
+// and the parent group with the label iam.horizon.io/group-parent
+// +kubebuilder:printcolumn:name="Workspace",type="string",JSONPath=".metadata.labels.horizon\\.io/workspace"
+// +kubebuilder:printcolumn:name="Parent",type="string",JSONPath=".metadata.labels.iam\\.horizon\\.io/group-parent"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:categories="iam",scope="Cluster"
+type Group struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +optional
+	Spec GroupSpec `json:"spec,omitempty"`
+	// +optional
+	Status GroupStatus `json:"status,omitempty"`
+}
+
+type GroupSpec struct {
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+type GroupStatus struct {
+}
+
+// GroupList contains a list of Group
+// +kubebuilder:object:root=true
+type GroupList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Group `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+
+// GroupBinding binds users to a group, the group is also referenced with the label iam.horizon.io/group-ref
+// +kubebuilder:printcolumn:name="Group",type="string",JSONPath=".groupRef.name"
+// +kubebuilder:printcolumn:name="Users",type="string",JSONPath=".users"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:categories="iam",scope="Cluster"
+type GroupBinding struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	GroupRef GroupRef `json:"groupRef"`
+
+	// +optional
+	Users []string `json:"users,omitempty"`
+}
+
+// GroupRef defines the desired relationship of a group
+type GroupRef struct {
+	// +optional
+	APIGroup string `json:"apiGroup,omitempty"`
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	Name string `json:"name"`
+}
+
+// GroupBindingList contains a list of GroupBinding
+// +kubebuilder:object:root=true
+type GroupBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GroupBinding `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+
+// GlobalRole contains rules that represent a set of permissions across the whole platform
+// +kubebuilder:resource:categories="iam",scope="Cluster"
+type GlobalRole struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Rules holds all the PolicyRules for this GlobalRole
+	// +optional
+	Rules []rbacv1.PolicyRule `json:"rules"`
+}
+
+// GlobalRoleList contains a list of GlobalRole
+// +kubebuilder:object:root=true
+type GlobalRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GlobalRole `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+
+// GlobalRoleBinding binds a GlobalRole to users and groups
+// +kubebuilder:printcolumn:name="Role",type="string",JSONPath=".roleRef.name"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:categories="iam",scope="Cluster"
+type GlobalRoleBinding struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Subjects holds references to the objects the role applies to
+	// +optional
+	Subjects []rbacv1.Subject `json:"subjects,omitempty"`
+
+	// RoleRef can only reference a GlobalRole
+	RoleRef rbacv1.RoleRef `json:"roleRef"`
+}
+
+// GlobalRoleBindingList contains a list of GlobalRoleBinding
+// +kubebuilder:object:root=true
+type GlobalRoleBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GlobalRoleBinding `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+
+// WorkspaceRole contains rules that represent a set of permissions in a workspace, the workspace is
+// specified with the label horizon.io/workspace
+// +kubebuilder:printcolumn:name="Workspace",type="string",JSONPath=".metadata.labels.horizon\\.io/workspace"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:categories="iam",scope="Cluster"
+type WorkspaceRole struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Rules holds all the PolicyRules for this WorkspaceRole
+	// +optional
+	Rules []rbacv1.PolicyRule `json:"rules"`
+}
+
+// WorkspaceRoleList contains a list of WorkspaceRole
+// +kubebuilder:object:root=true
+type WorkspaceRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkspaceRole `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+
+// WorkspaceRoleBinding binds a WorkspaceRole of the same workspace to users and groups, the workspace is
+// specified with the label horizon.io/workspace
+// +kubebuilder:printcolumn:name="Workspace",type="string",JSONPath=".metadata.labels.horizon\\.io/workspace"
+// +kubebuilder:printcolumn:name="Role",type="string",JSONPath=".roleRef.name"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:categories="iam",scope="Cluster"
+type WorkspaceRoleBinding struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Subjects holds references to the objects the role applies to
+	// +optional
+	Subjects []rbacv1.Subject `json:"subjects,omitempty"`
+
+	// RoleRef can only reference a WorkspaceRole of the same workspace
+	RoleRef rbacv1.RoleRef `json:"roleRef"`
+}
+
+// WorkspaceRoleBindingList contains a list of WorkspaceRoleBinding
+// +kubebuilder:object:root=true
+type WorkspaceRoleBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkspaceRoleBinding `json:"items"`
+}
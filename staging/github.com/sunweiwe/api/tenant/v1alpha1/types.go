@@ -1,11 +1,13 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func init() {
-	SchemeBuilder.Register(&Workspace{}, &WorkspaceList{})
+	SchemeBuilder.Register(&Workspace{}, &WorkspaceList{}, &WorkspaceTemplate{}, &WorkspaceTemplateList{})
+	SchemeBuilder.Register(&WorkspaceResourceQuota{}, &WorkspaceResourceQuotaList{})
 }
 
 const (
@@ -13,6 +15,17 @@ const (
 	ResourceSingularWorkspace = "workspace"
 	ResourcePluralWorkspace   = "workspaces"
 	WorkspaceLabel            = "horizon.io/workspace"
+	Finalizer                 = "finalizer.tenant.horizon.io"
+
+	ResourceKindWorkspaceTemplate     = "WorkspaceTemplate"
+	ResourceSingularWorkspaceTemplate = "workspacetemplate"
+	ResourcePluralWorkspaceTemplate   = "workspacetemplates"
+	// WorkspaceTemplateLabel marks the workspaces placed on the member clusters by the workspace template
+	WorkspaceTemplateLabel = "tenant.horizon.io/workspace-template"
+
+	ResourceKindWorkspaceResourceQuota     = "WorkspaceResourceQuota"
+	ResourceSingularWorkspaceResourceQuota = "workspaceresourcequota"
+	ResourcePluralWorkspaceResourceQuota   = "workspaceresourcequotas"
 )
 
 // +genclient
@@ -44,4 +57,122 @@ type WorkspaceSpec struct {
 }
 
 type WorkspaceStatus struct {
+	// Namespaces is the number of namespaces labelled with the workspace
+	// +optional
+	Namespaces int `json:"namespaces,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +genclient:nonNamespaced
+
+// WorkspaceTemplate places the workspace of the same name on the member clusters
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:categories="tenant",scope="Cluster"
+type WorkspaceTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              WorkspaceTemplateSpec   `json:"spec,omitempty"`
+	Status            WorkspaceTemplateStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +genclient:nonNamespaced
+
+// WorkspaceTemplateList contains a list of WorkspaceTemplate
+type WorkspaceTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkspaceTemplate `json:"items"`
+}
+
+type WorkspaceTemplateSpec struct {
+	Template  Template  `json:"template,omitempty"`
+	Placement Placement `json:"placement,omitempty"`
+}
+
+// Template is the workspace placed on the member clusters
+type Template struct {
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// +optional
+	Spec WorkspaceSpec `json:"spec,omitempty"`
+}
+
+// Placement selects the member clusters, the workspace is placed on both
+// the listed clusters and the clusters matching the selector
+type Placement struct {
+	// +optional
+	Clusters []GenericClusterReference `json:"clusters,omitempty"`
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+}
+
+type GenericClusterReference struct {
+	Name string `json:"name"`
+}
+
+type PlacementPhase string
+
+const (
+	PlacementPlaced PlacementPhase = "Placed"
+	PlacementFailed PlacementPhase = "Failed"
+)
+
+type WorkspaceTemplateStatus struct {
+	// Clusters is the placement status of the workspace on each selected cluster
+	// +optional
+	Clusters []ClusterPlacementStatus `json:"clusters,omitempty"`
+}
+
+type ClusterPlacementStatus struct {
+	Name string `json:"name"`
+
+	Phase PlacementPhase `json:"phase,omitempty"`
+
+	Message string `json:"message,omitempty"`
+
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +genclient:nonNamespaced
+
+// WorkspaceResourceQuota limits the resources of the workspace labelled on the quota,
+// the hard limits apply to the sum of all the namespaces of the workspace
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:categories="tenant",scope="Cluster"
+type WorkspaceResourceQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              WorkspaceResourceQuotaSpec   `json:"spec,omitempty"`
+	Status            WorkspaceResourceQuotaStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +genclient:nonNamespaced
+
+// WorkspaceResourceQuotaList contains a list of WorkspaceResourceQuota
+type WorkspaceResourceQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkspaceResourceQuota `json:"items"`
+}
+
+type WorkspaceResourceQuotaSpec struct {
+	// Hard is the set of hard limits for each named resource, the same resource names as ResourceQuota
+	// +optional
+	Hard corev1.ResourceList `json:"hard,omitempty"`
+}
+
+type WorkspaceResourceQuotaStatus struct {
+	// Hard is the set of enforced hard limits for each named resource
+	// +optional
+	Hard corev1.ResourceList `json:"hard,omitempty"`
+	// Used is the current observed total usage of the resource in the workspace
+	// +optional
+	Used corev1.ResourceList `json:"used,omitempty"`
 }